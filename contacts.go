@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/models"
+	"github.com/the9x/anneal/internal/storage"
+)
+
+// exportContacts writes every address in the local cache to destPath, as
+// vCard 3.0 for a .vcf file or CSV for a .csv one
+func exportContacts(cfg *config.Config, destPath string) error {
+	var write func(io.Writer, []models.EmailAddress) error
+	switch strings.ToLower(filepath.Ext(destPath)) {
+	case ".vcf", ".vcard":
+		write = writeVCards
+	case ".csv":
+		write = writeContactsCSV
+	default:
+		return fmt.Errorf("unknown format for %s; use a .vcf or .csv file", destPath)
+	}
+
+	store, err := storage.New(cfg.CacheDir)
+	if err != nil {
+		return fmt.Errorf("local cache unavailable: %w", err)
+	}
+	defer store.Close()
+
+	contacts, err := store.GetAllContacts()
+	if err != nil {
+		return err
+	}
+	if len(contacts) == 0 {
+		return fmt.Errorf("no contacts in the cache yet; open some folders first")
+	}
+
+	tmp := destPath + ".part"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", destPath, err)
+	}
+	defer os.Remove(tmp) // no-op once renamed
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	if err := write(w, contacts); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, destPath); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d contacts to %s\n", len(contacts), destPath)
+	return nil
+}
+
+// writeContactsCSV writes contacts as CSV with a Name,Email header
+func writeContactsCSV(w io.Writer, contacts []models.EmailAddress) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Name", "Email"})
+	for _, c := range contacts {
+		cw.Write([]string{c.Name, c.Email})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeVCards writes each contact as a vCard 3.0 entry
+func writeVCards(w io.Writer, contacts []models.EmailAddress) error {
+	for _, c := range contacts {
+		name := c.Name
+		if name == "" {
+			name = c.Email
+		}
+		// N is required in 3.0. "Doe, Jane" gives the family name first;
+		// otherwise it's taken to be the last word.
+		var family, given string
+		if last, first, ok := strings.Cut(c.Name, ","); ok {
+			family, given = strings.TrimSpace(last), strings.TrimSpace(first)
+		} else if words := strings.Fields(c.Name); len(words) > 0 {
+			family = words[len(words)-1]
+			given = strings.Join(words[:len(words)-1], " ")
+		}
+
+		lines := []string{
+			"BEGIN:VCARD",
+			"VERSION:3.0",
+			"FN:" + vcardEscape(name),
+			"N:" + vcardEscape(family) + ";" + vcardEscape(given) + ";;;",
+			"EMAIL;TYPE=INTERNET:" + vcardEscape(c.Email),
+			"END:VCARD",
+		}
+		for _, line := range lines {
+			if _, err := io.WriteString(w, foldVCardLine(line)+"\r\n"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// vcardEscape escapes the characters vCard treats as separators
+func vcardEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// foldVCardLine breaks a line longer than 75 bytes into continuation lines
+// starting with a space, without splitting a character
+func foldVCardLine(line string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		n := len(string(r))
+		if width+n > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	return b.String()
+}
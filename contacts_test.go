@@ -0,0 +1,108 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/the9x/anneal/internal/models"
+)
+
+func TestWriteVCards(t *testing.T) {
+	contacts := []models.EmailAddress{
+		{Name: "Doe, Jane", Email: "jane@example.com"},
+		{Name: "Ann; B\\C", Email: "ann@example.com"},
+		{Email: "noname@example.com"},
+	}
+	want := "BEGIN:VCARD\r\n" +
+		"VERSION:3.0\r\n" +
+		`FN:Doe\, Jane` + "\r\n" +
+		"N:Doe;Jane;;;\r\n" +
+		"EMAIL;TYPE=INTERNET:jane@example.com\r\n" +
+		"END:VCARD\r\n" +
+		"BEGIN:VCARD\r\n" +
+		"VERSION:3.0\r\n" +
+		`FN:Ann\; B\\C` + "\r\n" +
+		`N:B\\C;Ann\;;;;` + "\r\n" +
+		"EMAIL;TYPE=INTERNET:ann@example.com\r\n" +
+		"END:VCARD\r\n" +
+		"BEGIN:VCARD\r\n" +
+		"VERSION:3.0\r\n" +
+		"FN:noname@example.com\r\n" +
+		"N:;;;;\r\n" +
+		"EMAIL;TYPE=INTERNET:noname@example.com\r\n" +
+		"END:VCARD\r\n"
+
+	var b strings.Builder
+	if err := writeVCards(&b, contacts); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != want {
+		t.Errorf("got\n%q\nwant\n%q", b.String(), want)
+	}
+}
+
+func TestVcardEscape(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"a,b", `a\,b`},
+		{"a;b", `a\;b`},
+		{`a\b`, `a\\b`},
+		{`\,`, `\\\,`},
+		{"two\r\nlines\n", `two\nlines\n`},
+	}
+	for _, tt := range tests {
+		if got := vcardEscape(tt.in); got != tt.want {
+			t.Errorf("vcardEscape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFoldVCardLine(t *testing.T) {
+	short := "FN:" + strings.Repeat("a", 72)
+	if got := foldVCardLine(short); got != short {
+		t.Errorf("a 75-byte line was folded: %q", got)
+	}
+
+	long := "FN:" + strings.Repeat("a", 80)
+	want := "FN:" + strings.Repeat("a", 72) + "\r\n " + strings.Repeat("a", 8)
+	if got := foldVCardLine(long); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// "é" is two bytes; the 37th would end at byte 77, so it starts the
+	// continuation line rather than being split
+	multibyte := "FN:" + strings.Repeat("é", 40)
+	want = "FN:" + strings.Repeat("é", 36) + "\r\n " + strings.Repeat("é", 4)
+	if got := foldVCardLine(multibyte); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	for _, line := range strings.Split(foldVCardLine(multibyte), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("folded line is %d bytes: %q", len(line), line)
+		}
+	}
+}
+
+func TestWriteContactsCSV(t *testing.T) {
+	contacts := []models.EmailAddress{
+		{Name: "Doe, Jane", Email: "jane@example.com"},
+		{Name: `Say "hi"`, Email: "hi@example.com"},
+		{Name: "Ann; B\\C", Email: "ann@example.com"},
+		{Email: "noname@example.com"},
+	}
+	want := "Name,Email\n" +
+		"\"Doe, Jane\",jane@example.com\n" +
+		"\"Say \"\"hi\"\"\",hi@example.com\n" +
+		"Ann; B\\C,ann@example.com\n" +
+		",noname@example.com\n"
+
+	var b strings.Builder
+	if err := writeContactsCSV(&b, contacts); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != want {
+		t.Errorf("got\n%q\nwant\n%q", b.String(), want)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/jmap"
+	"github.com/the9x/anneal/internal/storage"
+)
+
+// doctorReport prints check results and counts the failures
+type doctorReport struct {
+	failed int
+}
+
+func (r *doctorReport) pass(format string, args ...any) {
+	fmt.Printf("  ✓ %s\n", fmt.Sprintf(format, args...))
+}
+
+func (r *doctorReport) warn(format string, args ...any) {
+	fmt.Printf("  ! %s\n", fmt.Sprintf(format, args...))
+}
+
+func (r *doctorReport) skip(format string, args ...any) {
+	fmt.Printf("  - %s\n", fmt.Sprintf(format, args...))
+}
+
+func (r *doctorReport) fail(format string, args ...any) {
+	fmt.Printf("  ✗ %s\n", fmt.Sprintf(format, args...))
+	r.failed++
+}
+
+// doctor checks each account's token and connection and the local cache
+// without starting the interface. It returns an error if any check failed.
+func doctor(cfg *config.Config) error {
+	report := &doctorReport{}
+
+	if len(cfg.Accounts) == 0 {
+		fmt.Println("Accounts")
+		report.fail("no account configured; run anneal to set one up")
+	}
+	for _, account := range cfg.Accounts {
+		fmt.Printf("%s (%s)\n", account.Name, account.Email)
+		checkAccount(cfg, account.Email, report)
+		fmt.Println()
+	}
+
+	fmt.Println("Local cache")
+	checkCache(cfg, report)
+	fmt.Println()
+
+	if report.failed > 0 {
+		return fmt.Errorf("%d check(s) failed", report.failed)
+	}
+	fmt.Println("All checks passed.")
+	return nil
+}
+
+// checkAccount verifies the token is stored and signs in with it
+func checkAccount(cfg *config.Config, email string, report *doctorReport) {
+	store := "keyring"
+	if cfg.TokenStore == config.TokenStoreFile {
+		store = "token file"
+	}
+
+	token, err := cfg.GetToken(email)
+	switch {
+	case errors.Is(err, config.ErrTokenNotFound):
+		report.fail("no token in the %s", store)
+		return
+	case config.IsKeyringUnavailable(err):
+		report.fail("keyring unavailable: %v (set token_store: file to use a file)", err)
+		return
+	case err != nil:
+		report.fail("can't read token: %v", err)
+		return
+	}
+	report.pass("token found in the %s", store)
+
+	client, err := jmap.New(email, token)
+	switch {
+	case errors.Is(err, jmap.ErrNoMailAccount):
+		report.pass("signed in")
+		report.fail("token has no mail access; create one with Mail enabled")
+		return
+	case err != nil:
+		report.fail("sign in failed: %v", err)
+		return
+	}
+	report.pass("signed in")
+	report.pass("mail access")
+
+	if client.SupportsSubmission() {
+		report.pass("sending allowed")
+	} else {
+		report.warn("token can't send mail; reading still works")
+	}
+}
+
+// checkCache opens the cache database, which also runs its migrations,
+// and makes sure both it and the attachment cache can be written. With
+// the cache off nothing is opened, so no cache files are created.
+func checkCache(cfg *config.Config, report *doctorReport) {
+	if cfg.CacheDisabled() {
+		report.skip("cache checks skipped; the cache is off")
+		return
+	}
+
+	store, err := storage.New(cfg.CacheDir)
+	if err != nil {
+		report.fail("cache database: %v", err)
+	} else {
+		if err := store.CheckWritable(); err != nil {
+			report.fail("cache database is read-only: %v", err)
+		} else {
+			report.pass("cache database writable")
+		}
+		store.Close()
+	}
+
+	if _, err := storage.NewBlobCache(cfg.CacheDir, 0); err != nil {
+		report.fail("attachment cache: %v", err)
+	} else {
+		report.pass("attachment cache available")
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/the9x/anneal/internal/config"
+)
+
+func TestCheckCacheSkippedWhenOff(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.NoCache = true
+
+	report := &doctorReport{}
+	checkCache(cfg, report)
+	if report.failed != 0 {
+		t.Errorf("%d cache checks failed with the cache off", report.failed)
+	}
+	entries, err := os.ReadDir(cfg.CacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Errorf("doctor created %s with the cache off", e.Name())
+	}
+}
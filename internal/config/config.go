@@ -4,9 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/the9x/anneal/internal/models"
-	"github.com/zalando/go-keyring"
 	"gopkg.in/yaml.v3"
 )
 
@@ -24,8 +24,240 @@ type Config struct {
 	PreviewPane bool             `yaml:"preview_pane"`
 	Threading   bool             `yaml:"threading"`
 	PageSize    int              `yaml:"page_size"`
+
+	// PriorityInbox splits the inbox into Focused (people you've written
+	// to) and Other sections
+	PriorityInbox bool `yaml:"priority_inbox"`
+
+	// PrioritySort moves mail the sender marked high priority (X-Priority
+	// or Importance) to the top of the list. Message lists are fetched
+	// with headers to know it, which makes them larger.
+	PrioritySort bool `yaml:"priority_sort"`
+
+	// TokenStore selects where API tokens live: "keyring" (default) uses
+	// the system keyring, "file" uses a 0600 plaintext file in the config
+	// directory for machines without a secret service.
+	TokenStore string `yaml:"token_store,omitempty"`
+
+	// MaxContentWidth caps the width of the message list and reader so
+	// lines stay readable on wide terminals. 0 removes the cap.
+	MaxContentWidth int `yaml:"max_content_width"`
+
+	// Mouse turns on clicking and scrolling with the mouse. Off by default,
+	// since it takes over the terminal's own text selection.
+	Mouse bool `yaml:"mouse"`
+
+	// ScrollMemory is how long (in minutes) the reader remembers the scroll
+	// position of an email after leaving it. 0 disables the memory.
+	ScrollMemory int `yaml:"scroll_memory"`
+
+	// AdvanceAfterAction decides where the selection goes after archiving
+	// or deleting: "next" (default), "previous" or "stay". Either way the
+	// list is shown with that conversation selected, not opened.
+	AdvanceAfterAction string `yaml:"advance_after_action"`
+
+	// ShowSize adds the message size to the reader header
+	ShowSize bool `yaml:"show_size"`
+
+	// ShortAddresses shows only names in the reader header, without the
+	// addresses. Toggled with w in the reader and remembered.
+	ShortAddresses bool `yaml:"short_addresses"`
+
+	// CollapseOwnReplies shows your own messages in a conversation as
+	// one-line "you replied" entries until expanded
+	CollapseOwnReplies bool `yaml:"collapse_own_replies"`
+
+	// DetectOTP surfaces one-time codes from verification emails in the
+	// reader. Off by default since it scans message bodies for codes.
+	DetectOTP bool `yaml:"detect_otp"`
+
+	// PrefetchCount is how many of the top threads have their newest body
+	// fetched in the background after a folder loads. 0 disables prefetch.
+	PrefetchCount int `yaml:"prefetch_count"`
+
+	// SyncBatchSize is how many emails a background sync fetches per
+	// request, so large folders don't come back in one huge response
+	SyncBatchSize int `yaml:"sync_batch_size"`
+
+	// SyncConcurrency is how many of those requests run at once. It
+	// applies within the sync of one folder; folders sync one at a time.
+	SyncConcurrency int `yaml:"sync_concurrency"`
+
+	// BlobCacheMB caps the on-disk cache of downloaded attachments, in
+	// megabytes. The least recently opened files are evicted first.
+	BlobCacheMB int `yaml:"blob_cache_mb"`
+
+	// NoCache turns off the local cache of mail and attachments, so every
+	// folder and message is fetched from the server and nothing is written
+	// to disk. See CacheDisabled, which also covers --no-cache.
+	NoCache bool `yaml:"no_cache"`
+
+	// NoCacheFlag is set by --no-cache for this run only and never saved
+	NoCacheFlag bool `yaml:"-"`
+
+	// salvaged is the file as read when it had to be salvaged, backed up
+	// again before the first save replaces it with the settings that could
+	// be read
+	salvaged []byte
+
+	// CacheDir holds the cache database and attachment cache in place of
+	// the data directory, for a separate disk, a RAM disk or one cache per
+	// profile. $ANNEAL_CACHE_DIR overrides it.
+	CacheDir string `yaml:"cache_dir,omitempty"`
+
+	// PipeCommand is a shell command the reader pipes the message body to
+	// with "!", showing its output in place of the body. The subject and
+	// sender are available as $ANNEAL_SUBJECT and $ANNEAL_FROM.
+	PipeCommand string `yaml:"pipe_command"`
+
+	// PreviewLength is how many characters of preview the conversation
+	// view shows under the selected message. 0 shows all of it.
+	PreviewLength int `yaml:"preview_length"`
+
+	// PreviewSource is where previews come from: "server" (default) or
+	// "body", built from the cached body without quotes or signature
+	PreviewSource string `yaml:"preview_source"`
+
+	// ExportFormat is how exported conversations are written: "markdown"
+	// (default) or "text"
+	ExportFormat string `yaml:"export_format"`
+
+	// ExportQuotes keeps quoted text in exported conversations. Off by
+	// default since the quoted messages are exported in full anyway.
+	ExportQuotes bool `yaml:"export_quotes"`
+
+	// ExportDir is where exported conversations are saved. When empty
+	// they're copied to the clipboard instead.
+	ExportDir string `yaml:"export_dir"`
+
+	// ReplyAllConfirm asks before replying to all when the original had
+	// more than this many recipients. 0 never asks.
+	ReplyAllConfirm int `yaml:"reply_all_confirm"`
+
+	// ConfirmEmptySend asks before sending a message with no subject or
+	// no body
+	ConfirmEmptySend bool `yaml:"confirm_empty_send"`
+
+	// DraftAutosave is how often, in seconds, an open compose is saved to
+	// Drafts. 0 turns autosave off.
+	DraftAutosave int `yaml:"draft_autosave"`
+
+	// MarkReadDelay is how long, in seconds, an email has to stay open
+	// before it's marked read. 0 marks it read as soon as it opens.
+	MarkReadDelay int `yaml:"mark_read_delay"`
+
+	// MarkThreadReadOnOpen marks every message in a conversation read when
+	// any one of them is opened, rather than just that message
+	MarkThreadReadOnOpen bool `yaml:"mark_thread_read_on_open"`
+
+	// MarkJunkRead marks mail arriving in the Junk folder read during sync
+	// and leaves it out of the new-mail markers
+	MarkJunkRead bool `yaml:"mark_junk_read"`
+
+	// Rules file newly arrived mail during background sync
+	Rules []Rule `yaml:"rules,omitempty"`
+
+	// TrustedSenders are addresses whose mail loads remote images when
+	// opened in the browser; for everyone else they're blocked until
+	// allowed
+	TrustedSenders []string `yaml:"trusted_senders,omitempty"`
+
+	// ThreadEnterBehavior decides what enter does on a conversation in the
+	// message list: "expand" (default) shows the conversation, while
+	// "open-latest" opens its newest email and leaves expanding to space.
+	ThreadEnterBehavior string `yaml:"thread_enter_behavior"`
+
+	// AutoOpenUnread opens unread mail when a folder is entered: "single"
+	// opens the only unread conversation if there's exactly one, "first"
+	// always opens the top unread one. Empty (default) or "off" opens
+	// nothing.
+	AutoOpenUnread string `yaml:"auto_open_unread"`
+
+	// ListColumns lays out the message list's columns
+	ListColumns ListColumns `yaml:"list_columns"`
+
+	// CompactHeader squeezes the reader header into a line or two:
+	// "auto" (default) on short terminals, "always" or "never"
+	CompactHeader string `yaml:"compact_header"`
+
+	// ReplyStyle places replies above the quoted message, "top" (default),
+	// or below it with the signature last, "bottom"
+	ReplyStyle string `yaml:"reply_style"`
+
+	// Language is the two letter code of the interface language, such as
+	// "de". Empty (default) follows LANG; unknown languages show English.
+	Language string `yaml:"language,omitempty"`
 }
 
+// ListColumns sets how the message list divides its width and which
+// optional columns it shows. Settings out of range fall back to the
+// defaults.
+type ListColumns struct {
+	// FromPercent is the share of the width left after the fixed columns
+	// given to the sender, 10 to 90; the subject gets the rest
+	FromPercent int `yaml:"from_percent"`
+
+	// FromMin and FromMax bound the sender column's width
+	FromMin int `yaml:"from_min"`
+	FromMax int `yaml:"from_max"`
+
+	// DateFormat is a Go time layout such as "2006-01-02 15:04" for the
+	// date column. Empty shows the time today and the date before.
+	DateFormat string `yaml:"date_format,omitempty"`
+
+	// Size adds a column with each conversation's size
+	Size bool `yaml:"size"`
+
+	// Flags shows the priority, follow-up and attachment markers before
+	// the subject
+	Flags bool `yaml:"flags"`
+}
+
+// Values for AdvanceAfterAction
+const (
+	AdvanceNext     = "next"
+	AdvancePrevious = "previous"
+	AdvanceStay     = "stay"
+)
+
+// Values for ThreadEnterBehavior
+const (
+	ThreadEnterExpand     = "expand"
+	ThreadEnterOpenLatest = "open-latest"
+)
+
+// Values for CompactHeader
+const (
+	CompactHeaderAuto   = "auto"
+	CompactHeaderAlways = "always"
+	CompactHeaderNever  = "never"
+)
+
+// Values for ReplyStyle
+const (
+	ReplyStyleTop    = "top"
+	ReplyStyleBottom = "bottom"
+)
+
+// Values for AutoOpenUnread
+const (
+	AutoOpenOff    = "off"
+	AutoOpenSingle = "single"
+	AutoOpenFirst  = "first"
+)
+
+// Values for ExportFormat
+const (
+	ExportFormatMarkdown = "markdown"
+	ExportFormatText     = "text"
+)
+
+// Values for PreviewSource
+const (
+	PreviewSourceServer = "server"
+	PreviewSourceBody   = "body"
+)
+
 // DefaultConfig returns a configuration with sensible defaults
 func DefaultConfig() *Config {
 	return &Config{
@@ -34,6 +266,33 @@ func DefaultConfig() *Config {
 		PreviewPane: true,
 		Threading:   true,
 		PageSize:    50,
+
+		MaxContentWidth: 100,
+		ScrollMemory:    30,
+		PrefetchCount:   10,
+		SyncBatchSize:   50,
+		SyncConcurrency: 4,
+		BlobCacheMB:     200,
+		PreviewLength:   60,
+		ReplyAllConfirm: 10,
+		DraftAutosave:   30,
+
+		AdvanceAfterAction:  AdvanceNext,
+		ThreadEnterBehavior: ThreadEnterExpand,
+		CollapseOwnReplies:  true,
+		PreviewSource:       PreviewSourceServer,
+		ExportFormat:        ExportFormatMarkdown,
+		ReplyStyle:          ReplyStyleTop,
+		CompactHeader:       CompactHeaderAuto,
+		ConfirmEmptySend:    true,
+		MarkJunkRead:        true,
+
+		ListColumns: ListColumns{
+			FromPercent: 25,
+			FromMin:     12,
+			FromMax:     24,
+			Flags:       true,
+		},
 	}
 }
 
@@ -46,7 +305,8 @@ func ConfigPath() (string, error) {
 	return filepath.Join(home, configDir, configFile), nil
 }
 
-// Load reads the configuration from disk
+// Load reads the configuration from disk. If the file doesn't parse, the
+// config is salvaged and returned with a *RecoveryError.
 func Load() (*Config, error) {
 	path, err := ConfigPath()
 	if err != nil {
@@ -63,13 +323,15 @@ func Load() (*Config, error) {
 
 	cfg := DefaultConfig()
 	if err := yaml.Unmarshal(data, cfg); err != nil {
-		return nil, err
+		// A typo shouldn't lock anyone out; start with what can be read
+		return recoverConfig(path, data, err)
 	}
 
 	return cfg, nil
 }
 
-// Save writes the configuration to disk
+// Save writes the configuration to disk. A salvaged config is only saved
+// once the original file is backed up.
 func (c *Config) Save() error {
 	path, err := ConfigPath()
 	if err != nil {
@@ -80,6 +342,13 @@ func (c *Config) Save() error {
 		return err
 	}
 
+	if c.salvaged != nil {
+		if err := os.WriteFile(backupPath(path), c.salvaged, 0600); err != nil {
+			return fmt.Errorf("config was only partly read and couldn't be backed up, so it wasn't saved: %w", err)
+		}
+		c.salvaged = nil
+	}
+
 	data, err := yaml.Marshal(c)
 	if err != nil {
 		return err
@@ -88,19 +357,10 @@ func (c *Config) Save() error {
 	return os.WriteFile(path, data, 0600)
 }
 
-// GetToken retrieves the API token for an account from the system keyring
-func GetToken(email string) (string, error) {
-	return keyring.Get(serviceName, email)
-}
-
-// SetToken stores the API token for an account in the system keyring
-func SetToken(email, token string) error {
-	return keyring.Set(serviceName, email, token)
-}
-
-// DeleteToken removes the API token for an account from the system keyring
-func DeleteToken(email string) error {
-	return keyring.Delete(serviceName, email)
+// CacheDisabled returns true if the local cache is off, in the config or
+// for this run
+func (c *Config) CacheDisabled() bool {
+	return c.NoCache || c.NoCacheFlag
 }
 
 // DefaultAccount returns the default account or the first one
@@ -116,6 +376,44 @@ func (c *Config) DefaultAccount() *models.Account {
 	return nil
 }
 
+// FindAccount returns the account with the given email address, or nil
+func (c *Config) FindAccount(email string) *models.Account {
+	for i := range c.Accounts {
+		if strings.EqualFold(c.Accounts[i].Email, email) {
+			return &c.Accounts[i]
+		}
+	}
+	return nil
+}
+
+// AccountAt returns the account at position i in the order they're
+// listed in the config, or nil when there are fewer. Account switching
+// numbers accounts in this order.
+func (c *Config) AccountAt(i int) *models.Account {
+	if i < 0 || i >= len(c.Accounts) {
+		return nil
+	}
+	return &c.Accounts[i]
+}
+
+// RemoveAccount drops an account from the configuration, making the first
+// remaining account the default if it was. It reports whether the account
+// was found.
+func (c *Config) RemoveAccount(email string) bool {
+	for i := range c.Accounts {
+		if !strings.EqualFold(c.Accounts[i].Email, email) {
+			continue
+		}
+		wasDefault := c.Accounts[i].Default
+		c.Accounts = append(c.Accounts[:i], c.Accounts[i+1:]...)
+		if wasDefault && len(c.Accounts) > 0 {
+			c.Accounts[0].Default = true
+		}
+		return true
+	}
+	return false
+}
+
 // AddAccount adds a new account to the configuration
 func (c *Config) AddAccount(name, email string, isDefault bool) error {
 	// Check for duplicate
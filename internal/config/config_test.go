@@ -0,0 +1,24 @@
+package config
+
+import "testing"
+
+func TestNoCacheFlagIsNotSaved(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := DefaultConfig()
+	cfg.NoCacheFlag = true
+	if !cfg.CacheDisabled() {
+		t.Fatal("CacheDisabled is false with --no-cache")
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.NoCache || loaded.CacheDisabled() {
+		t.Error("--no-cache was saved to the config")
+	}
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/the9x/anneal/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// RecoveryError reports a config file that couldn't be read in full. Load
+// returns it alongside a usable config holding the defaults plus every
+// setting that could be salvaged.
+type RecoveryError struct {
+	Backup  string   // copy of the file as it was, empty if it couldn't be saved
+	Skipped []string // top-level keys that were left at their defaults
+	Err     error    // the original parse error
+}
+
+func (e *RecoveryError) Error() string {
+	msg := fmt.Sprintf("config has errors (%v)", e.Err)
+	if len(e.Skipped) > 0 {
+		msg += "; using defaults for " + strings.Join(e.Skipped, ", ")
+	}
+	if e.Backup != "" {
+		msg += "; the original is saved as " + e.Backup
+	}
+	return msg
+}
+
+func (e *RecoveryError) Unwrap() error {
+	return e.Err
+}
+
+// recoverConfig salvages what it can from a config file that failed to
+// parse. Each top-level key is parsed on its own, so a typo only costs the
+// setting it's in; accounts are salvaged one by one.
+func recoverConfig(path string, data []byte, parseErr error) (*Config, error) {
+	rerr := &RecoveryError{Err: parseErr}
+
+	// Keep the original in case a later save overwrites it. Save backs it
+	// up again first, in case this copy failed or is gone by then.
+	backup := backupPath(path)
+	if err := os.WriteFile(backup, data, 0600); err == nil {
+		rerr.Backup = backup
+	}
+
+	cfg := DefaultConfig()
+	cfg.salvaged = data
+	for _, block := range topLevelBlocks(string(data)) {
+		if err := yaml.Unmarshal([]byte(block.text), cfg); err == nil {
+			continue
+		}
+		if block.key == "accounts" {
+			cfg.Accounts = salvageAccounts(block.text)
+			if len(cfg.Accounts) > 0 {
+				continue
+			}
+		}
+		rerr.Skipped = append(rerr.Skipped, block.key)
+	}
+
+	return cfg, rerr
+}
+
+// backupPath is where the original of a salvaged config is kept
+func backupPath(path string) string {
+	return path + ".bak"
+}
+
+// yamlBlock is a top-level key with its nested lines
+type yamlBlock struct {
+	key  string
+	text string
+}
+
+// topLevelBlocks splits a YAML document at each unindented line, so a
+// stray line ends up in a block of its own
+func topLevelBlocks(doc string) []yamlBlock {
+	var blocks []yamlBlock
+	for _, line := range strings.Split(doc, "\n") {
+		if line != "" && !strings.ContainsRune(" \t#-", rune(line[0])) {
+			key, _, _ := strings.Cut(line, ":")
+			blocks = append(blocks, yamlBlock{key: strings.TrimSpace(key)})
+		}
+		if len(blocks) > 0 {
+			blocks[len(blocks)-1].text += line + "\n"
+		}
+	}
+	return blocks
+}
+
+// salvageAccounts parses the accounts list one entry at a time, keeping
+// every entry that's valid on its own
+func salvageAccounts(block string) []models.Account {
+	lines := strings.Split(block, "\n")
+
+	// Entries start at the least indented "- "
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " ")
+		if strings.HasPrefix(trimmed, "- ") {
+			if n := len(line) - len(trimmed); indent < 0 || n < indent {
+				indent = n
+			}
+		}
+	}
+	if indent < 0 {
+		return nil
+	}
+
+	var entries []string
+	for _, line := range lines[1:] {
+		if len(line) > indent && strings.HasPrefix(line[indent:], "- ") && strings.TrimSpace(line[:indent]) == "" {
+			entries = append(entries, "")
+		}
+		if len(entries) > 0 {
+			entries[len(entries)-1] += line + "\n"
+		}
+	}
+
+	var accounts []models.Account
+	for _, entry := range entries {
+		var list []models.Account
+		if err := yaml.Unmarshal([]byte(entry), &list); err != nil {
+			continue
+		}
+		for _, acc := range list {
+			if acc.Email != "" {
+				accounts = append(accounts, acc)
+			}
+		}
+	}
+	return accounts
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// brokenConfig has a valid account and a theme line with bad indentation
+const brokenConfig = `accounts:
+  - name: Work
+    email: me@example.com
+theme: dark
+  editor: vim
+`
+
+func writeBrokenConfig(t *testing.T) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	path, err := ConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(brokenConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSalvagedConfigBackedUpBeforeSave(t *testing.T) {
+	path := writeBrokenConfig(t)
+	cfg, err := Load()
+	var recovered *RecoveryError
+	if !errors.As(err, &recovered) {
+		t.Fatalf("Load error = %v, want a RecoveryError", err)
+	}
+
+	// The copy made on load is gone by the time a setting is saved
+	os.Remove(path + ".bak")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := os.ReadFile(path + ".bak")
+	if err != nil || string(backup) != brokenConfig {
+		t.Fatalf("original not backed up before saving: %q, %v", backup, err)
+	}
+
+	// Later saves keep that backup rather than copying the partial config
+	cfg.Theme = "light"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if backup, _ := os.ReadFile(path + ".bak"); string(backup) != brokenConfig {
+		t.Errorf("backup replaced by a later save: %q", backup)
+	}
+}
+
+func TestSalvagedConfigNotSavedWithoutBackup(t *testing.T) {
+	path := writeBrokenConfig(t)
+	cfg, _ := Load()
+
+	// A directory in the way makes the backup fail
+	os.Remove(path + ".bak")
+	if err := os.Mkdir(path+".bak", 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Save(); err == nil {
+		t.Fatal("saved a partly read config without backing it up")
+	}
+	if data, _ := os.ReadFile(path); string(data) != brokenConfig {
+		t.Errorf("original overwritten: %q", data)
+	}
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/the9x/anneal/internal/models"
+)
+
+// Rule files newly arrived mail during background sync. Every condition
+// that's set must match, as a case-insensitive substring; a rule with no
+// conditions never matches. The first matching rule wins.
+type Rule struct {
+	Name    string `yaml:"name,omitempty"`
+	From    string `yaml:"from,omitempty"`
+	To      string `yaml:"to,omitempty"` // matched against To and Cc
+	Subject string `yaml:"subject,omitempty"`
+
+	// Exact makes From and To match only an address equal to them,
+	// compared without case, rather than any name or address containing
+	// them
+	Exact bool `yaml:"exact,omitempty"`
+
+	// Blocked marks the rules added by blocking a sender, which the
+	// blocked senders list shows and unblocking removes
+	Blocked bool `yaml:"blocked,omitempty"`
+
+	// Actions
+	MoveTo   string `yaml:"move_to,omitempty"` // mailbox name or role
+	Archive  bool   `yaml:"archive,omitempty"`
+	MarkRead bool   `yaml:"mark_read,omitempty"`
+	Flag     bool   `yaml:"flag,omitempty"`
+}
+
+// Matches returns true if the email meets all of the rule's conditions
+func (r Rule) Matches(e *models.Email) bool {
+	if r.From == "" && r.To == "" && r.Subject == "" {
+		return false
+	}
+	if r.From != "" && !addressesMatch(e.From, r.From, r.Exact) {
+		return false
+	}
+	if r.To != "" && !addressesMatch(e.To, r.To, r.Exact) && !addressesMatch(e.CC, r.To, r.Exact) {
+		return false
+	}
+	if r.Subject != "" && !containsFold(e.Subject, r.Subject) {
+		return false
+	}
+	return true
+}
+
+// Label names the rule for the log, falling back to its conditions
+func (r Rule) Label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	var conds []string
+	if r.From != "" {
+		conds = append(conds, "from "+r.From)
+	}
+	if r.To != "" {
+		conds = append(conds, "to "+r.To)
+	}
+	if r.Subject != "" {
+		conds = append(conds, fmt.Sprintf("subject %q", r.Subject))
+	}
+	return strings.Join(conds, ", ")
+}
+
+// addressesMatch returns true if any address's email equals s, when
+// exact, or else if its name or email contains s
+func addressesMatch(addrs []models.EmailAddress, s string, exact bool) bool {
+	for _, addr := range addrs {
+		if exact {
+			if strings.EqualFold(strings.TrimSpace(addr.Email), strings.TrimSpace(s)) {
+				return true
+			}
+		} else if containsFold(addr.Email, s) || containsFold(addr.Name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// BlockedRuleName names the rules added by blocking a sender
+const BlockedRuleName = "blocked sender"
+
+// BlockedSenders returns the blocked addresses, oldest block first
+func (c *Config) BlockedSenders() []string {
+	var addrs []string
+	for _, r := range c.Rules {
+		if r.Blocked {
+			addrs = append(addrs, r.From)
+		}
+	}
+	return addrs
+}
+
+// BlockSender adds a rule moving future mail from addr to the mailbox with
+// the given role. Returns false if the address is already blocked.
+func (c *Config) BlockSender(addr, role string) bool {
+	if c.IsBlockedSender(addr) {
+		return false
+	}
+	// Blocks go first so an earlier rule can't file the mail elsewhere
+	addr = strings.ToLower(strings.TrimSpace(addr))
+	c.Rules = append([]Rule{{Name: BlockedRuleName, From: addr, Exact: true, Blocked: true, MoveTo: role}}, c.Rules...)
+	return true
+}
+
+// IsBlockedSender returns true if addr is blocked, comparing whole
+// addresses without case
+func (c *Config) IsBlockedSender(addr string) bool {
+	return c.blockIndex(addr) >= 0
+}
+
+// blockIndex returns the index of the rule blocking addr, or -1
+func (c *Config) blockIndex(addr string) int {
+	addr = strings.TrimSpace(addr)
+	for i, r := range c.Rules {
+		if r.Blocked && strings.EqualFold(strings.TrimSpace(r.From), addr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// UnblockSender removes the block on addr, returning false if it wasn't
+// blocked
+func (c *Config) UnblockSender(addr string) bool {
+	i := c.blockIndex(addr)
+	if i < 0 {
+		return false
+	}
+	c.Rules = append(c.Rules[:i], c.Rules[i+1:]...)
+	return true
+}
+
+// IsTrustedSender returns true if remote content in mail from addr loads
+// without asking
+func (c *Config) IsTrustedSender(addr string) bool {
+	for _, trusted := range c.TrustedSenders {
+		if strings.EqualFold(trusted, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// TrustSender lets remote content from addr load from now on. Returns
+// false if the sender was already trusted.
+func (c *Config) TrustSender(addr string) bool {
+	if c.IsTrustedSender(addr) {
+		return false
+	}
+	c.TrustedSenders = append(c.TrustedSenders, strings.ToLower(addr))
+	return true
+}
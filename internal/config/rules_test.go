@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/the9x/anneal/internal/models"
+)
+
+func TestBlockedSenderMatchesExactAddress(t *testing.T) {
+	c := &Config{}
+	if !c.BlockSender("A@b.com", "junk") {
+		t.Fatal("BlockSender returned false for a new address")
+	}
+	if c.BlockSender("a@B.com", "junk") {
+		t.Error("BlockSender blocked the same address twice")
+	}
+
+	tests := []struct {
+		from models.EmailAddress
+		want bool
+	}{
+		{models.EmailAddress{Email: "a@b.com"}, true},
+		{models.EmailAddress{Email: "A@B.COM", Name: "Someone"}, true},
+		{models.EmailAddress{Email: "xa@b.com"}, false},
+		{models.EmailAddress{Email: "a@b.com.evil"}, false},
+		{models.EmailAddress{Email: "other@example.com", Name: "a@b.com"}, false},
+	}
+	for _, tt := range tests {
+		e := &models.Email{From: []models.EmailAddress{tt.from}}
+		if got := c.Rules[0].Matches(e); got != tt.want {
+			t.Errorf("block on a@b.com matching %+v = %v, want %v", tt.from, got, tt.want)
+		}
+	}
+
+	if c.UnblockSender("xa@b.com") {
+		t.Error("UnblockSender removed a block on a different address")
+	}
+	if !c.UnblockSender("A@B.com") || c.IsBlockedSender("a@b.com") {
+		t.Error("UnblockSender didn't remove the block")
+	}
+}
+
+func TestBlocksAreMarkedNotNamed(t *testing.T) {
+	c := &Config{Rules: []Rule{{Name: BlockedRuleName, From: "a@b.com", Exact: true, MoveTo: "junk"}}}
+	if c.IsBlockedSender("a@b.com") || c.UnblockSender("a@b.com") {
+		t.Error("a rule the user named like a block was treated as one")
+	}
+	c.BlockSender("c@d.com", "junk")
+	if got := c.BlockedSenders(); len(got) != 1 || got[0] != "c@d.com" {
+		t.Errorf("BlockedSenders = %q, want [c@d.com]", got)
+	}
+}
+
+func TestRuleSubstringMatch(t *testing.T) {
+	r := Rule{From: "@news.example.com"}
+	if !r.Matches(&models.Email{From: []models.EmailAddress{{Email: "daily@news.example.com"}}}) {
+		t.Error("substring rule didn't match")
+	}
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zalando/go-keyring"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	TokenStoreKeyring = "keyring"
+	TokenStoreFile    = "file"
+
+	tokenFile = "tokens.yaml"
+)
+
+// ErrTokenNotFound is returned when no token is stored for an account
+var ErrTokenNotFound = errors.New("token not found")
+
+// KeyringUnavailableError reports that the system keyring backend could not
+// be reached, as opposed to the token simply being missing
+type KeyringUnavailableError struct {
+	Err error
+}
+
+func (e *KeyringUnavailableError) Error() string {
+	return fmt.Sprintf("system keyring unavailable: %v", e.Err)
+}
+
+func (e *KeyringUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// IsKeyringUnavailable returns true if err means the keyring backend is missing
+func IsKeyringUnavailable(err error) bool {
+	var kerr *KeyringUnavailableError
+	return errors.As(err, &kerr)
+}
+
+// TokenFilePath returns the path to the plaintext token file
+func TokenFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, configDir, tokenFile), nil
+}
+
+// GetToken retrieves the API token for an account from the configured store.
+// When the keyring can't be reached, a token kept in the token file is used
+// instead.
+func (c *Config) GetToken(email string) (string, error) {
+	if c.TokenStore == TokenStoreFile {
+		tokens, err := readTokenFile()
+		if err != nil {
+			return "", err
+		}
+		token, ok := tokens[email]
+		if !ok {
+			return "", ErrTokenNotFound
+		}
+		return token, nil
+	}
+
+	token, err := keyring.Get(serviceName, email)
+	if err != nil {
+		err = keyringError(err)
+		if IsKeyringUnavailable(err) {
+			if tokens, ferr := readTokenFile(); ferr == nil && tokens[email] != "" {
+				return tokens[email], nil
+			}
+		}
+		return "", err
+	}
+	return token, nil
+}
+
+// SetToken stores the API token for an account in the configured store
+func (c *Config) SetToken(email, token string) error {
+	if c.TokenStore == TokenStoreFile {
+		tokens, err := readTokenFile()
+		if err != nil {
+			return err
+		}
+		tokens[email] = token
+		return writeTokenFile(tokens)
+	}
+
+	return keyringError(keyring.Set(serviceName, email, token))
+}
+
+// DeleteToken removes the API token for an account from the configured store
+func (c *Config) DeleteToken(email string) error {
+	if c.TokenStore == TokenStoreFile {
+		tokens, err := readTokenFile()
+		if err != nil {
+			return err
+		}
+		if _, ok := tokens[email]; !ok {
+			return ErrTokenNotFound
+		}
+		delete(tokens, email)
+		return writeTokenFile(tokens)
+	}
+
+	return keyringError(keyring.Delete(serviceName, email))
+}
+
+// keyringError maps go-keyring errors onto our own so callers can tell a
+// missing token from a broken keyring backend
+func keyringError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, keyring.ErrNotFound) {
+		return ErrTokenNotFound
+	}
+	return &KeyringUnavailableError{Err: err}
+}
+
+func readTokenFile() (map[string]string, error) {
+	path, err := TokenFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tokens, nil
+		}
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return tokens, nil
+}
+
+func writeTokenFile(tokens map[string]string) error {
+	path, err := TokenFilePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file, so tighten it explicitly
+	return os.Chmod(path, 0600)
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestGetTokenFallsBackToFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	keyring.MockInitWithError(errors.New("no secret service"))
+	t.Cleanup(keyring.MockInit)
+
+	cfg := DefaultConfig()
+	if _, err := cfg.GetToken("ann@example.com"); !IsKeyringUnavailable(err) {
+		t.Fatalf("GetToken with no keyring and no file = %v, want keyring unavailable", err)
+	}
+
+	file := &Config{TokenStore: TokenStoreFile}
+	if err := file.SetToken("ann@example.com", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	token, err := cfg.GetToken("ann@example.com")
+	if err != nil || token != "secret" {
+		t.Errorf("GetToken = %q, %v; want the token from the file", token, err)
+	}
+	if _, err := cfg.GetToken("bob@example.com"); !IsKeyringUnavailable(err) {
+		t.Errorf("GetToken for an account not in the file = %v, want keyring unavailable", err)
+	}
+}
@@ -0,0 +1,114 @@
+package i18n
+
+// german is the German catalog
+var german = map[string]string{
+	// Views
+	"folders":     "Ordner",
+	"messages":    "Nachrichten",
+	"thread":      "Unterhaltung",
+	"email":       "E-Mail",
+	"attachments": "Anhänge",
+	"compose":     "Verfassen",
+
+	// Empty states
+	"← Select a folder":       "← Ordner auswählen",
+	"No messages":             "Keine Nachrichten",
+	"No mailboxes":            "Keine Ordner",
+	"No thread selected":      "Keine Unterhaltung ausgewählt",
+	"No email selected":       "Keine E-Mail ausgewählt",
+	"No compose view":         "Kein Entwurf geöffnet",
+	"Thread no longer loaded": "Unterhaltung nicht mehr geladen",
+
+	// Help
+	"select":            "auswählen",
+	"open":              "öffnen",
+	"quit":              "beenden",
+	"log out":           "abmelden",
+	"help":              "Hilfe",
+	"blocked senders":   "blockierte Absender",
+	"edit":              "bearbeiten",
+	"retry now":         "jetzt erneut senden",
+	"discard":           "verwerfen",
+	"undelete":          "wiederherstellen",
+	"cancel send":       "Senden abbrechen",
+	"reply":             "antworten",
+	"reply all":         "allen antworten",
+	"forward":           "weiterleiten",
+	"archive":           "archivieren",
+	"read-only":         "schreibgeschützt",
+	"focused":           "wichtig",
+	"other":             "sonstige",
+	"filter":            "filtern",
+	"sort by date":      "nach Datum sortieren",
+	"read":              "lesen",
+	"load conversation": "Unterhaltung laden",
+	"collapse replies":  "Antworten einklappen",
+	"expand replies":    "Antworten ausklappen",
+	"export":            "exportieren",
+	"transcript":        "Verlauf",
+	"scroll":            "blättern",
+	"back":              "zurück",
+	"browser":           "Browser",
+	"links":             "Links",
+	"web link":          "Weblink",
+	"resend":            "erneut senden",
+	"next/prev":         "nächste/vorige",
+	"all recipients":    "alle Empfänger",
+	"full header":       "voller Kopf",
+	"text/html":         "Text/HTML",
+	"pipe":              "weiterreichen",
+	"next/prev match":   "nächster/voriger Treffer",
+	"find":              "suchen",
+	"next field":        "nächstes Feld",
+	"attach":            "anhängen",
+	"send":              "senden",
+	"cancel":            "abbrechen",
+	"top/above quote":   "oben/über dem Zitat",
+
+	// Key bindings
+	"up":                          "hoch",
+	"down":                        "runter",
+	"top":                         "Anfang",
+	"bottom":                      "Ende",
+	"open/expand":                 "öffnen/ausklappen",
+	"delete":                      "löschen",
+	"move":                        "verschieben",
+	"snooze":                      "zurückstellen",
+	"star":                        "markieren",
+	"mark unread":                 "als ungelesen markieren",
+	"refresh":                     "aktualisieren",
+	"expand thread":               "Unterhaltung ausklappen",
+	"collapse":                    "einklappen",
+	"pin":                         "anheften",
+	"follow-up":                   "nachverfolgen",
+	"copy code":                   "Code kopieren",
+	"copy web link":               "Weblink kopieren",
+	"resend bounce":               "Unzustellbares erneut senden",
+	"open in pager":               "im Pager öffnen",
+	"pipe to command":             "an Befehl weiterreichen",
+	"mark":                        "auswählen",
+	"undo bulk action":            "Sammelaktion rückgängig",
+	"archive all read":            "alle gelesenen archivieren",
+	"open in browser":             "im Browser öffnen",
+	"focused/other":               "wichtig/sonstige",
+	"move sender":                 "Absender verschieben",
+	"sort by size":                "nach Größe sortieren",
+	"thread grouping":             "Gruppierung",
+	"next match":                  "nächster Treffer",
+	"previous match":              "voriger Treffer",
+	"export conversation":         "Unterhaltung exportieren",
+	"read as transcript":          "als Verlauf lesen",
+	"attachments in folder":       "Anhänge im Ordner",
+	"text/html part":              "Text-/HTML-Teil",
+	"short/full addresses":        "kurze/volle Adressen",
+	"block sender / blocked list": "Absender blockieren / Liste",
+	"folders side by side":        "Ordner nebeneinander",
+	"next message":                "nächste Nachricht",
+	"previous message":            "vorige Nachricht",
+	"switch account":              "Konto wechseln",
+	"account 1":                   "Konto 1",
+	"account 2":                   "Konto 2",
+	"account 3":                   "Konto 3",
+	"account 4":                   "Konto 4",
+	"account 5":                   "Konto 5",
+}
@@ -0,0 +1,54 @@
+// Package i18n translates the interface's fixed strings. The English text
+// is the key, so anything without a translation shows as written.
+package i18n
+
+import (
+	"os"
+	"strings"
+)
+
+// catalogs holds the translations for each language, by its two letter
+// code. English needs none.
+var catalogs = map[string]map[string]string{
+	"de": german,
+}
+
+// current is the catalog in use, nil for English
+var current map[string]string
+
+// SetLanguage picks the language to show. An empty language is taken from
+// LC_ALL, LC_MESSAGES or LANG; one without a catalog falls back to English.
+func SetLanguage(language string) {
+	if language == "" {
+		language = fromEnvironment()
+	}
+	current = catalogs[normalize(language)]
+}
+
+// T returns the translation of an English string, or the string itself
+// when there is none
+func T(key string) string {
+	if s, ok := current[key]; ok {
+		return s
+	}
+	return key
+}
+
+// fromEnvironment returns the language set by the usual locale variables
+func fromEnvironment() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// normalize reduces a locale such as "de_AT.UTF-8" to its language, "de"
+func normalize(locale string) string {
+	locale = strings.ToLower(locale)
+	if i := strings.IndexAny(locale, "_-.@"); i >= 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
@@ -0,0 +1,37 @@
+package i18n
+
+import "testing"
+
+func TestGermanLookup(t *testing.T) {
+	SetLanguage("de_AT.UTF-8")
+	defer SetLanguage("en")
+
+	if got := T("No messages"); got != "Keine Nachrichten" {
+		t.Errorf(`T("No messages") = %q, want "Keine Nachrichten"`, got)
+	}
+	// A string without a German translation shows in English
+	if got := T("not in any catalog"); got != "not in any catalog" {
+		t.Errorf("missing key = %q, want the English text", got)
+	}
+}
+
+func TestEnglishFallback(t *testing.T) {
+	for _, language := range []string{"en", "fr_FR.UTF-8"} {
+		SetLanguage(language)
+		if got := T("No messages"); got != "No messages" {
+			t.Errorf("%s: T(\"No messages\") = %q, want the English text", language, got)
+		}
+	}
+}
+
+func TestLanguageFromEnvironment(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "de_DE.UTF-8")
+	t.Setenv("LANG", "en_US.UTF-8")
+	SetLanguage("")
+	defer SetLanguage("en")
+
+	if got := T("quit"); got != "beenden" {
+		t.Errorf(`T("quit") = %q, want "beenden" from LC_MESSAGES`, got)
+	}
+}
@@ -0,0 +1,88 @@
+package jmap
+
+import (
+	"mime"
+	"regexp"
+	"strings"
+
+	"git.sr.ht/~rockorager/go-jmap/mail"
+	"github.com/the9x/anneal/internal/models"
+)
+
+// wordDecoder decodes RFC 2047 encoded-words such as =?UTF-8?B?...?=
+var wordDecoder = new(mime.WordDecoder)
+
+var (
+	// rfc2231ParamsRe matches a name left as RFC 2231 parameters split
+	// into continuations, such as filename*0*=UTF-8''...; filename*1=...
+	rfc2231ParamsRe = regexp.MustCompile(`(?i)^\s*(file)?name\*\d`)
+
+	// rfc2231ValueRe matches a name left as an RFC 2231 extended value,
+	// charset'language'percent-encoded-text
+	rfc2231ValueRe = regexp.MustCompile(`^[A-Za-z0-9_.:-]+'[A-Za-z-]*'\S+$`)
+)
+
+// convertAddresses converts JMAP addresses to our model, cleaning up what
+// badly behaved senders put in their headers. Entries with neither a name
+// nor an address are dropped.
+func convertAddresses(addrs []*mail.Address) []models.EmailAddress {
+	var result []models.EmailAddress
+	for _, addr := range addrs {
+		if addr == nil {
+			continue
+		}
+		name := decodeName(addr.Name)
+		email := strings.Trim(strings.TrimSpace(addr.Email), "<>")
+		// A bare address sometimes lands in the name
+		if email == "" && strings.Contains(name, "@") && !strings.ContainsAny(name, " <>") {
+			email, name = name, ""
+		}
+		if name == "" && email == "" {
+			continue
+		}
+		result = append(result, models.EmailAddress{Name: name, Email: email})
+	}
+	return result
+}
+
+// decodeName decodes encoded-words the server left in a display name and
+// strips the quotes some mailers leave around it. Words in a charset Go
+// can't decode are kept as they are.
+func decodeName(name string) string {
+	if strings.Contains(name, "=?") {
+		if decoded, err := wordDecoder.DecodeHeader(name); err == nil {
+			name = decoded
+		}
+	}
+	name = strings.TrimSpace(name)
+	if len(name) >= 2 && (name[0] == '"' && name[len(name)-1] == '"' || name[0] == '\'' && name[len(name)-1] == '\'') {
+		name = strings.TrimSpace(name[1 : len(name)-1])
+	}
+	return name
+}
+
+// decodeAttachmentName decodes an attachment name the server passed on
+// still encoded, as RFC 2047 encoded-words or RFC 2231 parameters. Names
+// in a charset Go can't decode are kept as they are.
+func decodeAttachmentName(name string) string {
+	switch {
+	case rfc2231ParamsRe.MatchString(name):
+		if _, params, err := mime.ParseMediaType("attachment; " + name); err == nil {
+			if params["filename"] != "" {
+				return params["filename"]
+			}
+			if params["name"] != "" {
+				return params["name"]
+			}
+		}
+	case rfc2231ValueRe.MatchString(name):
+		if _, params, err := mime.ParseMediaType("attachment; filename*=" + name); err == nil && params["filename"] != "" {
+			return params["filename"]
+		}
+	case strings.Contains(name, "=?"):
+		if decoded, err := wordDecoder.DecodeHeader(name); err == nil {
+			return decoded
+		}
+	}
+	return name
+}
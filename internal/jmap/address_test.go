@@ -0,0 +1,75 @@
+package jmap
+
+import (
+	"testing"
+
+	"git.sr.ht/~rockorager/go-jmap/mail"
+	"github.com/the9x/anneal/internal/models"
+)
+
+func TestDecodeName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"=?UTF-8?B?SsO8cmdlbiBNw7xsbGVy?=", "Jürgen Müller"},
+		{"=?UTF-8?Q?Ren=C3=A9e_Dupont?=", "Renée Dupont"},
+		{"=?ISO-8859-1?Q?Andr=E9?= Pirard", "André Pirard"},
+		{"=?UTF-8?B?5bGx55Sw?= =?UTF-8?B?5aSq6YOO?=", "山田太郎"},
+		{`"Quoted Name"`, "Quoted Name"},
+		{"=?x-unknown?Q?abc?=", "=?x-unknown?Q?abc?="},
+		{"Plain Name", "Plain Name"},
+	}
+	for _, tt := range tests {
+		if got := decodeName(tt.in); got != tt.want {
+			t.Errorf("decodeName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestConvertAddresses(t *testing.T) {
+	got := convertAddresses([]*mail.Address{
+		nil,
+		{Name: "=?UTF-8?Q?Bj=C3=B6rk?=", Email: "bjork@example.com"},
+		{Name: "noreply@example.com"},
+		{Name: "", Email: ""},
+		{Email: " <bare@example.com> "},
+	})
+	want := []models.EmailAddress{
+		{Name: "Björk", Email: "bjork@example.com"},
+		{Email: "noreply@example.com"},
+		{Email: "bare@example.com"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("convertAddresses = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("address %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDecodeAttachmentName(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"2047 B", "=?UTF-8?B?UmVjaG51bmcgTcOkcnoucGRm?=", "Rechnung März.pdf"},
+		{"2047 Q", "=?UTF-8?Q?r=C3=A9sum=C3=A9.docx?=", "résumé.docx"},
+		{"2047 split words", "=?UTF-8?B?5aWR57SE?= =?UTF-8?B?5pu4LnBkZg==?=", "契約書.pdf"},
+		{"2231 value", "UTF-8''na%C3%AFve%20plan.txt", "naïve plan.txt"},
+		{"2231 value with language", "utf-8'en'%E2%82%AC100.xlsx", "€100.xlsx"},
+		{"2231 continuations", "filename*0*=UTF-8''%E6%96%87; filename*1*=%E4%BB%B6; filename*2=.pdf", "文件.pdf"},
+		{"2231 plain continuations", "name*0=long-report-; name*1=final.pdf", "long-report-final.pdf"},
+		{"2047 malformed charset", "=?no-such-charset?Q?abc.txt?=", "=?no-such-charset?Q?abc.txt?="},
+		{"2231 malformed charset", "no-such-charset''abc%20def.txt", "no-such-charset''abc%20def.txt"},
+		{"plain", "report.pdf", "report.pdf"},
+		{"apostrophes", "Bob's 'draft'.txt", "Bob's 'draft'.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeAttachmentName(tt.in); got != tt.want {
+				t.Errorf("decodeAttachmentName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,26 +1,59 @@
 package jmap
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"git.sr.ht/~rockorager/go-jmap"
 	"git.sr.ht/~rockorager/go-jmap/mail"
 	"git.sr.ht/~rockorager/go-jmap/mail/email"
+	"git.sr.ht/~rockorager/go-jmap/mail/emailsubmission"
 	"git.sr.ht/~rockorager/go-jmap/mail/mailbox"
+	"git.sr.ht/~rockorager/go-jmap/mail/thread"
 	"github.com/the9x/anneal/internal/models"
 )
 
+// TodoKeyword marks an email for follow-up
+const TodoKeyword = "$todo"
+
 // Client wraps the JMAP client for Fastmail
 type Client struct {
 	client      *jmap.Client
 	accountID   jmap.ID
 	email       string
 	accessToken string
+
+	// Server capabilities detected from the session
+	supportsSubmission bool
+	maxDelayedSend     time.Duration // 0 when scheduled sending is unsupported
+
+	// Account settings applied to outgoing mail
+	replyTo         string
+	identityReplyTo map[string]string // by lowercased identity address
+	sender          string
+
+	// Fetch headers with message lists so priority can be read
+	fetchPriority bool
+
+	// Outcome of recent requests, for the connection indicator
+	status connStatus
+
+	// Session in use, replaced when it expires; authMu lets only one
+	// request re-authenticate at a time
+	session *jmap.Session
+	authMu  sync.Mutex
 }
 
+// ErrNoMailAccount means the token signed in but has no mail access
+var ErrNoMailAccount = errors.New("no mail account found")
+
 // New creates a new JMAP client for Fastmail
 func New(emailAddr, token string) (*Client, error) {
 	client := &jmap.Client{
@@ -30,21 +63,55 @@ func New(emailAddr, token string) (*Client, error) {
 
 	// Authenticate and get session
 	if err := client.Authenticate(); err != nil {
+		debugf("✗ session request to %s failed: %v", client.SessionEndpoint, err)
 		return nil, fmt.Errorf("authentication failed: %w", err)
 	}
+	debugf("session established for %s (api %s)", emailAddr, client.Session.APIURL)
 
 	// Get account ID for mail
 	accountID := client.Session.PrimaryAccounts[mail.URI]
 	if accountID == "" {
-		return nil, fmt.Errorf("no mail account found")
+		return nil, ErrNoMailAccount
 	}
 
-	return &Client{
+	c := &Client{
 		client:      client,
 		accountID:   accountID,
 		email:       emailAddr,
 		accessToken: token,
-	}, nil
+		session:     client.Session,
+	}
+	c.detectCapabilities()
+
+	return c, nil
+}
+
+// detectCapabilities records which optional features the server supports.
+// Submission must be advertised on both the session and the account.
+func (c *Client) detectCapabilities() {
+	session := c.client.Session
+	_, c.supportsSubmission = session.RawCapabilities[emailsubmission.URI]
+	if acct, ok := session.Accounts[c.accountID]; ok {
+		if c.supportsSubmission {
+			_, c.supportsSubmission = acct.RawCapabilities[emailsubmission.URI]
+		}
+		if capability, ok := acct.Capabilities[emailsubmission.URI].(*emailsubmission.Capability); ok {
+			c.maxDelayedSend = time.Duration(capability.MaxDelayedSend) * time.Second
+		}
+	}
+
+	var uris []string
+	for uri := range session.RawCapabilities {
+		uris = append(uris, string(uri))
+	}
+	sort.Strings(uris)
+	debugf("server capabilities: %s", strings.Join(uris, ", "))
+	debugf("submission=%t max delayed send=%s", c.supportsSubmission, c.maxDelayedSend)
+}
+
+// SupportsSubmission reports whether the server allows sending email
+func (c *Client) SupportsSubmission() bool {
+	return c.supportsSubmission
 }
 
 // GetMailboxes fetches all mailboxes for the account
@@ -54,7 +121,7 @@ func (c *Client) GetMailboxes() ([]models.Mailbox, error) {
 		Account: c.accountID,
 	})
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get mailboxes: %w", err)
 	}
@@ -63,15 +130,7 @@ func (c *Client) GetMailboxes() ([]models.Mailbox, error) {
 	for _, inv := range resp.Responses {
 		if getResp, ok := inv.Args.(*mailbox.GetResponse); ok {
 			for _, mb := range getResp.List {
-				mailboxes = append(mailboxes, models.Mailbox{
-					ID:          string(mb.ID),
-					Name:        mb.Name,
-					Role:        string(mb.Role),
-					ParentID:    string(mb.ParentID),
-					TotalEmails: int(mb.TotalEmails),
-					UnreadCount: int(mb.UnreadEmails),
-					SortOrder:   int(mb.SortOrder),
-				})
+				mailboxes = append(mailboxes, convertMailbox(mb))
 			}
 		}
 	}
@@ -103,14 +162,10 @@ func (c *Client) GetEmails(mailboxID string, limit int) ([]models.Email, error)
 			Name:     "Email/query",
 			Path:     "/ids",
 		},
-		Properties: []string{
-			"id", "threadId", "mailboxIds", "from", "to", "cc", "bcc",
-			"replyTo", "subject", "preview", "receivedAt", "size",
-			"keywords", "hasAttachment",
-		},
+		Properties: c.summaryProperties(),
 	})
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get emails: %w", err)
 	}
@@ -135,14 +190,14 @@ func (c *Client) GetEmail(emailID string) (*models.Email, error) {
 		IDs:     []jmap.ID{jmap.ID(emailID)},
 		Properties: []string{
 			"id", "threadId", "mailboxIds", "from", "to", "cc", "bcc",
-			"replyTo", "subject", "preview", "receivedAt", "size",
+			"replyTo", "subject", "preview", "receivedAt", "sentAt", "size",
 			"keywords", "hasAttachment", "textBody", "htmlBody",
-			"attachments", "bodyValues",
+			"attachments", "bodyValues", "bodyStructure", "headers", "sender",
 		},
 		FetchAllBodyValues: true,
 	})
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get email: %w", err)
 	}
@@ -159,6 +214,48 @@ func (c *Client) GetEmail(emailID string) (*models.Email, error) {
 	return nil, fmt.Errorf("email not found")
 }
 
+// GetEmailBodies fetches several emails with full bodies in one request
+func (c *Client) GetEmailBodies(ids []string) ([]*models.Email, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	jmapIDs := make([]jmap.ID, len(ids))
+	for i, id := range ids {
+		jmapIDs[i] = jmap.ID(id)
+	}
+
+	req := &jmap.Request{}
+	req.Invoke(&email.Get{
+		Account: c.accountID,
+		IDs:     jmapIDs,
+		Properties: []string{
+			"id", "threadId", "mailboxIds", "from", "to", "cc", "bcc",
+			"replyTo", "subject", "preview", "receivedAt", "sentAt", "size",
+			"keywords", "hasAttachment", "textBody", "htmlBody",
+			"attachments", "bodyValues", "bodyStructure", "headers", "sender",
+		},
+		FetchAllBodyValues: true,
+	})
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get emails: %w", err)
+	}
+
+	var emails []*models.Email
+	for _, inv := range resp.Responses {
+		if getResp, ok := inv.Args.(*email.GetResponse); ok {
+			for _, e := range getResp.List {
+				converted := convertEmail(e)
+				emails = append(emails, &converted)
+			}
+		}
+	}
+
+	return emails, nil
+}
+
 // SetEmailKeywords updates email keywords (read/unread, flagged, etc.)
 func (c *Client) SetEmailKeywords(emailID string, keywords map[string]bool) error {
 	req := &jmap.Request{}
@@ -176,7 +273,7 @@ func (c *Client) SetEmailKeywords(emailID string, keywords map[string]bool) erro
 		},
 	})
 
-	_, err := c.client.Do(req)
+	_, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to update email: %w", err)
 	}
@@ -184,6 +281,35 @@ func (c *Client) SetEmailKeywords(emailID string, keywords map[string]bool) erro
 	return nil
 }
 
+// SetEmailsKeywords updates the keywords of several emails in a single
+// request
+func (c *Client) SetEmailsKeywords(emailIDs []string, keywords map[string]bool) error {
+	if len(emailIDs) == 0 {
+		return nil
+	}
+
+	patch := jmap.Patch{}
+	for k, v := range keywords {
+		patch["keywords/"+k] = v
+	}
+	updates := make(map[jmap.ID]jmap.Patch, len(emailIDs))
+	for _, id := range emailIDs {
+		updates[jmap.ID(id)] = patch
+	}
+
+	req := &jmap.Request{}
+	req.Invoke(&email.Set{
+		Account: c.accountID,
+		Update:  updates,
+	})
+
+	if _, err := c.do(req); err != nil {
+		return fmt.Errorf("failed to update emails: %w", err)
+	}
+
+	return nil
+}
+
 // MarkAsRead marks an email as read
 func (c *Client) MarkAsRead(emailID string) error {
 	return c.SetEmailKeywords(emailID, map[string]bool{
@@ -198,6 +324,49 @@ func (c *Client) MarkAsUnread(emailID string) error {
 	})
 }
 
+// GetEmailsWithKeyword fetches the newest emails carrying a keyword,
+// across all mailboxes
+func (c *Client) GetEmailsWithKeyword(keyword string, limit int) ([]models.Email, error) {
+	req := &jmap.Request{}
+
+	queryCall := req.Invoke(&email.Query{
+		Account: c.accountID,
+		Filter: &email.FilterCondition{
+			HasKeyword: keyword,
+		},
+		Sort: []*email.SortComparator{
+			{Property: "receivedAt", IsAscending: false},
+		},
+		Limit: uint64(limit),
+	})
+
+	req.Invoke(&email.Get{
+		Account: c.accountID,
+		ReferenceIDs: &jmap.ResultReference{
+			ResultOf: queryCall,
+			Name:     "Email/query",
+			Path:     "/ids",
+		},
+		Properties: c.summaryProperties(),
+	})
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get emails: %w", err)
+	}
+
+	var emails []models.Email
+	for _, inv := range resp.Responses {
+		if getResp, ok := inv.Args.(*email.GetResponse); ok {
+			for _, e := range getResp.List {
+				emails = append(emails, convertEmail(e))
+			}
+		}
+	}
+
+	return emails, nil
+}
+
 // MoveEmail moves an email to a different mailbox
 func (c *Client) MoveEmail(emailID string, fromMailboxID, toMailboxID string) error {
 	req := &jmap.Request{}
@@ -215,7 +384,7 @@ func (c *Client) MoveEmail(emailID string, fromMailboxID, toMailboxID string) er
 		},
 	})
 
-	_, err := c.client.Do(req)
+	_, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to move email: %w", err)
 	}
@@ -223,11 +392,95 @@ func (c *Client) MoveEmail(emailID string, fromMailboxID, toMailboxID string) er
 	return nil
 }
 
+// MoveEmails moves several emails to one mailbox in a single request
+func (c *Client) MoveEmails(emailIDs []string, toMailboxID string) error {
+	if len(emailIDs) == 0 {
+		return nil
+	}
+
+	updates := make(map[jmap.ID]jmap.Patch, len(emailIDs))
+	for _, id := range emailIDs {
+		updates[jmap.ID(id)] = jmap.Patch{
+			"mailboxIds": map[jmap.ID]bool{
+				jmap.ID(toMailboxID): true,
+			},
+		}
+	}
+
+	req := &jmap.Request{}
+	req.Invoke(&email.Set{
+		Account: c.accountID,
+		Update:  updates,
+	})
+
+	if _, err := c.do(req); err != nil {
+		return fmt.Errorf("failed to move emails: %w", err)
+	}
+
+	return nil
+}
+
+// SetEmailMailboxes replaces the mailboxes of several emails in a single
+// request, keyed by email ID. Used to undo a move.
+func (c *Client) SetEmailMailboxes(mailboxes map[string][]string) error {
+	if len(mailboxes) == 0 {
+		return nil
+	}
+
+	updates := make(map[jmap.ID]jmap.Patch, len(mailboxes))
+	for emailID, mailboxIDs := range mailboxes {
+		ids := make(map[jmap.ID]bool, len(mailboxIDs))
+		for _, id := range mailboxIDs {
+			ids[jmap.ID(id)] = true
+		}
+		updates[jmap.ID(emailID)] = jmap.Patch{"mailboxIds": ids}
+	}
+
+	req := &jmap.Request{}
+	req.Invoke(&email.Set{
+		Account: c.accountID,
+		Update:  updates,
+	})
+
+	if _, err := c.do(req); err != nil {
+		return fmt.Errorf("failed to restore emails: %w", err)
+	}
+
+	return nil
+}
+
 // DeleteEmail moves an email to trash
 func (c *Client) DeleteEmail(emailID, trashMailboxID string) error {
 	return c.MoveEmail(emailID, "", trashMailboxID)
 }
 
+// convertMailbox converts a JMAP mailbox to our model
+func convertMailbox(mb *mailbox.Mailbox) models.Mailbox {
+	result := models.Mailbox{
+		ID:          string(mb.ID),
+		Name:        mb.Name,
+		Role:        string(mb.Role),
+		ParentID:    string(mb.ParentID),
+		TotalEmails: int(mb.TotalEmails),
+		UnreadCount: int(mb.UnreadEmails),
+		SortOrder:   int(mb.SortOrder),
+	}
+	if r := mb.Rights; r != nil {
+		result.Rights = &models.MailboxRights{
+			MayReadItems:   r.MayReadItems,
+			MayAddItems:    r.MayAddItems,
+			MayRemoveItems: r.MayRemoveItems,
+			MaySetSeen:     r.MaySetSeen,
+			MaySetKeywords: r.MaySetKeywords,
+			MayCreateChild: r.MayCreateChild,
+			MayRename:      r.MayRename,
+			MayDelete:      r.MayDelete,
+			MaySubmit:      r.MaySubmit,
+		}
+	}
+	return result
+}
+
 // convertEmail converts a JMAP email to our model
 func convertEmail(e *email.Email) models.Email {
 	result := models.Email{
@@ -243,6 +496,9 @@ func convertEmail(e *email.Email) models.Email {
 	if e.ReceivedAt != nil {
 		result.ReceivedAt = *e.ReceivedAt
 	}
+	if e.SentAt != nil {
+		result.SentAt = *e.SentAt
+	}
 
 	// Convert mailbox IDs
 	for id := range e.MailboxIDs {
@@ -250,30 +506,11 @@ func convertEmail(e *email.Email) models.Email {
 	}
 
 	// Convert addresses
-	for _, addr := range e.From {
-		result.From = append(result.From, models.EmailAddress{
-			Name:  addr.Name,
-			Email: addr.Email,
-		})
-	}
-	for _, addr := range e.To {
-		result.To = append(result.To, models.EmailAddress{
-			Name:  addr.Name,
-			Email: addr.Email,
-		})
-	}
-	for _, addr := range e.CC {
-		result.CC = append(result.CC, models.EmailAddress{
-			Name:  addr.Name,
-			Email: addr.Email,
-		})
-	}
-	for _, addr := range e.ReplyTo {
-		result.ReplyTo = append(result.ReplyTo, models.EmailAddress{
-			Name:  addr.Name,
-			Email: addr.Email,
-		})
-	}
+	result.From = convertAddresses(e.From)
+	result.To = convertAddresses(e.To)
+	result.CC = convertAddresses(e.CC)
+	result.ReplyTo = convertAddresses(e.ReplyTo)
+	result.Sender = convertAddresses(e.Sender)
 
 	// Check keywords
 	if seen, ok := e.Keywords["$seen"]; ok && seen {
@@ -287,6 +524,9 @@ func convertEmail(e *email.Email) models.Email {
 	if draft, ok := e.Keywords["$draft"]; ok && draft {
 		result.IsDraft = true
 	}
+	if todo, ok := e.Keywords[TodoKeyword]; ok && todo {
+		result.IsTodo = true
+	}
 
 	// Get body content from body values
 	for _, part := range e.TextBody {
@@ -300,14 +540,23 @@ func convertEmail(e *email.Email) models.Email {
 		}
 	}
 
+	result.Priority = headerPriority(e.Headers)
+
+	// Signed or encrypted mail, from its MIME structure or inline armor
+	result.Security = partSecurity(e.BodyStructure)
+	if result.Security == "" {
+		result.Security = inlineSecurity(result.TextBody)
+	}
+
 	// Convert attachments
 	for _, att := range e.Attachments {
 		result.Attachments = append(result.Attachments, models.Attachment{
 			BlobID:   string(att.BlobID),
-			Name:     att.Name,
+			Name:     decodeAttachmentName(att.Name),
 			Type:     att.Type,
 			Size:     int(att.Size),
 			IsInline: att.Disposition == "inline",
+			CID:      att.CID,
 		})
 	}
 
@@ -334,8 +583,21 @@ func (c *Client) DownloadURL(blobID, filename string) string {
 	return url
 }
 
-// DownloadBlob downloads a blob and returns its contents
+// DownloadBlob downloads a blob and returns its contents, re-establishing
+// the session once if it has expired
 func (c *Client) DownloadBlob(blobID, filename string) ([]byte, error) {
+	data, err := c.downloadBlob(blobID, filename)
+	if !isAuthExpired(err) {
+		return data, err
+	}
+	if rerr := c.reauthenticate(); rerr != nil {
+		return nil, err
+	}
+	return c.downloadBlob(blobID, filename)
+}
+
+// downloadBlob makes a single download attempt
+func (c *Client) downloadBlob(blobID, filename string) ([]byte, error) {
 	url := c.DownloadURL(blobID, filename)
 
 	req, err := http.NewRequest("GET", url, nil)
@@ -346,12 +608,18 @@ func (c *Client) DownloadBlob(blobID, filename string) ([]byte, error) {
 	// Add authorization header (same as JMAP client uses)
 	req.Header.Set("Authorization", "Bearer "+c.accessToken)
 
+	start := time.Now()
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
+		debugf("✗ download %s failed: %v", blobID, err)
 		return nil, fmt.Errorf("failed to download: %w", err)
 	}
 	defer resp.Body.Close()
+	debugf("← download %s status %d in %s", blobID, resp.StatusCode, time.Since(start).Round(time.Millisecond))
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, &jmap.RequestError{Status: resp.StatusCode, Detail: "download failed: session expired"}
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("download failed with status: %d", resp.StatusCode)
 	}
@@ -381,7 +649,7 @@ func (c *Client) MailboxesWithState() ([]models.Mailbox, string, error) {
 		Account: c.accountID,
 	})
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to get mailboxes: %w", err)
 	}
@@ -392,15 +660,7 @@ func (c *Client) MailboxesWithState() ([]models.Mailbox, string, error) {
 		if getResp, ok := inv.Args.(*mailbox.GetResponse); ok {
 			state = getResp.State
 			for _, mb := range getResp.List {
-				mailboxes = append(mailboxes, models.Mailbox{
-					ID:          string(mb.ID),
-					Name:        mb.Name,
-					Role:        string(mb.Role),
-					ParentID:    string(mb.ParentID),
-					TotalEmails: int(mb.TotalEmails),
-					UnreadCount: int(mb.UnreadEmails),
-					SortOrder:   int(mb.SortOrder),
-				})
+				mailboxes = append(mailboxes, convertMailbox(mb))
 			}
 		}
 	}
@@ -416,7 +676,7 @@ func (c *Client) GetMailboxChanges(sinceState string) (*ChangesResult, error) {
 		SinceState: sinceState,
 	})
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get mailbox changes: %w", err)
 	}
@@ -461,7 +721,7 @@ func (c *Client) GetMailboxesByIDs(ids []string) ([]models.Mailbox, error) {
 		IDs:     jmapIDs,
 	})
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get mailboxes: %w", err)
 	}
@@ -470,20 +730,57 @@ func (c *Client) GetMailboxesByIDs(ids []string) ([]models.Mailbox, error) {
 	for _, inv := range resp.Responses {
 		if getResp, ok := inv.Args.(*mailbox.GetResponse); ok {
 			for _, mb := range getResp.List {
-				mailboxes = append(mailboxes, models.Mailbox{
-					ID:          string(mb.ID),
-					Name:        mb.Name,
-					Role:        string(mb.Role),
-					ParentID:    string(mb.ParentID),
+				mailboxes = append(mailboxes, convertMailbox(mb))
+			}
+		}
+	}
+
+	return mailboxes, nil
+}
+
+// MailboxCounts holds a mailbox's message counts
+type MailboxCounts struct {
+	TotalEmails int
+	UnreadCount int
+}
+
+// GetMailboxCounts fetches only the message counts of the given mailboxes,
+// keyed by mailbox ID
+func (c *Client) GetMailboxCounts(ids []string) (map[string]MailboxCounts, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	req := &jmap.Request{}
+	jmapIDs := make([]jmap.ID, len(ids))
+	for i, id := range ids {
+		jmapIDs[i] = jmap.ID(id)
+	}
+
+	req.Invoke(&mailbox.Get{
+		Account:    c.accountID,
+		IDs:        jmapIDs,
+		Properties: []string{"id", "totalEmails", "unreadEmails"},
+	})
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mailbox counts: %w", err)
+	}
+
+	counts := make(map[string]MailboxCounts, len(ids))
+	for _, inv := range resp.Responses {
+		if getResp, ok := inv.Args.(*mailbox.GetResponse); ok {
+			for _, mb := range getResp.List {
+				counts[string(mb.ID)] = MailboxCounts{
 					TotalEmails: int(mb.TotalEmails),
 					UnreadCount: int(mb.UnreadEmails),
-					SortOrder:   int(mb.SortOrder),
-				})
+				}
 			}
 		}
 	}
 
-	return mailboxes, nil
+	return counts, nil
 }
 
 // EmailsWithState fetches emails from a mailbox and returns the state token
@@ -508,14 +805,10 @@ func (c *Client) EmailsWithState(mailboxID string, limit int) ([]models.Email, s
 			Name:     "Email/query",
 			Path:     "/ids",
 		},
-		Properties: []string{
-			"id", "threadId", "mailboxIds", "from", "to", "cc", "bcc",
-			"replyTo", "subject", "preview", "receivedAt", "size",
-			"keywords", "hasAttachment",
-		},
+		Properties: c.summaryProperties(),
 	})
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to get emails: %w", err)
 	}
@@ -534,6 +827,39 @@ func (c *Client) EmailsWithState(mailboxID string, limit int) ([]models.Email, s
 	return emails, state, nil
 }
 
+// QueryEmailIDs returns the IDs of up to limit emails in a mailbox,
+// newest first, skipping the first position of them
+func (c *Client) QueryEmailIDs(mailboxID string, position, limit int) ([]string, error) {
+	req := &jmap.Request{}
+	req.Invoke(&email.Query{
+		Account: c.accountID,
+		Filter: &email.FilterCondition{
+			InMailbox: jmap.ID(mailboxID),
+		},
+		Sort: []*email.SortComparator{
+			{Property: "receivedAt", IsAscending: false},
+		},
+		Position: int64(position),
+		Limit:    uint64(limit),
+	})
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query emails: %w", err)
+	}
+
+	for _, inv := range resp.Responses {
+		if queryResp, ok := inv.Args.(*email.QueryResponse); ok {
+			ids := make([]string, len(queryResp.IDs))
+			for i, id := range queryResp.IDs {
+				ids[i] = string(id)
+			}
+			return ids, nil
+		}
+	}
+	return nil, fmt.Errorf("no query response received")
+}
+
 // GetEmailChanges gets email changes since the given state token
 func (c *Client) GetEmailChanges(sinceState string) (*ChangesResult, error) {
 	req := &jmap.Request{}
@@ -542,7 +868,7 @@ func (c *Client) GetEmailChanges(sinceState string) (*ChangesResult, error) {
 		SinceState: sinceState,
 	})
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get email changes: %w", err)
 	}
@@ -572,29 +898,95 @@ func (c *Client) GetEmailChanges(sinceState string) (*ChangesResult, error) {
 
 // GetEmailsByIDs fetches specific emails by ID (metadata only)
 func (c *Client) GetEmailsByIDs(ids []string) ([]models.Email, error) {
+	return c.GetEmailsByIDsWithContext(context.Background(), ids)
+}
+
+// GetEmailsByIDsWithContext is GetEmailsByIDs with a context that can
+// cancel the request
+func (c *Client) GetEmailsByIDsWithContext(ctx context.Context, ids []string) ([]models.Email, error) {
 	if len(ids) == 0 {
 		return nil, nil
 	}
 
-	req := &jmap.Request{}
+	req := &jmap.Request{Context: ctx}
 	jmapIDs := make([]jmap.ID, len(ids))
 	for i, id := range ids {
 		jmapIDs[i] = jmap.ID(id)
 	}
 
+	req.Invoke(&email.Get{
+		Account:    c.accountID,
+		IDs:        jmapIDs,
+		Properties: c.summaryProperties(),
+	})
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get emails: %w", err)
+	}
+
+	var emails []models.Email
+	for _, inv := range resp.Responses {
+		if getResp, ok := inv.Args.(*email.GetResponse); ok {
+			for _, e := range getResp.List {
+				emails = append(emails, convertEmail(e))
+			}
+		}
+	}
+
+	return emails, nil
+}
+
+// GetThreadEmailIDs returns the IDs of every email the server has in a thread
+func (c *Client) GetThreadEmailIDs(threadID string) ([]string, error) {
+	req := &jmap.Request{}
+	req.Invoke(&thread.Get{
+		Account: c.accountID,
+		IDs:     []jmap.ID{jmap.ID(threadID)},
+	})
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thread: %w", err)
+	}
+
+	for _, inv := range resp.Responses {
+		if getResp, ok := inv.Args.(*thread.GetResponse); ok {
+			if len(getResp.List) > 0 {
+				ids := make([]string, len(getResp.List[0].EmailIDs))
+				for i, id := range getResp.List[0].EmailIDs {
+					ids[i] = string(id)
+				}
+				return ids, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("thread not found")
+}
+
+// GetThread fetches every email in a thread (metadata only), across all mailboxes
+func (c *Client) GetThread(threadID string) ([]models.Email, error) {
+	req := &jmap.Request{}
+
+	threadCall := req.Invoke(&thread.Get{
+		Account: c.accountID,
+		IDs:     []jmap.ID{jmap.ID(threadID)},
+	})
+
 	req.Invoke(&email.Get{
 		Account: c.accountID,
-		IDs:     jmapIDs,
-		Properties: []string{
-			"id", "threadId", "mailboxIds", "from", "to", "cc", "bcc",
-			"replyTo", "subject", "preview", "receivedAt", "size",
-			"keywords", "hasAttachment",
+		ReferenceIDs: &jmap.ResultReference{
+			ResultOf: threadCall,
+			Name:     "Thread/get",
+			Path:     "/list/*/emailIds",
 		},
+		Properties: c.summaryProperties(),
 	})
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get emails: %w", err)
+		return nil, fmt.Errorf("failed to get thread: %w", err)
 	}
 
 	var emails []models.Email
@@ -0,0 +1,116 @@
+package jmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"git.sr.ht/~rockorager/go-jmap"
+)
+
+const (
+	debugLogFile    = "debug.log"
+	debugLogMaxSize = 5 * 1024 * 1024 // rotate once the log passes 5 MB
+	debugArgsMax    = 200             // max characters of arguments to log per call
+)
+
+// debugLog receives a line per JMAP request when debug mode is on
+var debugLog *log.Logger
+
+// SetDebugLog enables request logging to w. Pass nil to disable.
+func SetDebugLog(w io.Writer) {
+	if w == nil {
+		debugLog = nil
+		return
+	}
+	debugLog = log.New(w, "", log.LstdFlags|log.Lmicroseconds)
+}
+
+// OpenDebugLog opens the debug log file in dir, rotating the previous log
+// to debug.log.1 when it has grown past the size limit
+func OpenDebugLog(dir string) (*os.File, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, debugLogFile)
+	if info, err := os.Stat(path); err == nil && info.Size() > debugLogMaxSize {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return nil, fmt.Errorf("failed to rotate debug log: %w", err)
+		}
+	}
+
+	return os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+}
+
+// debugf writes a line to the debug log if enabled
+func debugf(format string, args ...interface{}) {
+	if debugLog != nil {
+		debugLog.Printf(format, args...)
+	}
+}
+
+// Logf writes a line to the debug log if enabled, for client-side events
+// worth tracing alongside the requests, like a filtering rule firing
+func Logf(format string, args ...interface{}) {
+	debugf(format, args...)
+}
+
+// doOnce performs a JMAP request, recording its outcome for the connection
+// indicator and logging each method call with its timing when debug mode
+// is on. The access token lives in the HTTP client and is never part of
+// the logged arguments.
+func (c *Client) doOnce(req *jmap.Request) (*jmap.Response, error) {
+	c.status.begin()
+	if debugLog == nil {
+		resp, err := c.client.Do(req)
+		c.status.end(err)
+		return resp, err
+	}
+
+	var methods []string
+	for _, call := range req.Calls {
+		methods = append(methods, call.Name)
+		debugf("→ %s %s", call.Name, summarizeArgs(call.Args))
+	}
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	c.status.end(err)
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		debugf("✗ %s failed after %s: %v", strings.Join(methods, ", "), elapsed, err)
+		return resp, err
+	}
+
+	for _, inv := range resp.Responses {
+		if methodErr, ok := inv.Args.(*jmap.MethodError); ok {
+			debugf("✗ %s (%s): %s", inv.Name, inv.CallID, methodErr.Error())
+			continue
+		}
+		debugf("← %s (%s) ok", inv.Name, inv.CallID)
+	}
+	debugf("  %s took %s", strings.Join(methods, ", "), elapsed)
+
+	return resp, nil
+}
+
+// summarizeArgs renders method arguments as JSON, cut to debugArgsMax
+// characters
+func summarizeArgs(args interface{}) string {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Sprintf("(%T)", args)
+	}
+	summary := []rune(string(data))
+	if len(summary) > debugArgsMax {
+		return string(summary[:debugArgsMax]) + "…"
+	}
+	return string(summary)
+}
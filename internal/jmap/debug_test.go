@@ -0,0 +1,23 @@
+package jmap
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSummarizeArgsCutsByCharacter(t *testing.T) {
+	short := map[string]string{"subject": "Grüße"}
+	if got, want := summarizeArgs(short), `{"subject":"Grüße"}`; got != want {
+		t.Errorf("summarizeArgs = %q, want %q", got, want)
+	}
+
+	long := map[string]string{"subject": strings.Repeat("日本", debugArgsMax)}
+	got := summarizeArgs(long)
+	if !utf8.ValidString(got) {
+		t.Errorf("summary cut through a character: %q", got)
+	}
+	if n := utf8.RuneCountInString(strings.TrimSuffix(got, "…")); n != debugArgsMax {
+		t.Errorf("summary is %d characters, want %d", n, debugArgsMax)
+	}
+}
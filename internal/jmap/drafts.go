@@ -0,0 +1,161 @@
+package jmap
+
+import (
+	"fmt"
+	"time"
+
+	"git.sr.ht/~rockorager/go-jmap"
+	"git.sr.ht/~rockorager/go-jmap/mail"
+	"git.sr.ht/~rockorager/go-jmap/mail/email"
+)
+
+// SaveDraft stores an unfinished message in the Drafts mailbox and returns
+// its email ID. Emails can't be edited in place, so a non-empty replaceID
+// is destroyed in the same request, leaving a single copy of the draft.
+func (c *Client) SaveDraft(to, cc []string, subject, body string, inReplyTo []string, identityID, fromName, replaceID string) (string, error) {
+	ident, err := c.findIdentity(identityID)
+	if err != nil {
+		return "", err
+	}
+	draftsID, err := c.draftsMailboxID()
+	if err != nil {
+		return "", err
+	}
+
+	name := ident.Name
+	if override := sanitizeHeader(fromName); override != "" {
+		name = override
+	}
+
+	// Drafts may hold half-typed addresses, so they aren't validated here
+	toAddrs := make([]*mail.Address, len(to))
+	for i, addr := range to {
+		toAddrs[i] = &mail.Address{Email: sanitizeHeader(addr)}
+	}
+	ccAddrs := make([]*mail.Address, len(cc))
+	for i, addr := range cc {
+		ccAddrs[i] = &mail.Address{Email: sanitizeHeader(addr)}
+	}
+
+	now := time.Now()
+	draft := &email.Email{
+		MailboxIDs: map[jmap.ID]bool{draftsID: true},
+		From:       []*mail.Address{{Name: name, Email: ident.Email}},
+		To:         toAddrs,
+		CC:         ccAddrs,
+		Subject:    sanitizeHeader(subject),
+		SentAt:     &now,
+		Keywords:   map[string]bool{"$draft": true, "$seen": true},
+		BodyValues: map[string]*email.BodyValue{
+			"body": {Value: body},
+		},
+		TextBody: []*email.BodyPart{
+			{PartID: "body", Type: "text/plain"},
+		},
+	}
+	if replyTo := c.replyToFor(ident.Email); replyTo != "" {
+		draft.ReplyTo = []*mail.Address{{Email: replyTo}}
+	}
+	for _, id := range inReplyTo {
+		draft.InReplyTo = append(draft.InReplyTo, sanitizeHeader(id))
+	}
+
+	set := &email.Set{
+		Account: c.accountID,
+		Create: map[jmap.ID]*email.Email{
+			"draft": draft,
+		},
+	}
+	if replaceID != "" {
+		set.Destroy = []jmap.ID{jmap.ID(replaceID)}
+	}
+	req := &jmap.Request{}
+	req.Invoke(set)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to save draft: %w", err)
+	}
+
+	for _, inv := range resp.Responses {
+		if setResp, ok := inv.Args.(*email.SetResponse); ok {
+			for _, setErr := range setResp.NotCreated {
+				desc := "unknown error"
+				if setErr.Description != nil {
+					desc = *setErr.Description
+				}
+				return "", fmt.Errorf("failed to save draft: %s", desc)
+			}
+			if created, ok := setResp.Created["draft"]; ok && created != nil {
+				return string(created.ID), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("failed to save draft: no email created")
+}
+
+// DeleteDraft destroys a saved draft
+func (c *Client) DeleteDraft(id string) error {
+	req := &jmap.Request{}
+	req.Invoke(&email.Set{
+		Account: c.accountID,
+		Destroy: []jmap.ID{jmap.ID(id)},
+	})
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete draft: %w", err)
+	}
+
+	for _, inv := range resp.Responses {
+		if setResp, ok := inv.Args.(*email.SetResponse); ok {
+			for _, setErr := range setResp.NotDestroyed {
+				// Already gone is as good as deleted
+				if setErr.Type == "notFound" {
+					continue
+				}
+				desc := "unknown error"
+				if setErr.Description != nil {
+					desc = *setErr.Description
+				}
+				return fmt.Errorf("failed to delete draft: %s", desc)
+			}
+		}
+	}
+
+	return nil
+}
+
+// findIdentity returns the identity with the given ID, or the default
+// identity when identityID is empty
+func (c *Client) findIdentity(identityID string) (*Identity, error) {
+	if identityID == "" {
+		return c.GetDefaultIdentity()
+	}
+
+	identities, err := c.GetIdentities()
+	if err != nil {
+		return nil, err
+	}
+	for i := range identities {
+		if identities[i].ID == identityID {
+			return &identities[i], nil
+		}
+	}
+	return nil, fmt.Errorf("identity not found: %s", identityID)
+}
+
+// draftsMailboxID returns the ID of the mailbox with the drafts role
+func (c *Client) draftsMailboxID() (jmap.ID, error) {
+	mailboxes, err := c.GetMailboxes()
+	if err != nil {
+		return "", fmt.Errorf("failed to get mailboxes: %w", err)
+	}
+	for _, mb := range mailboxes {
+		if mb.Role == "drafts" {
+			return jmap.ID(mb.ID), nil
+		}
+	}
+	return "", fmt.Errorf("drafts mailbox not found")
+}
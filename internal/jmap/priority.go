@@ -0,0 +1,61 @@
+package jmap
+
+import (
+	"strings"
+
+	"git.sr.ht/~rockorager/go-jmap/mail/email"
+	"github.com/the9x/anneal/internal/models"
+)
+
+// SetFetchPriority makes message lists include each message's headers, so
+// their priority is known without opening them. Off by default since the
+// headers are most of the list's size.
+func (c *Client) SetFetchPriority(on bool) {
+	c.fetchPriority = on
+}
+
+// summaryProperties lists the Email properties fetched for message lists
+func (c *Client) summaryProperties() []string {
+	props := []string{
+		"id", "threadId", "mailboxIds", "from", "to", "cc", "bcc",
+		"replyTo", "subject", "preview", "receivedAt", "sentAt", "size",
+		"keywords", "hasAttachment",
+	}
+	if c.fetchPriority {
+		props = append(props, "headers")
+	}
+	return props
+}
+
+// headerPriority reads the priority a sender set from X-Priority (1 and 2
+// are high, 4 and 5 low), Importance or Priority. Without any of them the
+// message is normal priority.
+func headerPriority(headers []*email.Header) int {
+	for _, h := range headers {
+		value := strings.ToLower(strings.TrimSpace(h.Value))
+		switch strings.ToLower(h.Name) {
+		case "x-priority":
+			switch {
+			case strings.HasPrefix(value, "1"), strings.HasPrefix(value, "2"):
+				return models.PriorityHigh
+			case strings.HasPrefix(value, "4"), strings.HasPrefix(value, "5"):
+				return models.PriorityLow
+			}
+		case "importance":
+			switch value {
+			case "high":
+				return models.PriorityHigh
+			case "low":
+				return models.PriorityLow
+			}
+		case "priority":
+			switch value {
+			case "urgent":
+				return models.PriorityHigh
+			case "non-urgent":
+				return models.PriorityLow
+			}
+		}
+	}
+	return models.PriorityNormal
+}
@@ -0,0 +1,73 @@
+package jmap
+
+import (
+	"fmt"
+	"time"
+
+	"git.sr.ht/~rockorager/go-jmap"
+	"git.sr.ht/~rockorager/go-jmap/mail/email"
+)
+
+// RawEmail points at the original message of an email, as sent, for
+// exporting it whole
+type RawEmail struct {
+	ID         string
+	BlobID     string
+	From       string // sender address, empty when there is none
+	ReceivedAt time.Time
+}
+
+// GetRawEmails lists a page of a mailbox's emails, oldest first, with the
+// blobs holding their original messages. total is the size of the whole
+// mailbox, for paging through it.
+func (c *Client) GetRawEmails(mailboxID string, position, limit int) (emails []RawEmail, total int, err error) {
+	req := &jmap.Request{}
+
+	queryCall := req.Invoke(&email.Query{
+		Account: c.accountID,
+		Filter: &email.FilterCondition{
+			InMailbox: jmap.ID(mailboxID),
+		},
+		Sort: []*email.SortComparator{
+			{Property: "receivedAt", IsAscending: true},
+		},
+		Position:       int64(position),
+		Limit:          uint64(limit),
+		CalculateTotal: true,
+	})
+
+	req.Invoke(&email.Get{
+		Account: c.accountID,
+		ReferenceIDs: &jmap.ResultReference{
+			ResultOf: queryCall,
+			Name:     "Email/query",
+			Path:     "/ids",
+		},
+		Properties: []string{"id", "blobId", "from", "receivedAt"},
+	})
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list emails: %w", err)
+	}
+
+	for _, inv := range resp.Responses {
+		switch r := inv.Args.(type) {
+		case *email.QueryResponse:
+			total = int(r.Total)
+		case *email.GetResponse:
+			for _, e := range r.List {
+				raw := RawEmail{ID: string(e.ID), BlobID: string(e.BlobID)}
+				if len(e.From) > 0 {
+					raw.From = e.From[0].Email
+				}
+				if e.ReceivedAt != nil {
+					raw.ReceivedAt = *e.ReceivedAt
+				}
+				emails = append(emails, raw)
+			}
+		}
+	}
+
+	return emails, total, nil
+}
@@ -0,0 +1,134 @@
+package jmap
+
+import (
+	"fmt"
+	"time"
+
+	"git.sr.ht/~rockorager/go-jmap"
+	"git.sr.ht/~rockorager/go-jmap/mail/emailsubmission"
+)
+
+// ScheduledSend is a submission the server is holding until its send time
+type ScheduledSend struct {
+	ID      string // EmailSubmission ID, used to cancel
+	EmailID string
+	SendAt  time.Time
+}
+
+// MaxDelayedSend returns how far ahead the server lets a message be
+// scheduled, or 0 if it doesn't support scheduled sending
+func (c *Client) MaxDelayedSend() time.Duration {
+	return c.maxDelayedSend
+}
+
+// holdUntil returns the envelope parameters asking the server to hold the
+// message until sendAt (RFC 4865 FUTURERELEASE)
+func (c *Client) holdUntil(sendAt time.Time) (map[string]interface{}, error) {
+	if c.maxDelayedSend == 0 {
+		return nil, fmt.Errorf("this server doesn't support scheduled sending")
+	}
+	if time.Until(sendAt) > c.maxDelayedSend {
+		return nil, fmt.Errorf("the server can only schedule up to %s ahead", c.maxDelayedSend)
+	}
+	return map[string]interface{}{
+		"HOLDUNTIL": sendAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// GetScheduledSends lists submissions that haven't been released yet,
+// soonest first
+func (c *Client) GetScheduledSends() ([]ScheduledSend, error) {
+	req := &jmap.Request{}
+
+	queryCall := req.Invoke(&emailsubmission.Query{
+		Account: c.accountID,
+		Filter: &emailsubmission.FilterCondition{
+			UndoStatus: "pending",
+		},
+		Sort: []*emailsubmission.SortComparator{
+			{Property: "sendAt", IsAscending: true},
+		},
+	})
+
+	req.Invoke(&emailsubmission.Get{
+		Account: c.accountID,
+		ReferenceIDs: &jmap.ResultReference{
+			ResultOf: queryCall,
+			Name:     "EmailSubmission/query",
+			Path:     "/ids",
+		},
+		Properties: []string{"id", "emailId", "sendAt", "undoStatus"},
+	})
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled sends: %w", err)
+	}
+
+	var sends []ScheduledSend
+	for _, inv := range resp.Responses {
+		if getResp, ok := inv.Args.(*emailsubmission.GetResponse); ok {
+			for _, s := range getResp.List {
+				send := ScheduledSend{ID: string(s.ID), EmailID: string(s.EmailID)}
+				if s.SendAt != nil {
+					send.SendAt = s.SendAt.Local()
+				}
+				sends = append(sends, send)
+			}
+		}
+	}
+
+	return sends, nil
+}
+
+// CancelScheduledSend stops a held submission before its send time and
+// puts the message back in Drafts
+func (c *Client) CancelScheduledSend(send ScheduledSend) error {
+	mailboxes, err := c.GetMailboxes()
+	if err != nil {
+		return fmt.Errorf("failed to get mailboxes: %w", err)
+	}
+
+	var draftsID string
+	for _, mb := range mailboxes {
+		if mb.Role == "drafts" {
+			draftsID = mb.ID
+			break
+		}
+	}
+
+	restore := jmap.Patch{"keywords/$draft": true}
+	if draftsID != "" {
+		restore["mailboxIds"] = map[jmap.ID]bool{jmap.ID(draftsID): true}
+	}
+
+	req := &jmap.Request{}
+	req.Invoke(&emailsubmission.Set{
+		Account: c.accountID,
+		Update: map[jmap.ID]jmap.Patch{
+			jmap.ID(send.ID): {"undoStatus": "canceled"},
+		},
+		OnSuccessUpdateEmail: map[jmap.ID]jmap.Patch{
+			jmap.ID(send.ID): restore,
+		},
+	})
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to cancel scheduled send: %w", err)
+	}
+
+	for _, inv := range resp.Responses {
+		if setResp, ok := inv.Args.(*emailsubmission.SetResponse); ok {
+			for _, setErr := range setResp.NotUpdated {
+				desc := "it may already have been sent"
+				if setErr.Description != nil {
+					desc = *setErr.Description
+				}
+				return fmt.Errorf("failed to cancel scheduled send: %s", desc)
+			}
+		}
+	}
+
+	return nil
+}
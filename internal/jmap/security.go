@@ -0,0 +1,49 @@
+package jmap
+
+import (
+	"strings"
+
+	"git.sr.ht/~rockorager/go-jmap/mail/email"
+	"github.com/the9x/anneal/internal/models"
+)
+
+// partSecurity looks through a body structure for a signed or encrypted
+// part. PGP/MIME uses multipart/signed and multipart/encrypted; S/MIME
+// marks encrypted mail with application/pkcs7-mime. Encryption wins when
+// a message is both, since the signature is inside it.
+func partSecurity(part *email.BodyPart) string {
+	if part == nil {
+		return ""
+	}
+	switch strings.ToLower(part.Type) {
+	case "multipart/encrypted", "application/pkcs7-mime", "application/x-pkcs7-mime":
+		return models.SecurityEncrypted
+	}
+
+	security := ""
+	if strings.EqualFold(part.Type, "multipart/signed") {
+		security = models.SecuritySigned
+	}
+	for _, sub := range part.SubParts {
+		switch partSecurity(sub) {
+		case models.SecurityEncrypted:
+			return models.SecurityEncrypted
+		case models.SecuritySigned:
+			security = models.SecuritySigned
+		}
+	}
+	return security
+}
+
+// inlineSecurity spots PGP armor pasted into a plain text body, as sent by
+// clients that sign or encrypt inline rather than with PGP/MIME
+func inlineSecurity(text string) string {
+	trimmed := strings.TrimSpace(text)
+	switch {
+	case strings.HasPrefix(trimmed, "-----BEGIN PGP MESSAGE-----"):
+		return models.SecurityEncrypted
+	case strings.HasPrefix(trimmed, "-----BEGIN PGP SIGNED MESSAGE-----"):
+		return models.SecuritySigned
+	}
+	return ""
+}
@@ -1,8 +1,12 @@
 package jmap
 
 import (
+	"errors"
 	"fmt"
+	netmail "net/mail"
+	"strings"
 	"time"
+	"unicode"
 
 	"git.sr.ht/~rockorager/go-jmap"
 	"git.sr.ht/~rockorager/go-jmap/mail"
@@ -25,7 +29,7 @@ func (c *Client) GetIdentities() ([]Identity, error) {
 		Account: c.accountID,
 	})
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get identities: %w", err)
 	}
@@ -58,55 +62,98 @@ func (c *Client) GetDefaultIdentity() (*Identity, error) {
 	return &identities[0], nil
 }
 
+// SetReplyTo sets the Reply-To address added to every message sent from
+// this account. An empty address sends without Reply-To.
+func (c *Client) SetReplyTo(addr string) {
+	c.replyTo = strings.TrimSpace(addr)
+}
+
+// SetIdentityReplyTo sets Reply-To addresses for mail sent from particular
+// identities, keyed by the identity's address, in place of the one set
+// with SetReplyTo
+func (c *Client) SetIdentityReplyTo(replyTo map[string]string) {
+	c.identityReplyTo = make(map[string]string, len(replyTo))
+	for addr, to := range replyTo {
+		c.identityReplyTo[strings.ToLower(addr)] = strings.TrimSpace(to)
+	}
+}
+
+// replyToFor returns the Reply-To address for mail sent from addr
+func (c *Client) replyToFor(addr string) string {
+	if to := c.identityReplyTo[strings.ToLower(addr)]; to != "" {
+		return to
+	}
+	return c.replyTo
+}
+
+// SetSender sets the Sender header for mail sent on someone else's behalf,
+// as from a shared mailbox. It's left out of messages whose From is the
+// same address. An empty address sends without Sender.
+func (c *Client) SetSender(addr string) {
+	c.sender = strings.TrimSpace(addr)
+}
+
 // SendEmail creates and sends an email using the default identity
 func (c *Client) SendEmail(to, cc []string, subject, body string, inReplyTo, references []string) error {
-	return c.SendEmailWithIdentity(to, cc, subject, body, inReplyTo, references, "")
+	return c.SendEmailWithIdentity(to, cc, subject, body, inReplyTo, references, "", "", time.Time{}, nil)
 }
 
-// SendEmailWithIdentity creates and sends an email using a specific identity
-func (c *Client) SendEmailWithIdentity(to, cc []string, subject, body string, inReplyTo, references []string, identityID string) error {
-	// Get identity
-	var ident *Identity
-	var err error
+// SendEmailWithIdentity creates and sends an email using a specific
+// identity. A non-empty fromName replaces the identity's display name in
+// the From header for this message only. A non-zero sendAt asks the server
+// to hold the message until then. attachments are files uploaded with
+// UploadFile.
+func (c *Client) SendEmailWithIdentity(to, cc []string, subject, body string, inReplyTo, references []string, identityID, fromName string, sendAt time.Time, attachments []*Upload) error {
+	ident, err := c.findIdentity(identityID)
+	if err != nil {
+		return err
+	}
 
-	if identityID != "" {
-		// Find specific identity
-		identities, err := c.GetIdentities()
-		if err != nil {
+	// Drafts holds the message until the submission moves it out
+	draftsID, err := c.draftsMailboxID()
+	if err != nil {
+		return err
+	}
+
+	// Nothing user-typed may carry a line break into the headers
+	subject = sanitizeHeader(subject)
+	for _, addr := range append(append([]string{}, to...), cc...) {
+		if err := validateAddress(addr); err != nil {
 			return err
 		}
-		for i := range identities {
-			if identities[i].ID == identityID {
-				ident = &identities[i]
-				break
-			}
-		}
-		if ident == nil {
-			return fmt.Errorf("identity not found: %s", identityID)
+	}
+	replyTo := c.replyToFor(ident.Email)
+	if replyTo != "" {
+		if err := validateAddress(replyTo); err != nil {
+			return fmt.Errorf("invalid reply_to in config: %w", err)
 		}
-	} else {
-		// Use default identity
-		ident, err = c.GetDefaultIdentity()
-		if err != nil {
-			return err
+	}
+	if c.sender != "" {
+		if err := validateAddress(c.sender); err != nil {
+			return fmt.Errorf("invalid sender in config: %w", err)
 		}
 	}
-
-	// Get drafts mailbox for temporary storage
-	mailboxes, err := c.GetMailboxes()
-	if err != nil {
-		return fmt.Errorf("failed to get mailboxes: %w", err)
+	for i := range inReplyTo {
+		inReplyTo[i] = sanitizeHeader(inReplyTo[i])
+	}
+	for i := range references {
+		references[i] = sanitizeHeader(references[i])
 	}
 
-	var draftsID jmap.ID
-	for _, mb := range mailboxes {
-		if mb.Role == "drafts" {
-			draftsID = jmap.ID(mb.ID)
-			break
+	// Scheduled messages carry the release time on the envelope
+	var mailFromParams interface{}
+	if !sendAt.IsZero() {
+		params, err := c.holdUntil(sendAt)
+		if err != nil {
+			return err
 		}
+		mailFromParams = params
 	}
-	if draftsID == "" {
-		return fmt.Errorf("drafts mailbox not found")
+
+	// The From name may be overridden for this message
+	name := ident.Name
+	if override := sanitizeHeader(fromName); override != "" {
+		name = override
 	}
 
 	// Build recipient addresses
@@ -133,7 +180,7 @@ func (c *Client) SendEmailWithIdentity(to, cc []string, subject, body string, in
 	now := time.Now()
 	newEmail := &email.Email{
 		MailboxIDs: map[jmap.ID]bool{draftsID: true},
-		From:       []*mail.Address{{Name: ident.Name, Email: ident.Email}},
+		From:       []*mail.Address{{Name: name, Email: ident.Email}},
 		To:         toAddrs,
 		CC:         ccAddrs,
 		Subject:    subject,
@@ -147,6 +194,22 @@ func (c *Client) SendEmailWithIdentity(to, cc []string, subject, body string, in
 		},
 	}
 
+	for _, att := range attachments {
+		newEmail.Attachments = append(newEmail.Attachments, &email.BodyPart{
+			BlobID:      jmap.ID(att.BlobID),
+			Name:        att.Name,
+			Type:        att.Type,
+			Disposition: "attachment",
+		})
+	}
+
+	if replyTo != "" {
+		newEmail.ReplyTo = []*mail.Address{{Email: replyTo}}
+	}
+	if c.sender != "" && !strings.EqualFold(c.sender, ident.Email) {
+		newEmail.Sender = []*mail.Address{{Email: c.sender}}
+	}
+
 	// Add reply headers if replying
 	if len(inReplyTo) > 0 {
 		newEmail.InReplyTo = inReplyTo
@@ -159,7 +222,7 @@ func (c *Client) SendEmailWithIdentity(to, cc []string, subject, body string, in
 
 	// Create the email
 	emailCreateID := jmap.ID("draft")
-	_ = req.Invoke(&email.Set{
+	createCall := req.Invoke(&email.Set{
 		Account: c.accountID,
 		Create: map[jmap.ID]*email.Email{
 			emailCreateID: newEmail,
@@ -168,14 +231,14 @@ func (c *Client) SendEmailWithIdentity(to, cc []string, subject, body string, in
 
 	// Submit the email for sending
 	submissionID := jmap.ID("send")
-	req.Invoke(&emailsubmission.Set{
+	submitCall := req.Invoke(&emailsubmission.Set{
 		Account: c.accountID,
 		Create: map[jmap.ID]*emailsubmission.EmailSubmission{
 			submissionID: {
 				IdentityID: jmap.ID(ident.ID),
 				EmailID:    jmap.ID("#" + string(emailCreateID)),
 				Envelope: &emailsubmission.Envelope{
-					MailFrom: &emailsubmission.Address{Email: ident.Email},
+					MailFrom: &emailsubmission.Address{Email: ident.Email, Parameters: mailFromParams},
 					RcptTo:   rcptTo,
 				},
 			},
@@ -189,36 +252,102 @@ func (c *Client) SendEmailWithIdentity(to, cc []string, subject, body string, in
 		},
 	})
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
 
-	// Check for errors in responses
+	// The two steps succeed or fail separately: the message can be
+	// created in Drafts and then not be submitted
+	var createdID string
+	var createErr, submitErr error
 	for _, inv := range resp.Responses {
-		if setResp, ok := inv.Args.(*email.SetResponse); ok {
-			if len(setResp.NotCreated) > 0 {
-				for _, setErr := range setResp.NotCreated {
-					desc := "unknown error"
-					if setErr.Description != nil {
-						desc = *setErr.Description
-					}
-					return fmt.Errorf("failed to create email: %s", desc)
-				}
-			}
+		switch inv.CallID {
+		case createCall:
+			createdID, createErr = createdEmail(inv.Args, emailCreateID)
+		case submitCall:
+			submitErr = submissionResult(inv.Args, submissionID)
 		}
-		if setResp, ok := inv.Args.(*emailsubmission.SetResponse); ok {
-			if len(setResp.NotCreated) > 0 {
-				for _, setErr := range setResp.NotCreated {
-					desc := "unknown error"
-					if setErr.Description != nil {
-						desc = *setErr.Description
-					}
-					return fmt.Errorf("failed to submit email: %s", desc)
-				}
-			}
+	}
+	if createErr != nil {
+		return fmt.Errorf("failed to create email: %w", createErr)
+	}
+	if submitErr == nil {
+		return nil
+	}
+
+	// Don't leave an unsent copy in Drafts that nobody knows about
+	if createdID == "" {
+		return fmt.Errorf("failed to submit email: %w", submitErr)
+	}
+	if err := c.DeleteDraft(createdID); err != nil {
+		return fmt.Errorf("failed to submit email: %w (an unsent copy is left in Drafts)", submitErr)
+	}
+	return fmt.Errorf("failed to submit email: %w", submitErr)
+}
+
+// createdEmail returns the ID of the email created in an Email/set
+// response, or why it wasn't
+func createdEmail(args interface{}, createID jmap.ID) (string, error) {
+	switch r := args.(type) {
+	case *jmap.MethodError:
+		return "", r
+	case *email.SetResponse:
+		if setErr, ok := r.NotCreated[createID]; ok {
+			return "", errors.New(setErrorDescription(setErr))
+		}
+		if created, ok := r.Created[createID]; ok && created != nil {
+			return string(created.ID), nil
 		}
 	}
+	return "", nil
+}
 
+// submissionResult returns why an EmailSubmission/set response didn't
+// submit the message, or nil if it did
+func submissionResult(args interface{}, createID jmap.ID) error {
+	switch r := args.(type) {
+	case *jmap.MethodError:
+		return r
+	case *emailsubmission.SetResponse:
+		if setErr, ok := r.NotCreated[createID]; ok {
+			return errors.New(setErrorDescription(setErr))
+		}
+	}
+	return nil
+}
+
+// setErrorDescription describes why a server refused to create something
+func setErrorDescription(setErr *jmap.SetError) string {
+	if setErr != nil && setErr.Description != nil {
+		return *setErr.Description
+	}
+	if setErr != nil && setErr.Type != "" {
+		return setErr.Type
+	}
+	return "unknown error"
+}
+
+// sanitizeHeader strips line breaks and other control characters that
+// could start a new header, collapsing the result to a single line
+func sanitizeHeader(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '\r' || r == '\n' || r == '\t' {
+			return ' '
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// validateAddress rejects anything but a single bare email address
+func validateAddress(addr string) error {
+	parsed, err := netmail.ParseAddress(addr)
+	if err != nil || parsed.Address != addr {
+		return fmt.Errorf("invalid recipient address %q", addr)
+	}
 	return nil
 }
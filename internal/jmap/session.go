@@ -0,0 +1,64 @@
+package jmap
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"git.sr.ht/~rockorager/go-jmap"
+)
+
+// do performs a JMAP request. If the server rejects it because the
+// session expired, the session is fetched again and the request retried
+// once, so the UI never sees the expiry.
+func (c *Client) do(req *jmap.Request) (*jmap.Response, error) {
+	resp, err := c.doOnce(req)
+	if !isAuthExpired(err) {
+		return resp, err
+	}
+	if rerr := c.reauthenticate(); rerr != nil {
+		return resp, err
+	}
+	return c.doOnce(req)
+}
+
+// reauthenticate fetches a fresh session. Requests failing together share
+// one re-authentication: whoever arrives after it succeeded just retries.
+func (c *Client) reauthenticate() error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	c.client.Lock()
+	stale := c.client.Session
+	c.client.Unlock()
+	if stale != c.session {
+		return nil
+	}
+
+	debugf("session expired, re-authenticating")
+	if err := c.client.Authenticate(); err != nil {
+		debugf("✗ re-authentication failed: %v", err)
+		return fmt.Errorf("re-authentication failed: %w", err)
+	}
+
+	c.client.Lock()
+	c.session = c.client.Session
+	c.client.Unlock()
+	debugf("session re-established (api %s)", c.session.APIURL)
+	return nil
+}
+
+// isAuthExpired reports whether a request failed because the server no
+// longer accepts the session's credentials
+func isAuthExpired(err error) bool {
+	if err == nil {
+		return false
+	}
+	var reqErr *jmap.RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.Status == http.StatusUnauthorized
+	}
+	// Errors without a JSON body only carry the status in the message
+	return strings.HasPrefix(err.Error(), fmt.Sprintf("HTTP %d", http.StatusUnauthorized))
+}
@@ -0,0 +1,60 @@
+package jmap
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"git.sr.ht/~rockorager/go-jmap"
+	"git.sr.ht/~rockorager/go-jmap/mail/email"
+)
+
+// SnoozeEmails hands emails to the server's snooze: they move to the
+// mailbox with the snoozed role, and the server returns them to the inbox
+// at until, on every device. Fastmail keeps the wake time in the emails'
+// snoozed property, part of its vendor extensions, so those are declared
+// when the session advertises them. An error means the server wouldn't
+// take the snooze and the emails weren't moved.
+func (c *Client) SnoozeEmails(emailIDs []string, snoozedMailboxID string, until time.Time) error {
+	if len(emailIDs) == 0 {
+		return nil
+	}
+
+	updates := make(map[jmap.ID]jmap.Patch, len(emailIDs))
+	for _, id := range emailIDs {
+		updates[jmap.ID(id)] = jmap.Patch{
+			"mailboxIds": map[jmap.ID]bool{
+				jmap.ID(snoozedMailboxID): true,
+			},
+			"snoozed": map[string]string{
+				"until": until.UTC().Format("2006-01-02T15:04:05Z"),
+			},
+		}
+	}
+
+	req := &jmap.Request{}
+	req.Invoke(&email.Set{
+		Account: c.accountID,
+		Update:  updates,
+	})
+	for uri := range c.client.Session.RawCapabilities {
+		if strings.Contains(string(uri), "fastmail.com/dev") {
+			req.Using = append(req.Using, uri)
+		}
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to snooze: %w", err)
+	}
+
+	for _, inv := range resp.Responses {
+		if setResp, ok := inv.Args.(*email.SetResponse); ok {
+			for _, setErr := range setResp.NotUpdated {
+				return fmt.Errorf("failed to snooze: %s", setErrorDescription(setErr))
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package jmap
+
+import "sync"
+
+// ConnState describes how the last requests to the server went
+type ConnState int
+
+const (
+	ConnUnknown ConnState = iota // No request has completed yet
+	ConnOK                       // The last request succeeded
+	ConnFailed                   // The last request failed
+	ConnPending                  // A request is in flight
+)
+
+// connStatus tracks in-flight requests and the outcome of the last one
+type connStatus struct {
+	mu       sync.Mutex
+	inFlight int
+	state    ConnState
+}
+
+// begin records that a request has started
+func (s *connStatus) begin() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight++
+}
+
+// end records the outcome of a finished request
+func (s *connStatus) end(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight--
+	if err != nil {
+		s.state = ConnFailed
+	} else {
+		s.state = ConnOK
+	}
+}
+
+// ConnectionState reports whether a request is in flight, or else how the
+// last one finished
+func (c *Client) ConnectionState() ConnState {
+	c.status.mu.Lock()
+	defer c.status.mu.Unlock()
+	if c.status.inFlight > 0 {
+		return ConnPending
+	}
+	return c.status.state
+}
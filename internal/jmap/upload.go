@@ -0,0 +1,49 @@
+package jmap
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Upload is a file uploaded to the server, ready to attach to a message
+type Upload struct {
+	BlobID string
+	Name   string
+	Type   string
+	Size   int
+}
+
+// UploadFile uploads the file at path, typed by its extension or else by
+// its first bytes
+func (c *Client) UploadFile(path string) (*Upload, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+	}
+	defer f.Close()
+
+	mediaType := mime.TypeByExtension(strings.ToLower(filepath.Ext(path)))
+	if mediaType == "" {
+		head := make([]byte, 512)
+		n, _ := f.Read(head)
+		mediaType = http.DetectContentType(head[:n])
+		if _, err := f.Seek(0, 0); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+		}
+	}
+
+	resp, err := c.client.Upload(c.accountID, f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload %s: %w", filepath.Base(path), err)
+	}
+	return &Upload{
+		BlobID: string(resp.ID),
+		Name:   filepath.Base(path),
+		Type:   mediaType,
+		Size:   int(resp.Size),
+	}, nil
+}
@@ -1,8 +1,70 @@
 package models
 
+import "strings"
+
 // Account represents a Fastmail account configuration
 type Account struct {
 	Name    string `yaml:"name"`
 	Email   string `yaml:"email"`
 	Default bool   `yaml:"default,omitempty"`
+
+	// Signature is appended to messages composed from this account
+	Signature string `yaml:"signature,omitempty"`
+
+	// ReplyTo is set as the Reply-To header on mail sent from this account
+	ReplyTo string `yaml:"reply_to,omitempty"`
+
+	// Identities gives an identity, by its address, a signature or
+	// Reply-To of its own in place of the account's
+	Identities map[string]IdentitySettings `yaml:"identities,omitempty"`
+
+	// Sender is set as the Sender header when sending from an identity
+	// with another address, for mail sent on someone else's behalf
+	Sender string `yaml:"sender,omitempty"`
+
+	// FolderIdentities maps a folder name to the From address preselected
+	// when composing from that folder
+	FolderIdentities map[string]string `yaml:"folder_identities,omitempty"`
+
+	// WebURL is the address of a message in the provider's web app, with
+	// {mailbox}, {thread} and {email} filled in. Empty uses Fastmail's;
+	// "off" turns the link off.
+	WebURL string `yaml:"web_url,omitempty"`
+
+	// SplitFolders remembers, by folder name, the folder last shown beside
+	// it in the side-by-side view
+	SplitFolders map[string]string `yaml:"split_folders,omitempty"`
+}
+
+// IdentitySettings holds what mail sent from one identity carries in place
+// of the account's settings. Empty fields fall back to the account's.
+type IdentitySettings struct {
+	Signature string `yaml:"signature,omitempty"`
+	ReplyTo   string `yaml:"reply_to,omitempty"`
+}
+
+// identity returns the settings for the identity with the given address
+func (a *Account) identity(addr string) IdentitySettings {
+	for key, settings := range a.Identities {
+		if strings.EqualFold(key, addr) {
+			return settings
+		}
+	}
+	return IdentitySettings{}
+}
+
+// SignatureFor returns the signature for mail sent from addr
+func (a *Account) SignatureFor(addr string) string {
+	if sig := a.identity(addr).Signature; sig != "" {
+		return sig
+	}
+	return a.Signature
+}
+
+// ReplyToFor returns the Reply-To address for mail sent from addr
+func (a *Account) ReplyToFor(addr string) string {
+	if replyTo := a.identity(addr).ReplyTo; replyTo != "" {
+		return replyTo
+	}
+	return a.ReplyTo
 }
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // EmailAddress represents an email address with optional name
 type EmailAddress struct {
@@ -10,6 +13,9 @@ type EmailAddress struct {
 
 // String returns a formatted email address
 func (e EmailAddress) String() string {
+	if e.Email == "" {
+		return e.ShortName()
+	}
 	if e.Name != "" {
 		return e.Name + " <" + e.Email + ">"
 	}
@@ -21,7 +27,10 @@ func (e EmailAddress) ShortName() string {
 	if e.Name != "" {
 		return e.Name
 	}
-	return e.Email
+	if e.Email != "" {
+		return e.Email
+	}
+	return "(unknown)"
 }
 
 // Email represents an email message
@@ -34,19 +43,37 @@ type Email struct {
 	CC           []EmailAddress
 	BCC          []EmailAddress
 	ReplyTo      []EmailAddress
+	Sender       []EmailAddress // who sent it on From's behalf; fetched with the body
 	Subject      string
 	Preview      string
 	TextBody     string
 	HTMLBody     string
 	ReceivedAt   time.Time
+	SentAt       time.Time // from the Date header; zero when unknown
 	Size         int
 	IsUnread     bool
 	IsFlagged    bool
 	IsDraft      bool
+	IsTodo       bool // tagged $todo for follow-up
 	HasAttachment bool
 	Attachments  []Attachment
+	Security     string // SecuritySigned or SecurityEncrypted, "" for plain mail
+	Priority     int    // from X-Priority or Importance; see PriorityHigh
 }
 
+// Priorities a sender can set on a message
+const (
+	PriorityLow    = -1
+	PriorityNormal = 0
+	PriorityHigh   = 1
+)
+
+// Security markers for signed or encrypted (PGP or S/MIME) mail
+const (
+	SecuritySigned    = "signed"
+	SecurityEncrypted = "encrypted"
+)
+
 // Attachment represents an email attachment
 type Attachment struct {
 	BlobID   string
@@ -54,6 +81,7 @@ type Attachment struct {
 	Type     string
 	Size     int
 	IsInline bool
+	CID      string // Content-ID referenced by cid: URLs in HTML
 }
 
 // FromDisplay returns the primary sender for display
@@ -64,15 +92,41 @@ func (e *Email) FromDisplay() string {
 	return "(unknown)"
 }
 
-// DateDisplay returns a formatted date for list view
-func (e *Email) DateDisplay() string {
+// SentOnBehalf returns who sent the email when that isn't its author, as
+// with delegated or shared mailboxes
+func (e *Email) SentOnBehalf() (EmailAddress, bool) {
+	if len(e.Sender) == 0 || e.Sender[0].Email == "" {
+		return EmailAddress{}, false
+	}
+	for _, from := range e.From {
+		if strings.EqualFold(from.Email, e.Sender[0].Email) {
+			return EmailAddress{}, false
+		}
+	}
+	return e.Sender[0], true
+}
+
+// Time returns when the email was sent if sent is true, as for mail in the
+// Sent folder, and when it was received otherwise. Without a known sent
+// time it falls back to the received time.
+func (e *Email) Time(sent bool) time.Time {
+	if sent && !e.SentAt.IsZero() {
+		return e.SentAt
+	}
+	return e.ReceivedAt
+}
+
+// DateDisplay returns a formatted date for list view, using the sent time
+// if sent is true
+func (e *Email) DateDisplay(sent bool) string {
+	t := e.Time(sent)
 	now := time.Now()
-	if e.ReceivedAt.Year() == now.Year() &&
-		e.ReceivedAt.YearDay() == now.YearDay() {
-		return e.ReceivedAt.Format("3:04 PM")
+	if t.Year() == now.Year() &&
+		t.YearDay() == now.YearDay() {
+		return t.Format("3:04 PM")
 	}
-	if e.ReceivedAt.Year() == now.Year() {
-		return e.ReceivedAt.Format("Jan 2")
+	if t.Year() == now.Year() {
+		return t.Format("Jan 2")
 	}
-	return e.ReceivedAt.Format("Jan 2, 2006")
+	return t.Format("Jan 2, 2006")
 }
@@ -1,14 +1,113 @@
 package models
 
+// Virtual folder IDs, computed client-side
+const (
+	// FollowUpMailboxID identifies the virtual folder listing $todo emails
+	FollowUpMailboxID = "$followup"
+
+	// ScheduledMailboxID identifies the virtual folder listing messages
+	// held for a scheduled send
+	ScheduledMailboxID = "$scheduled"
+
+	// OutboxMailboxID identifies the virtual folder listing messages that
+	// failed to send
+	OutboxMailboxID = "$outbox"
+)
+
 // Mailbox represents a mail folder
 type Mailbox struct {
 	ID          string
 	Name        string
-	Role        string // inbox, drafts, sent, trash, archive, junk
+	Role        string // inbox, drafts, sent, trash, archive, junk, snoozed
 	ParentID    string
 	TotalEmails int
 	UnreadCount int
 	SortOrder   int
+
+	// Rights are the user's permissions on the mailbox, or nil when not
+	// known (virtual folders, caches from older versions)
+	Rights *MailboxRights
+}
+
+// MailboxRights mirrors JMAP's myRights for a mailbox
+type MailboxRights struct {
+	MayReadItems   bool `json:"mayReadItems"`
+	MayAddItems    bool `json:"mayAddItems"`
+	MayRemoveItems bool `json:"mayRemoveItems"`
+	MaySetSeen     bool `json:"maySetSeen"`
+	MaySetKeywords bool `json:"maySetKeywords"`
+	MayCreateChild bool `json:"mayCreateChild"`
+	MayRename      bool `json:"mayRename"`
+	MayDelete      bool `json:"mayDelete"`
+	MaySubmit      bool `json:"maySubmit"`
+}
+
+// CanRemove reports whether emails may be moved out of or deleted from the
+// mailbox
+func (m *Mailbox) CanRemove() bool {
+	return m.Rights == nil || m.Rights.MayRemoveItems
+}
+
+// CanAdd reports whether emails may be moved into the mailbox
+func (m *Mailbox) CanAdd() bool {
+	return m.Rights == nil || m.Rights.MayAddItems
+}
+
+// CanSetSeen reports whether emails in the mailbox may be marked read or
+// unread
+func (m *Mailbox) CanSetSeen() bool {
+	return m.Rights == nil || m.Rights.MaySetSeen
+}
+
+// CanSetKeywords reports whether emails in the mailbox may be flagged or
+// tagged
+func (m *Mailbox) CanSetKeywords() bool {
+	return m.Rights == nil || m.Rights.MaySetKeywords
+}
+
+// IsReadOnly returns true if nothing in the mailbox can be changed
+func (m *Mailbox) IsReadOnly() bool {
+	return !m.CanRemove() && !m.CanAdd() && !m.CanSetSeen() && !m.CanSetKeywords()
+}
+
+// FollowUpMailbox returns the virtual Follow-up folder
+func FollowUpMailbox() Mailbox {
+	return Mailbox{
+		ID:   FollowUpMailboxID,
+		Name: "Follow-up",
+		Role: "followup",
+	}
+}
+
+// ScheduledMailbox returns the virtual Scheduled folder
+func ScheduledMailbox() Mailbox {
+	return Mailbox{
+		ID:   ScheduledMailboxID,
+		Name: "Scheduled",
+		Role: "scheduled",
+	}
+}
+
+// OutboxMailbox returns the virtual Outbox folder. Its messages exist only
+// locally, so it allows none of the server-side actions.
+func OutboxMailbox() Mailbox {
+	return Mailbox{
+		ID:     OutboxMailboxID,
+		Name:   "Outbox",
+		Role:   "outbox",
+		Rights: &MailboxRights{MayReadItems: true},
+	}
+}
+
+// IsVirtual returns true if the mailbox is computed client-side rather
+// than stored on the server
+func (m *Mailbox) IsVirtual() bool {
+	return IsVirtualMailboxID(m.ID)
+}
+
+// IsVirtualMailboxID returns true for the IDs of virtual folders
+func IsVirtualMailboxID(id string) bool {
+	return id == FollowUpMailboxID || id == ScheduledMailboxID || id == OutboxMailboxID
 }
 
 // IsSystem returns true if this is a system mailbox
@@ -31,6 +130,15 @@ func (m *Mailbox) DisplayName() string {
 		return "Archive"
 	case "junk":
 		return "Junk"
+	case "snoozed":
+		// Where the server keeps snoozed mail until it returns to the inbox
+		return "Snoozed"
+	case "followup":
+		return "Follow-up"
+	case "scheduled":
+		return "Scheduled"
+	case "outbox":
+		return "Outbox"
 	default:
 		return m.Name
 	}
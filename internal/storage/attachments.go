@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/the9x/anneal/internal/models"
+)
+
+// AttachmentRef is an attachment in the cache along with the email it
+// came with
+type AttachmentRef struct {
+	models.Attachment
+	EmailID    string
+	ThreadID   string
+	Subject    string
+	From       models.EmailAddress
+	ReceivedAt time.Time
+}
+
+// indexAttachments records the email's attachments, leaving out inline
+// images, in place of any recorded for it before. Called with writeMu held.
+func (s *Store) indexAttachments(email *models.Email) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM attachments WHERE email_id = ?", email.ID); err != nil {
+		return err
+	}
+	for i, att := range email.Attachments {
+		if att.IsInline || att.BlobID == "" {
+			continue
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO attachments (email_id, position, blob_id, name, type, size)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, email.ID, i, att.BlobID, att.Name, att.Type, att.Size); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetAttachments returns the attachments of the cached emails in a folder,
+// newest email first. Only emails whose body has been cached are covered.
+func (s *Store) GetAttachments(mailboxID string) ([]AttachmentRef, error) {
+	rows, err := s.db.Query(`
+		SELECT a.blob_id, a.name, a.type, a.size,
+		       e.id, e.thread_id, e.subject, e.from_json, e.received_at
+		FROM attachments a
+		JOIN emails e ON e.id = a.email_id
+		JOIN email_mailboxes m ON m.email_id = e.id
+		WHERE m.mailbox_id = ?
+		ORDER BY e.received_at DESC, a.position
+	`, mailboxID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []AttachmentRef
+	for rows.Next() {
+		var ref AttachmentRef
+		var name, typ, threadID, subject, fromJSON sql.NullString
+		var size, receivedAt sql.NullInt64
+		if err := rows.Scan(&ref.BlobID, &name, &typ, &size,
+			&ref.EmailID, &threadID, &subject, &fromJSON, &receivedAt); err != nil {
+			return nil, err
+		}
+		ref.Name = name.String
+		ref.Type = typ.String
+		ref.Size = int(size.Int64)
+		ref.ThreadID = threadID.String
+		ref.Subject = subject.String
+		ref.ReceivedAt = time.Unix(receivedAt.Int64, 0)
+		if fromJSON.Valid {
+			var from []models.EmailAddress
+			json.Unmarshal([]byte(fromJSON.String), &from)
+			if len(from) > 0 {
+				ref.From = from[0]
+			}
+		}
+		refs = append(refs, ref)
+	}
+	return refs, rows.Err()
+}
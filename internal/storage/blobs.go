@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BlobCache keeps downloaded JMAP blobs on disk. Blob IDs are immutable,
+// so a cached file never goes stale; the cache only has to stay under its
+// size limit.
+type BlobCache struct {
+	dir      string
+	maxBytes int64
+	mu       sync.Mutex
+}
+
+// NewBlobCache creates a blob cache in the cache directory (see CacheDir)
+// holding at most maxBytes. A limit of 0 or less disables eviction.
+func NewBlobCache(cacheDir string, maxBytes int64) (*BlobCache, error) {
+	dataDir, err := CacheDir(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(dataDir, "blobs")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create blob cache: %w", err)
+	}
+
+	return &BlobCache{dir: dir, maxBytes: maxBytes}, nil
+}
+
+// Path returns where a blob is stored. Each blob gets its own directory so
+// the file keeps its original name for the application that opens it.
+func (c *BlobCache) Path(blobID, name string) string {
+	name = filepath.Base(name)
+	if name == "." || name == string(filepath.Separator) || name == "" {
+		name = "attachment"
+	}
+	return filepath.Join(c.dir, sanitizeBlobID(blobID), name)
+}
+
+// Lookup returns the cached file for a blob if it exists and has the
+// expected size. A size of 0 or less skips the size check.
+func (c *BlobCache) Lookup(blobID, name string, size int64) (string, bool) {
+	path := c.Path(blobID, name)
+
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+	if size > 0 && info.Size() != size {
+		// Partial or corrupt download; fetch it again
+		os.Remove(path)
+		return "", false
+	}
+
+	// Mark as recently used so eviction keeps it
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return path, true
+}
+
+// Put writes a blob to the cache and evicts the least recently used
+// blobs if the cache grew past its limit
+func (c *BlobCache) Put(blobID, name string, data []byte) (string, error) {
+	path := c.Path(blobID, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", fmt.Errorf("failed to create blob dir: %w", err)
+	}
+
+	// Write to a temp file first so a crash never leaves a truncated blob
+	tmp := path + ".part"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to save blob: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to save blob: %w", err)
+	}
+
+	c.evict(path)
+	return path, nil
+}
+
+// Clear deletes every cached blob. The cache isn't split by account, so
+// this removes attachments downloaded by any of them.
+func (c *BlobCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("failed to clear blob cache: %w", err)
+	}
+	return os.MkdirAll(c.dir, 0700)
+}
+
+// evict removes the least recently used blobs until the cache fits its
+// limit. The blob just written is never removed.
+func (c *BlobCache) evict(keep string) {
+	if c.maxBytes <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	type entry struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	var entries []entry
+	var total int64
+	filepath.Walk(c.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		entries = append(entries, entry{path, info.Size(), info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+
+	if total <= c.maxBytes {
+		return
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+
+	for _, e := range entries {
+		if total <= c.maxBytes {
+			break
+		}
+		if e.path == keep {
+			continue
+		}
+		if err := os.Remove(e.path); err == nil {
+			total -= e.size
+			os.Remove(filepath.Dir(e.path))
+		}
+	}
+}
+
+// sanitizeBlobID makes a blob ID safe to use as a directory name
+func sanitizeBlobID(blobID string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '.':
+			return '_'
+		}
+		return r
+	}, blobID)
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/the9x/anneal/internal/models"
+)
+
+// Contact is an address seen in cached mail, with how often and how
+// recently it appeared
+type Contact struct {
+	Name     string
+	Email    string
+	Count    int
+	LastSeen time.Time
+}
+
+// SuggestContacts returns addresses from the sender and recipient lists of
+// cached emails, most frequent first and most recent among equals. Only
+// contacts whose name or address contains query are returned; an empty
+// query matches all. A limit of zero or less returns every match.
+func (s *Store) SuggestContacts(accountID, query string, limit int) ([]Contact, error) {
+	byEmail, err := s.collectContacts(`
+		SELECT from_json, to_json, cc_json, received_at
+		FROM emails
+		WHERE account_id = ?
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	contacts := make([]Contact, 0, len(byEmail))
+	for _, c := range byEmail {
+		if query != "" && !strings.Contains(c.Email, query) && !strings.Contains(strings.ToLower(c.Name), query) {
+			continue
+		}
+		contacts = append(contacts, *c)
+	}
+	sort.Slice(contacts, func(i, j int) bool {
+		if contacts[i].Count != contacts[j].Count {
+			return contacts[i].Count > contacts[j].Count
+		}
+		if !contacts[i].LastSeen.Equal(contacts[j].LastSeen) {
+			return contacts[i].LastSeen.After(contacts[j].LastSeen)
+		}
+		return contacts[i].Email < contacts[j].Email
+	})
+
+	if limit > 0 && len(contacts) > limit {
+		contacts = contacts[:limit]
+	}
+	return contacts, nil
+}
+
+// GetAllContacts returns every address in cached mail across all accounts,
+// once each, sorted by name. Addresses without a name sort by the address.
+func (s *Store) GetAllContacts() ([]models.EmailAddress, error) {
+	byEmail, err := s.collectContacts(`
+		SELECT from_json, to_json, cc_json, received_at
+		FROM emails
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := make([]models.EmailAddress, 0, len(byEmail))
+	for _, c := range byEmail {
+		contacts = append(contacts, models.EmailAddress{Name: c.Name, Email: c.Email})
+	}
+	sortKey := func(addr models.EmailAddress) string {
+		if addr.Name != "" {
+			return strings.ToLower(addr.Name)
+		}
+		return addr.Email
+	}
+	sort.Slice(contacts, func(i, j int) bool {
+		if ki, kj := sortKey(contacts[i]), sortKey(contacts[j]); ki != kj {
+			return ki < kj
+		}
+		return contacts[i].Email < contacts[j].Email
+	})
+	return contacts, nil
+}
+
+// collectContacts tallies the addresses in the sender and recipient lists
+// of the emails query selects, by lowercased address
+func (s *Store) collectContacts(query string, args ...interface{}) (map[string]*Contact, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byEmail := make(map[string]*Contact)
+	var addrs []models.EmailAddress
+	for rows.Next() {
+		var fromJSON, toJSON, ccJSON sql.NullString
+		var receivedAt sql.NullInt64
+		if err := rows.Scan(&fromJSON, &toJSON, &ccJSON, &receivedAt); err != nil {
+			return nil, err
+		}
+		seen := time.Unix(receivedAt.Int64, 0)
+
+		for _, field := range []sql.NullString{fromJSON, toJSON, ccJSON} {
+			if !field.Valid {
+				continue
+			}
+			addrs = addrs[:0]
+			json.Unmarshal([]byte(field.String), &addrs)
+			for _, addr := range addrs {
+				key := strings.ToLower(addr.Email)
+				if key == "" {
+					continue
+				}
+				c, ok := byEmail[key]
+				if !ok {
+					c = &Contact{Email: key}
+					byEmail[key] = c
+				}
+				c.Count++
+				if seen.After(c.LastSeen) {
+					c.LastSeen = seen
+					// Keep the name from the newest message that has one
+					if addr.Name != "" {
+						c.Name = addr.Name
+					}
+				} else if c.Name == "" {
+					c.Name = addr.Name
+				}
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return byEmail, nil
+}
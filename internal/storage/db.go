@@ -5,14 +5,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	_ "modernc.org/sqlite"
 )
 
-// Store handles all local persistence
+// Store handles all local persistence. Writes come from UI commands, the
+// background syncer and fire-and-forget goroutines at once, so they are
+// serialized here rather than left to fail with "database is locked".
 type Store struct {
-	db *sql.DB
+	db      *sql.DB
+	writeMu sync.Mutex
 }
 
 // SyncState tracks JMAP state tokens for incremental sync
@@ -23,14 +28,16 @@ type SyncState struct {
 	LastSync     time.Time
 }
 
-// New creates a new Store, initializing the database if needed
-func New() (*Store, error) {
-	dbPath, err := getDBPath()
+// New creates a new Store in the cache directory (see CacheDir),
+// initializing the database if needed
+func New(cacheDir string) (*Store, error) {
+	dbPath, err := getDBPath(cacheDir)
 	if err != nil {
 		return nil, err
 	}
 
-	db, err := sql.Open("sqlite", dbPath)
+	// Readers wait for a busy writer instead of failing immediately
+	db, err := sql.Open("sqlite", dbPath+"?_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -56,8 +63,21 @@ func (s *Store) Close() error {
 	return s.db.Close()
 }
 
-// getDBPath returns the path to the SQLite database file
-func getDBPath() (string, error) {
+// CheckWritable makes sure the cache database can take writes by starting
+// a write and rolling it back
+func (s *Store) CheckWritable() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM sync_state WHERE account_id = ''")
+	return err
+}
+
+// DataDir returns the application data directory, creating it if needed
+func DataDir() (string, error) {
 	// Use XDG data directory or fallback
 	dataDir := os.Getenv("XDG_DATA_HOME")
 	if dataDir == "" {
@@ -73,7 +93,55 @@ func getDBPath() (string, error) {
 		return "", err
 	}
 
-	return filepath.Join(appDir, "cache.db"), nil
+	return appDir, nil
+}
+
+// CacheDir returns the directory holding the cache database and the
+// attachment cache, creating it if needed: $ANNEAL_CACHE_DIR when set,
+// else the configured dir, else the data directory
+func CacheDir(configured string) (string, error) {
+	dir := configuredCacheDir(configured)
+	if dir == "" {
+		return DataDir()
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	return dir, nil
+}
+
+// TempDir returns where temporary files of the given kind go: under the
+// cache directory when one is set, so nothing lands outside it, and under
+// the system temp dir otherwise
+func TempDir(configured, kind string) string {
+	if dir := configuredCacheDir(configured); dir != "" {
+		return filepath.Join(dir, "tmp", kind)
+	}
+	return filepath.Join(os.TempDir(), "anneal", kind)
+}
+
+// configuredCacheDir returns the cache directory set by $ANNEAL_CACHE_DIR
+// or the config, with a leading ~ expanded, or "" for the default
+func configuredCacheDir(configured string) string {
+	dir := configured
+	if env := os.Getenv("ANNEAL_CACHE_DIR"); env != "" {
+		dir = env
+	}
+	if strings.HasPrefix(dir, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, dir[2:])
+		}
+	}
+	return dir
+}
+
+// getDBPath returns the path to the SQLite database file
+func getDBPath(cacheDir string) (string, error) {
+	dir, err := CacheDir(cacheDir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache.db"), nil
 }
 
 // migrate runs database migrations
@@ -98,6 +166,18 @@ func (s *Store) migrate() error {
 	// Run migrations
 	migrations := []string{
 		migration001,
+		migration002,
+		migration003,
+		migration004,
+		migration005,
+		migration006,
+		migration007,
+		migration008,
+		migration009,
+		migration010,
+		migration011,
+		migration012,
+		migration013,
 	}
 
 	for i, migration := range migrations {
@@ -184,6 +264,108 @@ CREATE INDEX IF NOT EXISTS idx_emails_received ON emails(received_at DESC);
 CREATE INDEX IF NOT EXISTS idx_email_mailboxes_mailbox ON email_mailboxes(mailbox_id);
 `
 
+const migration002 = `
+-- Per-sender Focused/Other overrides for the priority inbox
+CREATE TABLE IF NOT EXISTS sender_priority (
+    account_id TEXT NOT NULL,
+    email TEXT NOT NULL,
+    focused INTEGER NOT NULL,
+    PRIMARY KEY (account_id, email)
+);
+`
+
+const migration003 = `
+-- Threads pinned to the top of the list (client-side only)
+CREATE TABLE IF NOT EXISTS pinned_threads (
+    account_id TEXT NOT NULL,
+    thread_id TEXT NOT NULL,
+    pinned_at INTEGER NOT NULL,
+    PRIMARY KEY (account_id, thread_id)
+);
+`
+
+const migration004 = `
+-- Follow-up ($todo keyword) flag
+ALTER TABLE emails ADD COLUMN is_todo INTEGER DEFAULT 0;
+`
+
+const migration005 = `
+-- Mailbox permissions (JMAP myRights) as JSON, NULL when unknown
+ALTER TABLE mailboxes ADD COLUMN my_rights TEXT;
+`
+
+const migration006 = `
+-- Messages that failed to send, kept for retry (client-side only)
+CREATE TABLE IF NOT EXISTS outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id TEXT NOT NULL,
+    message TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    updated_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_account ON outbox(account_id);
+`
+
+const migration007 = `
+-- Autosaved compose content that couldn't reach the server, one per account
+CREATE TABLE IF NOT EXISTS local_drafts (
+    account_id TEXT PRIMARY KEY,
+    message TEXT NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+`
+
+const migration008 = `
+-- When the sender sent the message (JMAP sentAt), 0 when unknown
+ALTER TABLE emails ADD COLUMN sent_at INTEGER DEFAULT 0;
+`
+
+const migration009 = `
+-- Whether the body is signed or encrypted, empty for plain mail
+ALTER TABLE email_bodies ADD COLUMN security TEXT;
+`
+
+const migration010 = `
+-- X-Priority/Importance: 1 high, 0 normal or unknown, -1 low
+ALTER TABLE emails ADD COLUMN priority INTEGER DEFAULT 0;
+`
+
+const migration011 = `
+-- Sender header as JSON, fetched with the body; NULL when not sent
+ALTER TABLE email_bodies ADD COLUMN sender_json TEXT;
+`
+
+const migration012 = `
+-- Attachments of cached bodies, for listing every attachment in a folder
+CREATE TABLE IF NOT EXISTS attachments (
+    email_id TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    blob_id TEXT NOT NULL,
+    name TEXT,
+    type TEXT,
+    size INTEGER,
+    PRIMARY KEY (email_id, position)
+);
+
+-- Index the bodies already cached
+INSERT OR IGNORE INTO attachments (email_id, position, blob_id, name, type, size)
+SELECT b.email_id, j.key, json_extract(j.value, '$.BlobID'), json_extract(j.value, '$.Name'),
+       json_extract(j.value, '$.Type'), json_extract(j.value, '$.Size')
+FROM email_bodies b, json_each(b.attachments_json) j
+WHERE json_valid(b.attachments_json) AND NOT json_extract(j.value, '$.IsInline');
+`
+
+const migration013 = `
+-- Snoozes kept locally when the server has no snooze of its own
+CREATE TABLE IF NOT EXISTS snoozes (
+    email_id TEXT PRIMARY KEY,
+    account_id TEXT NOT NULL,
+    until INTEGER NOT NULL,
+    return_mailbox_id TEXT NOT NULL
+);
+`
+
 // GetSyncState retrieves the sync state for an account
 func (s *Store) GetSyncState(accountID string) (*SyncState, error) {
 	row := s.db.QueryRow(`
@@ -212,6 +394,9 @@ func (s *Store) GetSyncState(accountID string) (*SyncState, error) {
 
 // SaveSyncState saves the sync state for an account
 func (s *Store) SaveSyncState(state *SyncState) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	_, err := s.db.Exec(`
 		INSERT OR REPLACE INTO sync_state (account_id, mailbox_state, email_state, last_sync)
 		VALUES (?, ?, ?, ?)
@@ -219,13 +404,68 @@ func (s *Store) SaveSyncState(state *SyncState) error {
 	return err
 }
 
+// DeleteAccount removes everything cached for an account: mail, bodies,
+// mailboxes, sync state and the client-side pins, overrides, outbox and
+// drafts. The database is compacted afterwards so the deleted rows don't
+// linger on disk.
+func (s *Store) DeleteAccount(accountID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Bodies and folder links are keyed by email, so go through emails first
+	statements := []string{
+		"DELETE FROM email_bodies WHERE email_id IN (SELECT id FROM emails WHERE account_id = ?)",
+		"DELETE FROM attachments WHERE email_id IN (SELECT id FROM emails WHERE account_id = ?)",
+		"DELETE FROM email_mailboxes WHERE email_id IN (SELECT id FROM emails WHERE account_id = ?)",
+		"DELETE FROM emails WHERE account_id = ?",
+		"DELETE FROM mailboxes WHERE account_id = ?",
+		"DELETE FROM sync_state WHERE account_id = ?",
+		"DELETE FROM sender_priority WHERE account_id = ?",
+		"DELETE FROM pinned_threads WHERE account_id = ?",
+		"DELETE FROM outbox WHERE account_id = ?",
+		"DELETE FROM local_drafts WHERE account_id = ?",
+		"DELETE FROM snoozes WHERE account_id = ?",
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt, accountID); err != nil {
+			return fmt.Errorf("failed to delete cached data: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return s.compact()
+}
+
+// compact rewrites the database without its free pages and empties the
+// write-ahead log, so deleted mail isn't left readable in either file
+func (s *Store) compact() error {
+	if _, err := s.db.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("failed to compact cache: %w", err)
+	}
+	if _, err := s.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("failed to compact cache: %w", err)
+	}
+	return nil
+}
+
 // ClearCache removes all cached data (for debugging/reset)
 func (s *Store) ClearCache() error {
-	tables := []string{"email_bodies", "email_mailboxes", "emails", "mailboxes", "sync_state"}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	tables := []string{"email_bodies", "attachments", "email_mailboxes", "emails", "mailboxes", "sync_state", "sender_priority", "pinned_threads", "outbox", "local_drafts", "snoozes"}
 	for _, table := range tables {
 		if _, err := s.db.Exec("DELETE FROM " + table); err != nil {
 			return err
 		}
 	}
-	return nil
+	return s.compact()
 }
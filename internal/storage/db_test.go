@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/the9x/anneal/internal/models"
+)
+
+// newTestStore opens a store on a fresh database in a temp dir
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	t.Setenv("ANNEAL_CACHE_DIR", "")
+	store, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	store := newTestStore(t)
+
+	const workers, rounds = 8, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*rounds*4)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				email := models.Email{
+					ID:         fmt.Sprintf("e%d-%d", w, i),
+					ThreadID:   fmt.Sprintf("t%d", w),
+					MailboxIDs: []string{"inbox"},
+					Subject:    "hello",
+					From:       []models.EmailAddress{{Email: "a@example.com"}},
+					ReceivedAt: time.Unix(int64(i), 0),
+					IsUnread:   true,
+					TextBody:   "body",
+					Attachments: []models.Attachment{
+						{BlobID: fmt.Sprintf("b%d-%d", w, i), Name: "a.pdf", Size: 10},
+					},
+				}
+				errs <- store.SaveEmails("acct", []models.Email{email})
+				errs <- store.SaveEmailBody(&email)
+				errs <- store.UpdateEmailFlags(email.ID, i%2 == 0, i%3 == 0)
+				_, err := store.GetEmails("inbox", 50)
+				errs <- err
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err == nil {
+			continue
+		}
+		if strings.Contains(err.Error(), "SQLITE_BUSY") || strings.Contains(err.Error(), "database is locked") {
+			t.Fatalf("concurrent write failed with a busy database: %v", err)
+		}
+		t.Fatalf("concurrent write failed: %v", err)
+	}
+
+	emails, err := store.GetEmails("inbox", workers*rounds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(emails) != workers*rounds {
+		t.Errorf("got %d emails after concurrent writes, want %d", len(emails), workers*rounds)
+	}
+}
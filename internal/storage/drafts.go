@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// SaveLocalDraft keeps autosaved compose content while the server can't be
+// reached. Each account holds one, replaced on every save.
+func (s *Store) SaveLocalDraft(accountID string, msg OutboxMessage) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+		INSERT OR REPLACE INTO local_drafts (account_id, message, updated_at)
+		VALUES (?, ?, ?)
+	`, accountID, string(data), time.Now().Unix())
+	return err
+}
+
+// GetLocalDraft returns the account's locally saved draft, or nil if there
+// is none
+func (s *Store) GetLocalDraft(accountID string) (*OutboxMessage, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT message FROM local_drafts WHERE account_id = ?`, accountID).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var msg OutboxMessage
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// DeleteLocalDraft removes the account's locally saved draft
+func (s *Store) DeleteLocalDraft(accountID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	_, err := s.db.Exec(`DELETE FROM local_drafts WHERE account_id = ?`, accountID)
+	return err
+}
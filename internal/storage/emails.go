@@ -3,6 +3,7 @@ package storage
 import (
 	"database/sql"
 	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/the9x/anneal/internal/models"
@@ -12,7 +13,7 @@ import (
 func (s *Store) GetEmails(mailboxID string, limit int) ([]models.Email, error) {
 	rows, err := s.db.Query(`
 		SELECT e.id, e.thread_id, e.subject, e.preview, e.from_json, e.to_json, e.cc_json,
-		       e.reply_to_json, e.received_at, e.size, e.is_unread, e.is_flagged, e.is_draft, e.has_attachment
+		       e.reply_to_json, e.received_at, e.size, e.is_unread, e.is_flagged, e.is_draft, e.has_attachment, e.is_todo, e.sent_at, e.priority
 		FROM emails e
 		JOIN email_mailboxes em ON e.id = em.email_id
 		WHERE em.mailbox_id = ?
@@ -31,7 +32,7 @@ func (s *Store) GetEmails(mailboxID string, limit int) ([]models.Email, error) {
 func (s *Store) GetEmailsByThread(threadID string) ([]models.Email, error) {
 	rows, err := s.db.Query(`
 		SELECT id, thread_id, subject, preview, from_json, to_json, cc_json,
-		       reply_to_json, received_at, size, is_unread, is_flagged, is_draft, has_attachment
+		       reply_to_json, received_at, size, is_unread, is_flagged, is_draft, has_attachment, is_todo, sent_at, priority
 		FROM emails
 		WHERE thread_id = ?
 		ORDER BY received_at ASC
@@ -50,23 +51,27 @@ func (s *Store) scanEmails(rows *sql.Rows) ([]models.Email, error) {
 	for rows.Next() {
 		var e models.Email
 		var fromJSON, toJSON, ccJSON, replyToJSON sql.NullString
-		var receivedAt int64
-		var isUnread, isFlagged, isDraft, hasAttachment int
+		var receivedAt, sentAt int64
+		var isUnread, isFlagged, isDraft, hasAttachment, isTodo int
 
 		err := rows.Scan(
 			&e.ID, &e.ThreadID, &e.Subject, &e.Preview,
 			&fromJSON, &toJSON, &ccJSON, &replyToJSON,
-			&receivedAt, &e.Size, &isUnread, &isFlagged, &isDraft, &hasAttachment,
+			&receivedAt, &e.Size, &isUnread, &isFlagged, &isDraft, &hasAttachment, &isTodo, &sentAt, &e.Priority,
 		)
 		if err != nil {
 			return nil, err
 		}
 
 		e.ReceivedAt = time.Unix(receivedAt, 0)
+		if sentAt != 0 {
+			e.SentAt = time.Unix(sentAt, 0)
+		}
 		e.IsUnread = isUnread == 1
 		e.IsFlagged = isFlagged == 1
 		e.IsDraft = isDraft == 1
 		e.HasAttachment = hasAttachment == 1
+		e.IsTodo = isTodo == 1
 
 		// Parse JSON address fields
 		if fromJSON.Valid {
@@ -90,17 +95,41 @@ func (s *Store) scanEmails(rows *sql.Rows) ([]models.Email, error) {
 
 // SaveEmails saves emails and their mailbox associations
 func (s *Store) SaveEmails(accountID string, emails []models.Email) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
+	// List fetches don't carry the headers priority is read from, so an
+	// unknown (0) priority keeps the one saved with the full headers
 	emailStmt, err := tx.Prepare(`
-		INSERT OR REPLACE INTO emails
+		INSERT INTO emails
 		(id, account_id, thread_id, subject, preview, from_json, to_json, cc_json, reply_to_json,
-		 received_at, size, is_unread, is_flagged, is_draft, has_attachment, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 received_at, size, is_unread, is_flagged, is_draft, has_attachment, is_todo, sent_at, priority, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			account_id = excluded.account_id,
+			thread_id = excluded.thread_id,
+			subject = excluded.subject,
+			preview = excluded.preview,
+			from_json = excluded.from_json,
+			to_json = excluded.to_json,
+			cc_json = excluded.cc_json,
+			reply_to_json = excluded.reply_to_json,
+			received_at = excluded.received_at,
+			size = excluded.size,
+			is_unread = excluded.is_unread,
+			is_flagged = excluded.is_flagged,
+			is_draft = excluded.is_draft,
+			has_attachment = excluded.has_attachment,
+			is_todo = excluded.is_todo,
+			sent_at = excluded.sent_at,
+			priority = COALESCE(NULLIF(excluded.priority, 0), emails.priority),
+			updated_at = excluded.updated_at
 	`)
 	if err != nil {
 		return err
@@ -139,11 +168,19 @@ func (s *Store) SaveEmails(accountID string, emails []models.Email) error {
 		if e.HasAttachment {
 			hasAttachment = 1
 		}
+		isTodo := 0
+		if e.IsTodo {
+			isTodo = 1
+		}
+		var sentAt int64
+		if !e.SentAt.IsZero() {
+			sentAt = e.SentAt.Unix()
+		}
 
 		_, err := emailStmt.Exec(
 			e.ID, accountID, e.ThreadID, e.Subject, e.Preview,
 			string(fromJSON), string(toJSON), string(ccJSON), string(replyToJSON),
-			e.ReceivedAt.Unix(), e.Size, isUnread, isFlagged, isDraft, hasAttachment, now,
+			e.ReceivedAt.Unix(), e.Size, isUnread, isFlagged, isDraft, hasAttachment, isTodo, sentAt, e.Priority, now,
 		)
 		if err != nil {
 			return err
@@ -164,8 +201,8 @@ func (s *Store) SaveEmails(accountID string, emails []models.Email) error {
 func (s *Store) GetEmailBody(emailID string) (*models.Email, error) {
 	row := s.db.QueryRow(`
 		SELECT e.id, e.thread_id, e.subject, e.preview, e.from_json, e.to_json, e.cc_json,
-		       e.reply_to_json, e.received_at, e.size, e.is_unread, e.is_flagged, e.is_draft, e.has_attachment,
-		       b.text_body, b.html_body, b.attachments_json
+		       e.reply_to_json, e.received_at, e.size, e.is_unread, e.is_flagged, e.is_draft, e.has_attachment, e.is_todo, e.sent_at, e.priority,
+		       b.text_body, b.html_body, b.attachments_json, b.security, b.sender_json
 		FROM emails e
 		LEFT JOIN email_bodies b ON e.id = b.email_id
 		WHERE e.id = ?
@@ -173,15 +210,15 @@ func (s *Store) GetEmailBody(emailID string) (*models.Email, error) {
 
 	var e models.Email
 	var fromJSON, toJSON, ccJSON, replyToJSON sql.NullString
-	var textBody, htmlBody, attachmentsJSON sql.NullString
-	var receivedAt int64
-	var isUnread, isFlagged, isDraft, hasAttachment int
+	var textBody, htmlBody, attachmentsJSON, security, senderJSON sql.NullString
+	var receivedAt, sentAt int64
+	var isUnread, isFlagged, isDraft, hasAttachment, isTodo int
 
 	err := row.Scan(
 		&e.ID, &e.ThreadID, &e.Subject, &e.Preview,
 		&fromJSON, &toJSON, &ccJSON, &replyToJSON,
-		&receivedAt, &e.Size, &isUnread, &isFlagged, &isDraft, &hasAttachment,
-		&textBody, &htmlBody, &attachmentsJSON,
+		&receivedAt, &e.Size, &isUnread, &isFlagged, &isDraft, &hasAttachment, &isTodo, &sentAt, &e.Priority,
+		&textBody, &htmlBody, &attachmentsJSON, &security, &senderJSON,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -191,10 +228,14 @@ func (s *Store) GetEmailBody(emailID string) (*models.Email, error) {
 	}
 
 	e.ReceivedAt = time.Unix(receivedAt, 0)
+	if sentAt != 0 {
+		e.SentAt = time.Unix(sentAt, 0)
+	}
 	e.IsUnread = isUnread == 1
 	e.IsFlagged = isFlagged == 1
 	e.IsDraft = isDraft == 1
 	e.HasAttachment = hasAttachment == 1
+	e.IsTodo = isTodo == 1
 
 	if fromJSON.Valid {
 		json.Unmarshal([]byte(fromJSON.String), &e.From)
@@ -208,6 +249,9 @@ func (s *Store) GetEmailBody(emailID string) (*models.Email, error) {
 	if replyToJSON.Valid {
 		json.Unmarshal([]byte(replyToJSON.String), &e.ReplyTo)
 	}
+	if senderJSON.Valid {
+		json.Unmarshal([]byte(senderJSON.String), &e.Sender)
+	}
 
 	if textBody.Valid {
 		e.TextBody = textBody.String
@@ -218,18 +262,40 @@ func (s *Store) GetEmailBody(emailID string) (*models.Email, error) {
 	if attachmentsJSON.Valid {
 		json.Unmarshal([]byte(attachmentsJSON.String), &e.Attachments)
 	}
+	e.Security = security.String
 
 	return &e, nil
 }
 
 // SaveEmailBody saves the full body for an email
 func (s *Store) SaveEmailBody(email *models.Email) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	attachmentsJSON, _ := json.Marshal(email.Attachments)
+	var senderJSON sql.NullString
+	if len(email.Sender) > 0 {
+		data, _ := json.Marshal(email.Sender)
+		senderJSON = sql.NullString{String: string(data), Valid: true}
+	}
 
 	_, err := s.db.Exec(`
-		INSERT OR REPLACE INTO email_bodies (email_id, text_body, html_body, attachments_json, fetched_at)
-		VALUES (?, ?, ?, ?, ?)
-	`, email.ID, email.TextBody, email.HTMLBody, string(attachmentsJSON), time.Now().Unix())
+		INSERT OR REPLACE INTO email_bodies (email_id, text_body, html_body, attachments_json, security, sender_json, fetched_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, email.ID, email.TextBody, email.HTMLBody, string(attachmentsJSON), email.Security, senderJSON, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+	if err := s.indexAttachments(email); err != nil {
+		return err
+	}
+	if email.Priority == models.PriorityNormal {
+		return nil
+	}
+
+	// Bodies are fetched with headers, so they know the priority even when
+	// the list wasn't
+	_, err = s.db.Exec("UPDATE emails SET priority = ? WHERE id = ?", email.Priority, email.ID)
 	return err
 }
 
@@ -242,6 +308,9 @@ func (s *Store) HasEmailBody(emailID string) (bool, error) {
 
 // DeleteEmail removes an email from the cache
 func (s *Store) DeleteEmail(emailID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
@@ -251,6 +320,9 @@ func (s *Store) DeleteEmail(emailID string) error {
 	if _, err := tx.Exec("DELETE FROM email_bodies WHERE email_id = ?", emailID); err != nil {
 		return err
 	}
+	if _, err := tx.Exec("DELETE FROM attachments WHERE email_id = ?", emailID); err != nil {
+		return err
+	}
 	if _, err := tx.Exec("DELETE FROM email_mailboxes WHERE email_id = ?", emailID); err != nil {
 		return err
 	}
@@ -263,6 +335,9 @@ func (s *Store) DeleteEmail(emailID string) error {
 
 // UpdateEmailMailboxes updates the mailbox associations for an email
 func (s *Store) UpdateEmailMailboxes(emailID string, mailboxIDs []string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
@@ -292,6 +367,9 @@ func (s *Store) UpdateEmailMailboxes(emailID string, mailboxIDs []string) error
 
 // UpdateEmailFlags updates read/flagged status
 func (s *Store) UpdateEmailFlags(emailID string, isUnread, isFlagged bool) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	unread := 0
 	if isUnread {
 		unread = 1
@@ -308,8 +386,46 @@ func (s *Store) UpdateEmailFlags(emailID string, isUnread, isFlagged bool) error
 	return err
 }
 
+// GetTodoEmails retrieves emails tagged for follow-up across all mailboxes
+func (s *Store) GetTodoEmails(accountID string, limit int) ([]models.Email, error) {
+	rows, err := s.db.Query(`
+		SELECT id, thread_id, subject, preview, from_json, to_json, cc_json,
+		       reply_to_json, received_at, size, is_unread, is_flagged, is_draft, has_attachment, is_todo, sent_at, priority
+		FROM emails
+		WHERE account_id = ? AND is_todo = 1
+		ORDER BY received_at DESC
+		LIMIT ?
+	`, accountID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return s.scanEmails(rows)
+}
+
+// SetEmailTodo updates the follow-up flag
+func (s *Store) SetEmailTodo(emailID string, todo bool) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	isTodo := 0
+	if todo {
+		isTodo = 1
+	}
+
+	_, err := s.db.Exec(`
+		UPDATE emails SET is_todo = ?, updated_at = ?
+		WHERE id = ?
+	`, isTodo, time.Now().Unix(), emailID)
+	return err
+}
+
 // PurgeOldBodies removes bodies older than the given duration
 func (s *Store) PurgeOldBodies(olderThan time.Duration) (int64, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	cutoff := time.Now().Add(-olderThan).Unix()
 	result, err := s.db.Exec("DELETE FROM email_bodies WHERE fetched_at < ?", cutoff)
 	if err != nil {
@@ -317,3 +433,38 @@ func (s *Store) PurgeOldBodies(olderThan time.Duration) (int64, error) {
 	}
 	return result.RowsAffected()
 }
+
+// GetEmailIDsFrom returns the cached emails sent from an address, leaving
+// out those already in skipMailboxID
+func (s *Store) GetEmailIDsFrom(accountID, addr, skipMailboxID string) ([]string, error) {
+	// The LIKE narrows the scan; the exact match happens on the decoded JSON
+	rows, err := s.db.Query(`
+		SELECT e.id, e.from_json FROM emails e
+		WHERE e.account_id = ? AND lower(e.from_json) LIKE ?
+		AND NOT EXISTS (
+			SELECT 1 FROM email_mailboxes em WHERE em.email_id = e.id AND em.mailbox_id = ?
+		)
+	`, accountID, "%"+strings.ToLower(addr)+"%", skipMailboxID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		var fromJSON sql.NullString
+		if err := rows.Scan(&id, &fromJSON); err != nil {
+			return nil, err
+		}
+		var from []models.EmailAddress
+		json.Unmarshal([]byte(fromJSON.String), &from)
+		for _, f := range from {
+			if strings.EqualFold(f.Email, addr) {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	return ids, rows.Err()
+}
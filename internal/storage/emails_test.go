@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/the9x/anneal/internal/models"
+)
+
+func TestSaveEmailsKeepsPriority(t *testing.T) {
+	store := newTestStore(t)
+
+	email := models.Email{ID: "e1", ThreadID: "t1", MailboxIDs: []string{"inbox"}, Subject: "urgent"}
+	if err := store.SaveEmails("acct", []models.Email{email}); err != nil {
+		t.Fatal(err)
+	}
+	full := email
+	full.TextBody = "body"
+	full.Priority = models.PriorityHigh
+	if err := store.SaveEmailBody(&full); err != nil {
+		t.Fatal(err)
+	}
+
+	// A list refresh without the priority headers
+	email.Subject = "urgent (updated)"
+	if err := store.SaveEmails("acct", []models.Email{email}); err != nil {
+		t.Fatal(err)
+	}
+
+	emails, err := store.GetEmails("inbox", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(emails) != 1 {
+		t.Fatalf("got %d emails, want 1", len(emails))
+	}
+	if emails[0].Priority != models.PriorityHigh {
+		t.Errorf("priority after a list refresh = %d, want %d", emails[0].Priority, models.PriorityHigh)
+	}
+	if emails[0].Subject != "urgent (updated)" {
+		t.Errorf("subject = %q, the refresh wasn't saved", emails[0].Subject)
+	}
+	if cached, err := store.GetEmailBody("e1"); err != nil || cached == nil || cached.TextBody != "body" {
+		t.Errorf("body lost after a list refresh: %+v, %v", cached, err)
+	}
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"github.com/the9x/anneal/internal/jmap"
+	"github.com/the9x/anneal/internal/models"
+)
+
+// SetMarkJunkRead sets whether mail arriving in the Junk folder is marked
+// read during sync
+func (s *Syncer) SetMarkJunkRead(on bool) {
+	s.markJunkRead = on
+}
+
+// markJunkArrivalsRead marks emails that just arrived in the Junk folder
+// read, in one request, so spam doesn't add to the unread counts. Mail the
+// server already marked read is left alone, as is junk the user marks
+// unread later, since only created emails are looked at. The emails are
+// updated in place; failures are logged and leave them unread.
+func (s *Syncer) markJunkArrivalsRead(accountID string, emails []models.Email, created []string) int {
+	if !s.markJunkRead || len(created) == 0 {
+		return 0
+	}
+
+	mailboxes, err := s.store.GetMailboxes(accountID)
+	if err != nil {
+		return 0
+	}
+	var junkID string
+	for _, mb := range mailboxes {
+		if mb.Role == "junk" {
+			junkID = mb.ID
+		}
+	}
+	if junkID == "" {
+		return 0
+	}
+
+	isNew := make(map[string]bool, len(created))
+	for _, id := range created {
+		isNew[id] = true
+	}
+	var ids []string
+	for _, e := range emails {
+		if isNew[e.ID] && e.IsUnread && containsID(e.MailboxIDs, junkID) {
+			ids = append(ids, e.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return 0
+	}
+
+	if err := s.client.SetEmailsKeywords(ids, map[string]bool{"$seen": true}); err != nil {
+		jmap.Logf("junk: %v", err)
+		return 0
+	}
+	read := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		read[id] = true
+	}
+	for i := range emails {
+		if read[emails[i].ID] {
+			emails[i].IsUnread = false
+		}
+	}
+
+	// The Junk count was refreshed before this sync; bring it up to date
+	s.refreshMailboxCounts(accountID, junkID)
+	return len(ids)
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/the9x/anneal/internal/models"
@@ -9,7 +10,7 @@ import (
 // GetMailboxes retrieves all mailboxes for an account
 func (s *Store) GetMailboxes(accountID string) ([]models.Mailbox, error) {
 	rows, err := s.db.Query(`
-		SELECT id, name, role, parent_id, total_emails, unread_count, sort_order
+		SELECT id, name, role, parent_id, total_emails, unread_count, sort_order, my_rights
 		FROM mailboxes
 		WHERE account_id = ?
 		ORDER BY sort_order, name
@@ -22,9 +23,9 @@ func (s *Store) GetMailboxes(accountID string) ([]models.Mailbox, error) {
 	var mailboxes []models.Mailbox
 	for rows.Next() {
 		var mb models.Mailbox
-		var role, parentID *string
+		var role, parentID, rights *string
 
-		err := rows.Scan(&mb.ID, &mb.Name, &role, &parentID, &mb.TotalEmails, &mb.UnreadCount, &mb.SortOrder)
+		err := rows.Scan(&mb.ID, &mb.Name, &role, &parentID, &mb.TotalEmails, &mb.UnreadCount, &mb.SortOrder, &rights)
 		if err != nil {
 			return nil, err
 		}
@@ -35,6 +36,12 @@ func (s *Store) GetMailboxes(accountID string) ([]models.Mailbox, error) {
 		if parentID != nil {
 			mb.ParentID = *parentID
 		}
+		if rights != nil {
+			var r models.MailboxRights
+			if json.Unmarshal([]byte(*rights), &r) == nil {
+				mb.Rights = &r
+			}
+		}
 
 		mailboxes = append(mailboxes, mb)
 	}
@@ -44,6 +51,9 @@ func (s *Store) GetMailboxes(accountID string) ([]models.Mailbox, error) {
 
 // SaveMailboxes saves mailboxes for an account (replaces existing)
 func (s *Store) SaveMailboxes(accountID string, mailboxes []models.Mailbox) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
@@ -57,8 +67,8 @@ func (s *Store) SaveMailboxes(accountID string, mailboxes []models.Mailbox) erro
 
 	// Insert new mailboxes
 	stmt, err := tx.Prepare(`
-		INSERT INTO mailboxes (id, account_id, name, role, parent_id, total_emails, unread_count, sort_order, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO mailboxes (id, account_id, name, role, parent_id, total_emails, unread_count, sort_order, my_rights, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return err
@@ -75,7 +85,7 @@ func (s *Store) SaveMailboxes(accountID string, mailboxes []models.Mailbox) erro
 			parentID = &mb.ParentID
 		}
 
-		_, err := stmt.Exec(mb.ID, accountID, mb.Name, role, parentID, mb.TotalEmails, mb.UnreadCount, mb.SortOrder, now)
+		_, err := stmt.Exec(mb.ID, accountID, mb.Name, role, parentID, mb.TotalEmails, mb.UnreadCount, mb.SortOrder, encodeRights(mb.Rights), now)
 		if err != nil {
 			return err
 		}
@@ -86,6 +96,9 @@ func (s *Store) SaveMailboxes(accountID string, mailboxes []models.Mailbox) erro
 
 // UpdateMailbox updates a single mailbox
 func (s *Store) UpdateMailbox(accountID string, mb models.Mailbox) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	var role, parentID *string
 	if mb.Role != "" {
 		role = &mb.Role
@@ -95,14 +108,47 @@ func (s *Store) UpdateMailbox(accountID string, mb models.Mailbox) error {
 	}
 
 	_, err := s.db.Exec(`
-		INSERT OR REPLACE INTO mailboxes (id, account_id, name, role, parent_id, total_emails, unread_count, sort_order, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, mb.ID, accountID, mb.Name, role, parentID, mb.TotalEmails, mb.UnreadCount, mb.SortOrder, time.Now().Unix())
+		INSERT OR REPLACE INTO mailboxes (id, account_id, name, role, parent_id, total_emails, unread_count, sort_order, my_rights, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, mb.ID, accountID, mb.Name, role, parentID, mb.TotalEmails, mb.UnreadCount, mb.SortOrder, encodeRights(mb.Rights), time.Now().Unix())
 	return err
 }
 
+// UpdateMailboxCounts sets a cached mailbox's message counts, reporting
+// whether they differed from what was cached
+func (s *Store) UpdateMailboxCounts(accountID, mailboxID string, total, unread int) (bool, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	res, err := s.db.Exec(`
+		UPDATE mailboxes SET total_emails = ?, unread_count = ?, updated_at = ?
+		WHERE id = ? AND account_id = ? AND (total_emails != ? OR unread_count != ?)
+	`, total, unread, time.Now().Unix(), mailboxID, accountID, total, unread)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// encodeRights stores mailbox rights as JSON, or NULL when unknown
+func encodeRights(rights *models.MailboxRights) *string {
+	if rights == nil {
+		return nil
+	}
+	data, err := json.Marshal(rights)
+	if err != nil {
+		return nil
+	}
+	s := string(data)
+	return &s
+}
+
 // DeleteMailbox removes a mailbox
 func (s *Store) DeleteMailbox(mailboxID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	_, err := s.db.Exec("DELETE FROM mailboxes WHERE id = ?", mailboxID)
 	return err
 }
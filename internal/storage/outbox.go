@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// OutboxMessage is everything needed to send a message again
+type OutboxMessage struct {
+	To         []string `json:"to"`
+	CC         []string `json:"cc,omitempty"`
+	Subject    string   `json:"subject"`
+	Body       string   `json:"body"`
+	InReplyTo  []string `json:"inReplyTo,omitempty"`
+	IdentityID string   `json:"identityId,omitempty"`
+	FromName   string   `json:"fromName,omitempty"`
+
+	// Attachments are the paths of files to attach, uploaded on each try
+	Attachments []string `json:"attachments,omitempty"`
+}
+
+// OutboxItem is a message that failed to send
+type OutboxItem struct {
+	ID        int64
+	Message   OutboxMessage
+	Attempts  int
+	LastError string
+	UpdatedAt time.Time
+}
+
+// GetOutbox returns the failed messages for an account, most recent first
+func (s *Store) GetOutbox(accountID string) ([]OutboxItem, error) {
+	rows, err := s.db.Query(`
+		SELECT id, message, attempts, last_error, updated_at
+		FROM outbox WHERE account_id = ?
+		ORDER BY updated_at DESC
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []OutboxItem
+	for rows.Next() {
+		var item OutboxItem
+		var message string
+		var lastError sql.NullString
+		var updatedAt int64
+		if err := rows.Scan(&item.ID, &message, &item.Attempts, &lastError, &updatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(message), &item.Message); err != nil {
+			continue // Unreadable rows can't be retried anyway
+		}
+		item.LastError = lastError.String
+		item.UpdatedAt = time.Unix(updatedAt, 0)
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// RecordSendFailure stores a message that failed to send. An id of 0 adds
+// it to the outbox; otherwise the existing entry's message is replaced and
+// its attempt count goes up. Returns the entry's ID.
+func (s *Store) RecordSendFailure(accountID string, id int64, msg OutboxMessage, sendErr error) (int64, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now().Unix()
+
+	if id != 0 {
+		res, err := s.db.Exec(`
+			UPDATE outbox SET message = ?, attempts = attempts + 1, last_error = ?, updated_at = ?
+			WHERE id = ? AND account_id = ?
+		`, string(data), sendErr.Error(), now, id, accountID)
+		if err != nil {
+			return 0, err
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			return id, nil
+		}
+		// Discarded meanwhile; keep the message anyway
+	}
+
+	res, err := s.db.Exec(`
+		INSERT INTO outbox (account_id, message, attempts, last_error, updated_at)
+		VALUES (?, ?, 1, ?, ?)
+	`, accountID, string(data), sendErr.Error(), now)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// DeleteOutboxItem removes a message from the outbox once it was sent or
+// discarded
+func (s *Store) DeleteOutboxItem(accountID string, id int64) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	_, err := s.db.Exec(`
+		DELETE FROM outbox WHERE id = ? AND account_id = ?
+	`, id, accountID)
+	return err
+}
@@ -0,0 +1,44 @@
+package storage
+
+import "time"
+
+// GetPinnedThreads returns the IDs of pinned threads for an account
+func (s *Store) GetPinnedThreads(accountID string) (map[string]bool, error) {
+	rows, err := s.db.Query(`
+		SELECT thread_id FROM pinned_threads WHERE account_id = ?
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pinned := make(map[string]bool)
+	for rows.Next() {
+		var threadID string
+		if err := rows.Scan(&threadID); err != nil {
+			return nil, err
+		}
+		pinned[threadID] = true
+	}
+
+	return pinned, rows.Err()
+}
+
+// SetThreadPinned pins or unpins a thread
+func (s *Store) SetThreadPinned(accountID, threadID string, pinned bool) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if !pinned {
+		_, err := s.db.Exec(`
+			DELETE FROM pinned_threads WHERE account_id = ? AND thread_id = ?
+		`, accountID, threadID)
+		return err
+	}
+
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO pinned_threads (account_id, thread_id, pinned_at)
+		VALUES (?, ?, ?)
+	`, accountID, threadID, time.Now().Unix())
+	return err
+}
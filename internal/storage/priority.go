@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/the9x/anneal/internal/models"
+)
+
+// GetRepliedAddresses returns the lowercased addresses the user has sent
+// mail to, based on cached emails in the sent mailbox
+func (s *Store) GetRepliedAddresses(accountID string) (map[string]bool, error) {
+	rows, err := s.db.Query(`
+		SELECT e.to_json, e.cc_json
+		FROM emails e
+		JOIN email_mailboxes em ON e.id = em.email_id
+		JOIN mailboxes m ON m.id = em.mailbox_id
+		WHERE e.account_id = ? AND m.role = 'sent'
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	addresses := make(map[string]bool)
+	for rows.Next() {
+		var toJSON, ccJSON sql.NullString
+		if err := rows.Scan(&toJSON, &ccJSON); err != nil {
+			return nil, err
+		}
+
+		var addrs []models.EmailAddress
+		for _, field := range []sql.NullString{toJSON, ccJSON} {
+			if !field.Valid {
+				continue
+			}
+			addrs = addrs[:0]
+			json.Unmarshal([]byte(field.String), &addrs)
+			for _, addr := range addrs {
+				addresses[strings.ToLower(addr.Email)] = true
+			}
+		}
+	}
+
+	return addresses, rows.Err()
+}
+
+// GetSenderPriorities returns the user's Focused/Other overrides by sender
+func (s *Store) GetSenderPriorities(accountID string) (map[string]bool, error) {
+	rows, err := s.db.Query(`
+		SELECT email, focused FROM sender_priority WHERE account_id = ?
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	priorities := make(map[string]bool)
+	for rows.Next() {
+		var email string
+		var focused int
+		if err := rows.Scan(&email, &focused); err != nil {
+			return nil, err
+		}
+		priorities[email] = focused == 1
+	}
+
+	return priorities, rows.Err()
+}
+
+// SetSenderPriority records whether mail from a sender belongs in Focused
+func (s *Store) SetSenderPriority(accountID, email string, focused bool) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	value := 0
+	if focused {
+		value = 1
+	}
+
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO sender_priority (account_id, email, focused)
+		VALUES (?, ?, ?)
+	`, accountID, strings.ToLower(email), value)
+	return err
+}
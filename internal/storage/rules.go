@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"strings"
+
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/jmap"
+	"github.com/the9x/anneal/internal/models"
+)
+
+// SetRules sets the filtering rules applied to newly arrived mail
+func (s *Syncer) SetRules(rules []config.Rule) {
+	s.rules = rules
+}
+
+// applyRules runs the filtering rules over emails that just arrived in the
+// inbox, batching the resulting moves and keyword changes into as few
+// requests as possible. The emails are updated in place so the cache
+// matches the server. Failures are logged and leave the mail where it is.
+func (s *Syncer) applyRules(accountID string, emails []models.Email, created []string) int {
+	if len(s.rules) == 0 || len(created) == 0 {
+		return 0
+	}
+
+	mailboxes, err := s.store.GetMailboxes(accountID)
+	if err != nil {
+		return 0
+	}
+	var inboxID string
+	for _, mb := range mailboxes {
+		if mb.Role == "inbox" {
+			inboxID = mb.ID
+		}
+	}
+	if inboxID == "" {
+		return 0
+	}
+
+	isNew := make(map[string]bool, len(created))
+	for _, id := range created {
+		isNew[id] = true
+	}
+
+	moves := make(map[string][]string) // target mailbox ID -> email IDs
+	var markRead, flag []string
+	fired := make(map[string]*config.Rule)
+	for i := range emails {
+		e := &emails[i]
+		if !isNew[e.ID] || e.IsDraft || !containsID(e.MailboxIDs, inboxID) {
+			continue
+		}
+		for r := range s.rules {
+			rule := &s.rules[r]
+			if !rule.Matches(e) {
+				continue
+			}
+			jmap.Logf("rule %q fired for %q from %s", rule.Label(), e.Subject, e.FromDisplay())
+			fired[e.ID] = rule
+
+			target := ""
+			if rule.Archive {
+				target = findMailbox(mailboxes, "archive")
+			}
+			if rule.MoveTo != "" {
+				target = findMailbox(mailboxes, rule.MoveTo)
+				if target == "" {
+					jmap.Logf("rule %q: no mailbox named %q", rule.Label(), rule.MoveTo)
+				}
+			}
+			if target != "" && target != inboxID {
+				moves[target] = append(moves[target], e.ID)
+			}
+			if rule.MarkRead && e.IsUnread {
+				markRead = append(markRead, e.ID)
+			}
+			if rule.Flag && !e.IsFlagged {
+				flag = append(flag, e.ID)
+			}
+			break
+		}
+	}
+
+	moved := make(map[string]string)
+	for target, ids := range moves {
+		if err := s.client.MoveEmails(ids, target); err != nil {
+			jmap.Logf("rules: %v", err)
+			continue
+		}
+		for _, id := range ids {
+			moved[id] = target
+		}
+	}
+	read := make(map[string]bool)
+	if err := s.client.SetEmailsKeywords(markRead, map[string]bool{"$seen": true}); err != nil {
+		jmap.Logf("rules: %v", err)
+	} else {
+		for _, id := range markRead {
+			read[id] = true
+		}
+	}
+	flagged := make(map[string]bool)
+	if err := s.client.SetEmailsKeywords(flag, map[string]bool{"$flagged": true}); err != nil {
+		jmap.Logf("rules: %v", err)
+	} else {
+		for _, id := range flag {
+			flagged[id] = true
+		}
+	}
+
+	for i := range emails {
+		e := &emails[i]
+		if target, ok := moved[e.ID]; ok {
+			e.MailboxIDs = []string{target}
+		}
+		if read[e.ID] {
+			e.IsUnread = false
+		}
+		if flagged[e.ID] {
+			e.IsFlagged = true
+		}
+	}
+
+	return len(fired)
+}
+
+// findMailbox returns the ID of the mailbox with the given role, or else
+// the given name, compared without case
+func findMailbox(mailboxes []models.Mailbox, nameOrRole string) string {
+	for _, mb := range mailboxes {
+		if strings.EqualFold(mb.Role, nameOrRole) {
+			return mb.ID
+		}
+	}
+	for _, mb := range mailboxes {
+		if strings.EqualFold(mb.Name, nameOrRole) {
+			return mb.ID
+		}
+	}
+	return ""
+}
+
+func containsID(ids []string, id string) bool {
+	for _, x := range ids {
+		if x == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/the9x/anneal/internal/jmap"
+)
+
+// Snooze is an email snoozed locally, to be moved back at Until
+type Snooze struct {
+	EmailID         string
+	Until           time.Time
+	ReturnMailboxID string
+}
+
+// SaveSnoozes records emails snoozed until the given time, to be moved back
+// to returnMailboxID when it comes
+func (s *Store) SaveSnoozes(accountID string, emailIDs []string, until time.Time, returnMailboxID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, id := range emailIDs {
+		if _, err := tx.Exec(`
+			INSERT OR REPLACE INTO snoozes (email_id, account_id, until, return_mailbox_id)
+			VALUES (?, ?, ?, ?)
+		`, id, accountID, until.Unix(), returnMailboxID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// DueSnoozes returns the local snoozes for an account whose time has come
+func (s *Store) DueSnoozes(accountID string, now time.Time) ([]Snooze, error) {
+	rows, err := s.db.Query(`
+		SELECT email_id, until, return_mailbox_id FROM snoozes
+		WHERE account_id = ? AND until <= ?
+	`, accountID, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []Snooze
+	for rows.Next() {
+		var sn Snooze
+		var until int64
+		if err := rows.Scan(&sn.EmailID, &until, &sn.ReturnMailboxID); err != nil {
+			return nil, err
+		}
+		sn.Until = time.Unix(until, 0)
+		due = append(due, sn)
+	}
+	return due, rows.Err()
+}
+
+// DeleteSnoozes forgets the local snoozes of the given emails
+func (s *Store) DeleteSnoozes(emailIDs []string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	for _, id := range emailIDs {
+		if _, err := s.db.Exec("DELETE FROM snoozes WHERE email_id = ?", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wakeSnoozed moves locally snoozed emails whose time has come back to the
+// mailbox they were snoozed from, unread, in one request per mailbox.
+// Snoozes that fail to wake stay recorded and are tried on the next sync.
+func (s *Syncer) wakeSnoozed(accountID string) {
+	due, err := s.store.DueSnoozes(accountID, time.Now())
+	if err != nil || len(due) == 0 {
+		return
+	}
+
+	byMailbox := make(map[string][]string)
+	for _, sn := range due {
+		byMailbox[sn.ReturnMailboxID] = append(byMailbox[sn.ReturnMailboxID], sn.EmailID)
+	}
+
+	for mailboxID, ids := range byMailbox {
+		if err := s.client.MoveEmails(ids, mailboxID); err != nil {
+			jmap.Logf("snooze: %v", err)
+			continue
+		}
+		if err := s.client.SetEmailsKeywords(ids, map[string]bool{"$seen": false}); err != nil {
+			jmap.Logf("snooze: %v", err)
+		}
+		if err := s.store.DeleteSnoozes(ids); err != nil {
+			jmap.Logf("snooze: %v", err)
+		}
+		jmap.Logf("snooze: woke %d emails", len(ids))
+	}
+}
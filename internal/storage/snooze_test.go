@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDueSnoozes(t *testing.T) {
+	store := newTestStore(t)
+	now := time.Now()
+
+	if err := store.SaveSnoozes("acct", []string{"a", "b"}, now.Add(-time.Minute), "inbox"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveSnoozes("acct", []string{"c"}, now.Add(time.Hour), "inbox"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveSnoozes("other", []string{"d"}, now.Add(-time.Minute), "inbox"); err != nil {
+		t.Fatal(err)
+	}
+
+	due, err := store.DueSnoozes("acct", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 2 || due[0].ReturnMailboxID != "inbox" {
+		t.Fatalf("due snoozes = %+v, want a and b", due)
+	}
+
+	if err := store.DeleteSnoozes([]string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if due, _ := store.DueSnoozes("acct", now.Add(2*time.Hour)); len(due) != 1 || due[0].EmailID != "c" {
+		t.Errorf("after waking, due snoozes = %+v, want only c", due)
+	}
+}
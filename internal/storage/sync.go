@@ -3,6 +3,7 @@ package storage
 import (
 	"time"
 
+	"github.com/the9x/anneal/internal/config"
 	"github.com/the9x/anneal/internal/jmap"
 	"github.com/the9x/anneal/internal/models"
 )
@@ -11,13 +12,25 @@ import (
 type Syncer struct {
 	store  *Store
 	client *jmap.Client
+	rules  []config.Rule
+
+	// markJunkRead marks mail arriving in the Junk folder read
+	markJunkRead bool
+
+	// Email details are fetched batchSize at a time, with up to
+	// concurrency requests in flight
+	batchSize   int
+	concurrency int
+	progress    syncProgress
 }
 
 // NewSyncer creates a new syncer
 func NewSyncer(store *Store, client *jmap.Client) *Syncer {
 	return &Syncer{
-		store:  store,
-		client: client,
+		store:       store,
+		client:      client,
+		batchSize:   defaultSyncBatchSize,
+		concurrency: defaultSyncConcurrency,
 	}
 }
 
@@ -29,10 +42,14 @@ type SyncResult struct {
 	EmailsCreated      int
 	EmailsUpdated      int
 	EmailsDestroyed    int
+	RulesApplied       int // new emails filed by a rule
+	JunkMarkedRead     int // new emails in Junk marked read
 }
 
-// SyncMailboxes synchronizes mailboxes with the server
-func (s *Syncer) SyncMailboxes() (*SyncResult, error) {
+// SyncMailboxes synchronizes mailboxes with the server. The counts of
+// activeID, the open folder, are always refreshed: after actions taken
+// offline they can be off even when the server reports no changes.
+func (s *Syncer) SyncMailboxes(activeID string) (*SyncResult, error) {
 	accountID := s.client.AccountID()
 	result := &SyncResult{}
 
@@ -80,6 +97,12 @@ func (s *Syncer) SyncMailboxes() (*SyncResult, error) {
 		result.MailboxesUpdated = len(changes.Updated)
 	}
 
+	if activeID != "" && !models.IsVirtualMailboxID(activeID) && !containsID(idsToFetch, activeID) {
+		if s.refreshMailboxCounts(accountID, activeID) {
+			result.MailboxesUpdated++
+		}
+	}
+
 	// Update sync state
 	state.MailboxState = changes.NewState
 	state.LastSync = time.Now()
@@ -90,6 +113,27 @@ func (s *Syncer) SyncMailboxes() (*SyncResult, error) {
 	return result, nil
 }
 
+// refreshMailboxCounts fetches a mailbox's counts and caches them,
+// reporting whether they changed. Failures are only logged since the rest
+// of the sync still stands.
+func (s *Syncer) refreshMailboxCounts(accountID, mailboxID string) bool {
+	counts, err := s.client.GetMailboxCounts([]string{mailboxID})
+	if err != nil {
+		jmap.Logf("mailbox counts: %v", err)
+		return false
+	}
+	c, ok := counts[mailboxID]
+	if !ok {
+		return false
+	}
+	changed, err := s.store.UpdateMailboxCounts(accountID, mailboxID, c.TotalEmails, c.UnreadCount)
+	if err != nil {
+		jmap.Logf("mailbox counts: %v", err)
+		return false
+	}
+	return changed
+}
+
 // fullMailboxSync does a complete mailbox sync
 func (s *Syncer) fullMailboxSync(accountID string) (*SyncResult, error) {
 	result := &SyncResult{}
@@ -134,6 +178,9 @@ func (s *Syncer) SyncEmails(mailboxID string, limit int) (*SyncResult, error) {
 		return s.fullEmailSync(accountID, mailboxID, limit)
 	}
 
+	// Snoozes due now move back first, so the changes below include them
+	s.wakeSnoozed(accountID)
+
 	// Try incremental sync
 	changes, err := s.client.GetEmailChanges(state.EmailState)
 	if err != nil {
@@ -152,11 +199,14 @@ func (s *Syncer) SyncEmails(mailboxID string, limit int) (*SyncResult, error) {
 	// Handle created and updated emails
 	idsToFetch := append(changes.Created, changes.Updated...)
 	if len(idsToFetch) > 0 {
-		emails, err := s.client.GetEmailsByIDs(idsToFetch)
+		emails, err := s.fetchInBatches(idsToFetch, 0)
 		if err != nil {
 			return nil, err
 		}
 
+		result.RulesApplied = s.applyRules(accountID, emails, changes.Created)
+		result.JunkMarkedRead = s.markJunkArrivalsRead(accountID, emails, changes.Created)
+
 		if err := s.store.SaveEmails(accountID, emails); err != nil {
 			return nil, err
 		}
@@ -175,14 +225,28 @@ func (s *Syncer) SyncEmails(mailboxID string, limit int) (*SyncResult, error) {
 	return result, nil
 }
 
-// fullEmailSync does a complete email sync for a mailbox
+// fullEmailSync does a complete email sync for a mailbox. The first batch
+// comes with the state to sync from later; the IDs of the rest are then
+// looked up and their details fetched in batches.
 func (s *Syncer) fullEmailSync(accountID, mailboxID string, limit int) (*SyncResult, error) {
 	result := &SyncResult{}
 
-	emails, newState, err := s.client.EmailsWithState(mailboxID, limit)
+	first := min(limit, s.batchSize)
+	emails, newState, err := s.client.EmailsWithState(mailboxID, first)
 	if err != nil {
 		return nil, err
 	}
+	if limit > first && len(emails) == first {
+		ids, err := s.client.QueryEmailIDs(mailboxID, first, limit-first)
+		if err != nil {
+			return nil, err
+		}
+		rest, err := s.fetchInBatches(ids, first)
+		if err != nil {
+			return nil, err
+		}
+		emails = append(emails, rest...)
+	}
 
 	if err := s.store.SaveEmails(accountID, emails); err != nil {
 		return nil, err
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/the9x/anneal/internal/models"
+)
+
+// Defaults for how email details are fetched during a sync
+const (
+	defaultSyncBatchSize   = 50
+	defaultSyncConcurrency = 4
+)
+
+// syncProgress counts the emails fetched by a sync in progress
+type syncProgress struct {
+	done  atomic.Int64
+	total atomic.Int64
+}
+
+// SetBatching sets how many emails are fetched per request during a sync
+// and how many of those requests run at once. Values below 1 use the
+// defaults.
+func (s *Syncer) SetBatching(batchSize, concurrency int) {
+	if batchSize < 1 {
+		batchSize = defaultSyncBatchSize
+	}
+	if concurrency < 1 {
+		concurrency = defaultSyncConcurrency
+	}
+	s.batchSize = batchSize
+	s.concurrency = concurrency
+}
+
+// Progress returns how many emails the running sync has fetched out of
+// how many it expects; total is 0 when no sync is fetching in batches
+func (s *Syncer) Progress() (done, total int) {
+	return int(s.progress.done.Load()), int(s.progress.total.Load())
+}
+
+// fetchInBatches fetches the details of the emails with the given IDs,
+// batchSize at a time with up to concurrency requests in flight. already
+// is how many were fetched before, for the progress count. The emails
+// come back in the order of ids.
+func (s *Syncer) fetchInBatches(ids []string, already int) ([]models.Email, error) {
+	var batches [][]string
+	for start := 0; start < len(ids); start += s.batchSize {
+		batches = append(batches, ids[start:min(start+s.batchSize, len(ids))])
+	}
+
+	s.progress.done.Store(int64(already))
+	s.progress.total.Store(int64(already + len(ids)))
+	defer func() {
+		s.progress.done.Store(0)
+		s.progress.total.Store(0)
+	}()
+
+	return s.runBatches(batches, s.client.GetEmailsByIDsWithContext)
+}
+
+// runBatches fetches each batch with up to concurrency requests in flight.
+// The first failure cancels the requests still running and stops any more
+// from starting, and is the error returned.
+func (s *Syncer) runBatches(batches [][]string, fetch func(context.Context, []string) ([]models.Email, error)) ([]models.Email, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := make([][]models.Email, len(batches))
+	var (
+		failOnce sync.Once
+		failErr  error
+	)
+	sem := make(chan struct{}, s.concurrency)
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		sem <- struct{}{}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			emails, err := fetch(ctx, batch)
+			if err != nil {
+				failOnce.Do(func() {
+					failErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = emails
+			s.progress.done.Add(int64(len(batch)))
+		}()
+	}
+	wg.Wait()
+
+	if failErr != nil {
+		return nil, failErr
+	}
+	var emails []models.Email
+	for _, batch := range results {
+		emails = append(emails, batch...)
+	}
+	return emails, nil
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/the9x/anneal/internal/models"
+)
+
+func TestRunBatchesCancelsOnFailure(t *testing.T) {
+	s := &Syncer{concurrency: 2}
+	batches := [][]string{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}}
+	failure := errors.New("server error")
+
+	var started atomic.Int32
+	fetch := func(ctx context.Context, ids []string) ([]models.Email, error) {
+		started.Add(1)
+		if ids[0] == "a" {
+			return nil, failure
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(5 * time.Second):
+			return []models.Email{{ID: ids[0]}}, nil
+		}
+	}
+
+	begin := time.Now()
+	_, err := s.runBatches(batches, fetch)
+	if !errors.Is(err, failure) {
+		t.Errorf("err = %v, want the failed batch's error", err)
+	}
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Errorf("waited %s for batches that should have been cancelled", elapsed)
+	}
+	if n := started.Load(); n != 2 {
+		t.Errorf("%d batches started, want only the 2 in flight when one failed", n)
+	}
+}
+
+func TestRunBatchesKeepsOrder(t *testing.T) {
+	s := &Syncer{concurrency: 3}
+	batches := [][]string{{"a", "b"}, {"c"}, {"d", "e"}}
+	fetch := func(_ context.Context, ids []string) ([]models.Email, error) {
+		var emails []models.Email
+		for _, id := range ids {
+			emails = append(emails, models.Email{ID: id})
+		}
+		return emails, nil
+	}
+
+	emails, err := s.runBatches(batches, fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	for _, e := range emails {
+		got += e.ID
+	}
+	if got != "abcde" {
+		t.Errorf("emails in order %q, want abcde", got)
+	}
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// NextAccount returns the email of the account the user switched to, or
+// "" if the app quit without switching. The caller connects to it and
+// starts a new app.
+func (a *App) NextAccount() string {
+	return a.nextAccount
+}
+
+// handleAccountKeys switches to one of the first five accounts by number
+// and opens the account picker for the rest. ok is false for other keys.
+func (a *App) handleAccountKeys(msg tea.KeyMsg) (cmd tea.Cmd, ok bool) {
+	switch {
+	case key.Matches(msg, a.keys.Accounts):
+		return a.openAccountPicker(), true
+	case key.Matches(msg, a.keys.Account1):
+		return a.switchAccount(0), true
+	case key.Matches(msg, a.keys.Account2):
+		return a.switchAccount(1), true
+	case key.Matches(msg, a.keys.Account3):
+		return a.switchAccount(2), true
+	case key.Matches(msg, a.keys.Account4):
+		return a.switchAccount(3), true
+	case key.Matches(msg, a.keys.Account5):
+		return a.switchAccount(4), true
+	}
+	return nil, false
+}
+
+// switchAccount quits so the account at position i is opened in its place
+func (a *App) switchAccount(i int) tea.Cmd {
+	account := a.cfg.AccountAt(i)
+	if account == nil {
+		return a.showToast(fmt.Sprintf("No account %d configured", i+1), toastInfo, 3*time.Second)
+	}
+	if strings.EqualFold(account.Email, a.client.Email()) {
+		return a.showToast("Already on "+account.Email, toastInfo, 3*time.Second)
+	}
+	a.nextAccount = account.Email
+	return tea.Quit
+}
+
+// openAccountPicker lists every configured account to switch between
+func (a *App) openAccountPicker() tea.Cmd {
+	if len(a.cfg.Accounts) < 2 {
+		return a.showToast("Only one account configured", toastInfo, 3*time.Second)
+	}
+	a.accountPicker = true
+	a.accountSelected = 0
+	for i, account := range a.cfg.Accounts {
+		if strings.EqualFold(account.Email, a.client.Email()) {
+			a.accountSelected = i
+		}
+	}
+	return nil
+}
+
+// handleAccountPickerKeys moves through the accounts; enter switches to
+// the selected one
+func (a *App) handleAccountPickerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if a.accountSelected > 0 {
+			a.accountSelected--
+		}
+	case "down", "j":
+		if a.accountSelected < len(a.cfg.Accounts)-1 {
+			a.accountSelected++
+		}
+	case "enter":
+		a.accountPicker = false
+		return a, a.switchAccount(a.accountSelected)
+	case "esc", "q", "A":
+		a.accountPicker = false
+	}
+	return a, nil
+}
+
+// renderAccountPicker renders the accounts in place of the main pane
+func (a *App) renderAccountPicker(width int) string {
+	label := lipgloss.NewStyle().Foreground(ColorDim)
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render("◇ accounts"))
+	b.WriteString("\n\n")
+
+	for i, account := range a.cfg.Accounts {
+		number := "  "
+		if i < 5 {
+			number = fmt.Sprintf("%d ", i+1)
+		}
+		line := fmt.Sprintf("  %s%s <%s>", number, account.Name, account.Email)
+		if account.Name == "" {
+			line = fmt.Sprintf("  %s%s", number, account.Email)
+		}
+		if strings.EqualFold(account.Email, a.client.Email()) {
+			line += "  (open)"
+		}
+		style := lipgloss.NewStyle().Foreground(ColorSecondary)
+		if i == a.accountSelected {
+			line = "▶" + line[1:]
+			style = lipgloss.NewStyle().Foreground(ColorPrimary).Background(ColorBgSelect)
+		}
+		b.WriteString(style.MaxWidth(width-4).Render(line) + "\n")
+	}
+
+	b.WriteString("\n" + label.Render("enter: switch  esc: close"))
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Padding(1, 2).
+		Render(b.String())
+}
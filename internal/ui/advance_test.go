@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/jmap"
+	"github.com/the9x/anneal/internal/models"
+)
+
+func TestAdvanceSelection(t *testing.T) {
+	tests := []struct {
+		advance  string
+		selected int
+		want     int
+	}{
+		{config.AdvanceNext, 1, 2},
+		{config.AdvanceNext, 2, 1},
+		{config.AdvancePrevious, 1, 0},
+		{config.AdvancePrevious, 0, 1},
+		{config.AdvanceStay, 1, 1},
+		{config.AdvanceStay, 2, 2},
+	}
+	for _, tt := range tests {
+		cfg := config.DefaultConfig()
+		cfg.AdvanceAfterAction = tt.advance
+		a := &App{cfg: cfg, threads: []Thread{{ID: "t0"}, {ID: "t1"}, {ID: "t2"}}}
+		a.viewState = ViewMessages
+		a.selectedThread = tt.selected
+
+		a.advanceSelection()
+		if a.selectedThread != tt.want {
+			t.Errorf("%s from row %d: selected %d, want %d", tt.advance, tt.selected, a.selectedThread, tt.want)
+		}
+		if tt.advance == config.AdvanceStay && a.followThreadID != "" {
+			t.Errorf("stay follows thread %q", a.followThreadID)
+		}
+	}
+}
+
+func TestArchiveFromReaderLeavesNextUnread(t *testing.T) {
+	a := &App{cfg: config.DefaultConfig(), client: &jmap.Client{}, keys: DefaultKeyMap(), leaving: map[string]bool{}}
+	a.threads = []Thread{
+		{ID: "t0", Emails: []models.Email{{ID: "a", ThreadID: "t0"}}},
+		{ID: "t1", Emails: []models.Email{{ID: "b", ThreadID: "t1", IsUnread: true}}},
+	}
+	a.viewState = ViewEmail
+	a.currentEmail = &a.threads[0].Emails[0]
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if a.viewState != ViewMessages {
+		t.Errorf("view after archiving from the reader = %v, want the list", a.viewState)
+	}
+	if a.selectedThread != 1 {
+		t.Errorf("selected thread %d, want the next one", a.selectedThread)
+	}
+	if a.loading || a.currentEmail != nil || !a.threads[1].Emails[0].IsUnread {
+		t.Error("archiving opened the next thread")
+	}
+}
@@ -2,17 +2,22 @@ package ui
 
 import (
 	"fmt"
+	"hash/fnv"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/i18n"
 	"github.com/the9x/anneal/internal/jmap"
 	"github.com/the9x/anneal/internal/models"
 	"github.com/the9x/anneal/internal/storage"
@@ -42,6 +47,54 @@ type Thread struct {
 	From      string
 	UnreadCnt int
 	Expanded  bool
+	Pinned    bool
+
+	// ServerCount is how many emails the server has in this conversation,
+	// or 0 if not yet checked. It can exceed len(Emails) when members live
+	// in other folders or haven't been synced.
+	ServerCount int
+}
+
+// size returns the total size of the thread's loaded emails in bytes
+func (t Thread) size() int {
+	total := 0
+	for _, e := range t.Emails {
+		total += e.Size
+	}
+	return total
+}
+
+// latest returns the thread's most recently received email. The thread
+// must have at least one.
+func (t Thread) latest() models.Email {
+	latest := t.Emails[0]
+	for _, e := range t.Emails[1:] {
+		if e.ReceivedAt.After(latest.ReceivedAt) {
+			latest = e
+		}
+	}
+	return latest
+}
+
+// isHighPriority returns true if any of the thread's emails was sent as
+// high priority
+func (t Thread) isHighPriority() bool {
+	for _, e := range t.Emails {
+		if e.Priority == models.PriorityHigh {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAttachment returns true if any of the thread's emails has attachments
+func (t Thread) hasAttachment() bool {
+	for _, e := range t.Emails {
+		if e.HasAttachment {
+			return true
+		}
+	}
+	return false
 }
 
 // App is the main application model
@@ -49,6 +102,7 @@ type App struct {
 	cfg       *config.Config
 	client    *jmap.Client
 	store     *storage.Store
+	blobs     *storage.BlobCache
 	syncer    *storage.Syncer
 	keys      KeyMap
 	help      help.Model
@@ -59,11 +113,13 @@ type App struct {
 	loading   bool
 	syncing   bool // Background sync in progress
 	err       error
+	retry     tea.Cmd // Reissues the load that failed with err, if any
 
 	// Data
 	mailboxes       []models.Mailbox
 	selectedMailbox int
 	emails          []models.Email
+	emailsMailbox   string // Mailbox emails was loaded from
 	threads         []Thread
 	selectedThread  int
 	selectedInThread int
@@ -78,6 +134,141 @@ type App struct {
 
 	// State for compose
 	prevViewState ViewState // Where to return after compose
+
+	// Reader scroll positions, keyed by email ID
+	scrollMemory map[string]scrollPosition
+
+	// Priority inbox: which section is shown and the classifier's data
+	showOther      bool
+	repliedSenders map[string]bool
+	senderPriority map[string]bool
+
+	// Thread IDs pinned to the top of the list
+	pinnedThreads map[string]bool
+
+	// Inline filter over the loaded threads
+	filtering   bool
+	filterInput textinput.Model
+	filterQuery string
+
+	// Find in the open email
+	finding   bool
+	findInput textinput.Model
+
+	// Current time for the status bar clock
+	now time.Time
+
+	// Multi-selection of thread IDs and the last bulk move, for undo
+	marked   map[string]bool
+	lastBulk *bulkAction
+
+	// Archived threads shown struck through until the list refreshes
+	leaving map[string]bool
+
+	// Emails that came in with the last refresh, marked until the
+	// selection moves or the marker times out
+	arrived     map[string]bool
+	arrivalsGen int
+
+	// Bumped each time an email opens, so a pending mark-read for one
+	// that was left early is dropped
+	markReadGen int
+
+	// Show own replies in full in the thread view despite collapse_own_replies
+	expandOwnReplies bool
+
+	// One-time code detected in the open email
+	otpCode string
+
+	// Previews built from cached bodies, by email ID
+	bodyPreviews map[string]string
+
+	// Sort the message list by thread size instead of date
+	sortBySize bool
+
+	// Held submissions by email ID, while the Scheduled folder is open
+	scheduled map[string]jmap.ScheduledSend
+
+	// Failed sends by list ID while the Outbox is open, and the one being
+	// edited in compose
+	outbox        map[string]storage.OutboxItem
+	composeOutbox *storage.OutboxItem
+
+	// Autosave of the open compose: the content last saved and a note on
+	// when, whether a save is in flight, a compose sent while its save
+	// was still running, whose draft must go once the save lands, and one
+	// closed while its save was running, saved again once the save lands
+	draftSnapshot string
+	draftStatus   string
+	draftSaving   bool
+	sentCompose   *views.ComposeView
+	closedDraft   *pendingDraft
+
+	// Thread whose grouping is shown in the debug overlay
+	debugThreadID string
+
+	// Thread to reselect by ID once the list refreshes after an action
+	followThreadID string
+
+	// Transient message shown over the status bar
+	toast    *toast
+	toastSeq int
+
+	// Problem found before the UI started, shown once it's up
+	startupWarning string
+
+	// Open yes/no question, answered before any other key is handled
+	confirm *confirmation
+
+	// Blocked senders list, shown in place of the main pane
+	blockedList     bool
+	blockedSelected int
+
+	// Recipient picker over the compose form, listing cached contacts
+	contactPicker   bool
+	contacts        []storage.Contact
+	contactSelected int
+	contactChecked  map[string]bool
+
+	// Second folder beside the message list, for moving mail across
+	split *splitPane
+
+	// Account picker, and the account to open once the app quits
+	accountPicker   bool
+	accountSelected int
+	nextAccount     string
+
+	// Links of the open email, listed in place of the reader while set
+	links        []string
+	linkSelected int
+
+	// Folder picker for moving mail, shown in place of the main pane
+	move *movePicker
+
+	// Wake times for snoozing a conversation, in place of the main pane
+	snooze *snoozePicker
+
+	// transcript shows the open conversation as one document
+	transcript *transcript
+
+	// attachments lists the attachments found in the open folder
+	attachments *folderAttachments
+
+	// g was just pressed; a role letter before this time jumps to that
+	// folder
+	goPendingUntil time.Time
+
+	// File browser over the compose form for picking attachments, and
+	// the directory the last one came from
+	fileBrowser *views.FileBrowserView
+	attachDir   string
+}
+
+// scrollPosition remembers where the reader was left for an email
+type scrollPosition struct {
+	offset   int
+	bodyHash uint64
+	savedAt  time.Time
 }
 
 // NewApp creates a new application instance
@@ -89,28 +280,78 @@ func NewApp(cfg *config.Config, client *jmap.Client, store *storage.Store) *App
 	var syncer *storage.Syncer
 	if store != nil {
 		syncer = storage.NewSyncer(store, client)
+		syncer.SetRules(cfg.Rules)
+		syncer.SetMarkJunkRead(cfg.MarkJunkRead)
+		syncer.SetBatching(cfg.SyncBatchSize, cfg.SyncConcurrency)
+	}
+
+	// Attachments still open from a temp file if the cache can't be created
+	var blobs *storage.BlobCache
+	if !cfg.CacheDisabled() {
+		blobs, _ = storage.NewBlobCache(cfg.CacheDir, int64(cfg.BlobCacheMB)<<20)
+	}
+
+	keys := DefaultKeyMap()
+	if !client.SupportsSubmission() {
+		// Hide compose and reply bindings on servers that can't send
+		keys.Compose.SetEnabled(false)
+		keys.Reply.SetEnabled(false)
+		keys.ReplyAll.SetEnabled(false)
+		keys.Forward.SetEnabled(false)
 	}
 
 	return &App{
 		cfg:       cfg,
 		client:    client,
 		store:     store,
+		blobs:     blobs,
 		syncer:    syncer,
-		keys:      DefaultKeyMap(),
+		keys:      keys,
 		help:      help.New(),
 		spinner:   s,
 		viewState: ViewFolders,
 		loading:   true,
+
+		scrollMemory:  make(map[string]scrollPosition),
+		pinnedThreads: make(map[string]bool),
+		filterInput:   newFilterInput(),
+		findInput:     newFindInput(),
+		marked:        make(map[string]bool),
+		leaving:       make(map[string]bool),
+		bodyPreviews:  make(map[string]string),
+		now:           time.Now(),
 	}
 }
 
 // Init initializes the application
 func (a *App) Init() tea.Cmd {
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		a.spinner.Tick,
 		a.loadMailboxesCacheFirst,
 		a.loadIdentities,
-	)
+		tickClock(),
+	}
+	if a.cfg.PriorityInbox && a.store != nil {
+		cmds = append(cmds, a.loadPriorityData)
+	}
+	if a.store != nil {
+		cmds = append(cmds, a.loadPinnedThreads, a.flushLocalDraft)
+	}
+	if a.startupWarning != "" {
+		cmds = append(cmds, a.showToast(a.startupWarning, toastError, 15*time.Second))
+	}
+	return tea.Batch(cmds...)
+}
+
+// SetStartupWarning shows a message, such as a config problem, when the UI
+// starts
+func (a *App) SetStartupWarning(msg string) {
+	a.startupWarning = msg
+}
+
+func (a *App) loadPinnedThreads() tea.Msg {
+	pinned, err := a.store.GetPinnedThreads(a.client.AccountID())
+	return pinnedThreadsLoadedMsg{pinned: pinned, err: err}
 }
 
 func (a *App) loadIdentities() tea.Msg {
@@ -122,16 +363,20 @@ func (a *App) loadIdentities() tea.Msg {
 type mailboxesLoadedMsg struct {
 	mailboxes  []models.Mailbox
 	fromCache  bool
+	outbox     bool // failed sends are waiting, so the Outbox is shown
 	err        error
 }
 
 type emailsLoadedMsg struct {
 	emails    []models.Email
+	mailboxID string
 	fromCache bool
+	fresh     bool // from loadEmailsFresh, diffed to mark new arrivals
 	err       error
 }
 
 type emailLoadedMsg struct {
+	id        string
 	email     *models.Email
 	fromCache bool
 	err       error
@@ -143,18 +388,55 @@ type syncCompleteMsg struct {
 	err           error
 }
 
+// syncProgressMsg redraws the status bar while a sync runs, so its
+// progress count keeps moving
+type syncProgressMsg struct{}
+
+// syncProgressInterval is how often the sync progress is redrawn
+const syncProgressInterval = 250 * time.Millisecond
+
 type emailActionMsg struct {
 	err error
 }
 
+// archivedMsg reports a thread archive, carrying what's needed to undo it
+type archivedMsg struct {
+	threadID string
+	action   *bulkAction
+	err      error
+}
+
 type emailSentMsg struct {
-	err error
+	sendAt time.Time // zero when sent immediately
+	queued bool      // the failed message was kept in the outbox
+	err    error
 }
 
 type attachmentOpenedMsg struct {
 	err error
 }
 
+type threadCountMsg struct {
+	threadID string
+	count    int
+	err      error
+}
+
+type threadLoadedMsg struct {
+	threadID string
+	emails   []models.Email
+	err      error
+}
+
+type pinnedThreadsLoadedMsg struct {
+	pinned map[string]bool
+	err    error
+}
+
+type threadPinnedMsg struct {
+	err error
+}
+
 type identitiesLoadedMsg struct {
 	identities []jmap.Identity
 	err        error
@@ -166,27 +448,35 @@ func (a *App) loadMailboxesCacheFirst() tea.Msg {
 	if a.syncer != nil {
 		mailboxes, err := a.syncer.GetCachedMailboxes()
 		if err == nil && len(mailboxes) > 0 {
-			return mailboxesLoadedMsg{mailboxes: mailboxes, fromCache: true, err: nil}
+			return mailboxesLoadedMsg{mailboxes: mailboxes, fromCache: true, outbox: a.hasOutbox(), err: nil}
 		}
 	}
 
 	// Fall back to network
 	mailboxes, err := a.client.GetMailboxes()
-	return mailboxesLoadedMsg{mailboxes: mailboxes, fromCache: false, err: err}
+	return mailboxesLoadedMsg{mailboxes: mailboxes, fromCache: false, outbox: a.hasOutbox(), err: err}
 }
 
 func (a *App) loadMailboxes() tea.Msg {
 	mailboxes, err := a.client.GetMailboxes()
-	return mailboxesLoadedMsg{mailboxes: mailboxes, fromCache: false, err: err}
+	return mailboxesLoadedMsg{mailboxes: mailboxes, fromCache: false, outbox: a.hasOutbox(), err: err}
 }
 
 func (a *App) loadEmails(mailboxID string) tea.Cmd {
+	switch mailboxID {
+	case models.FollowUpMailboxID:
+		return a.loadFollowUps()
+	case models.ScheduledMailboxID:
+		return a.loadScheduled()
+	case models.OutboxMailboxID:
+		return a.loadOutbox()
+	}
 	return func() tea.Msg {
 		// Try cache first
 		if a.syncer != nil {
 			emails, err := a.syncer.GetCachedEmails(mailboxID, a.cfg.PageSize)
 			if err == nil && len(emails) > 0 {
-				return emailsLoadedMsg{emails: emails, fromCache: true, err: nil}
+				return emailsLoadedMsg{emails: emails, mailboxID: mailboxID, fromCache: true, err: nil}
 			}
 		}
 
@@ -198,12 +488,20 @@ func (a *App) loadEmails(mailboxID string) tea.Cmd {
 			a.store.SaveEmails(a.client.AccountID(), emails)
 		}
 
-		return emailsLoadedMsg{emails: emails, fromCache: false, err: err}
+		return emailsLoadedMsg{emails: emails, mailboxID: mailboxID, fromCache: false, err: err}
 	}
 }
 
 // loadEmailsFresh always fetches from network, skipping cache
 func (a *App) loadEmailsFresh(mailboxID string) tea.Cmd {
+	switch mailboxID {
+	case models.FollowUpMailboxID:
+		return a.loadFollowUps()
+	case models.ScheduledMailboxID:
+		return a.loadScheduled()
+	case models.OutboxMailboxID:
+		return a.loadOutbox()
+	}
 	return func() tea.Msg {
 		emails, err := a.client.GetEmails(mailboxID, a.cfg.PageSize)
 
@@ -212,7 +510,7 @@ func (a *App) loadEmailsFresh(mailboxID string) tea.Cmd {
 			a.store.SaveEmails(a.client.AccountID(), emails)
 		}
 
-		return emailsLoadedMsg{emails: emails, fromCache: false, err: err}
+		return emailsLoadedMsg{emails: emails, mailboxID: mailboxID, fromCache: false, fresh: true, err: err}
 	}
 }
 
@@ -222,7 +520,7 @@ func (a *App) loadEmail(emailID string) tea.Cmd {
 		if a.syncer != nil {
 			email, err := a.syncer.GetCachedEmailBody(emailID)
 			if err == nil && email != nil && (email.TextBody != "" || email.HTMLBody != "") {
-				return emailLoadedMsg{email: email, fromCache: true, err: nil}
+				return emailLoadedMsg{id: emailID, email: email, fromCache: true, err: nil}
 			}
 		}
 
@@ -234,7 +532,7 @@ func (a *App) loadEmail(emailID string) tea.Cmd {
 			a.store.SaveEmailBody(email)
 		}
 
-		return emailLoadedMsg{email: email, fromCache: false, err: err}
+		return emailLoadedMsg{id: emailID, email: email, fromCache: false, err: err}
 	}
 }
 
@@ -245,13 +543,13 @@ func (a *App) syncInBackground(mailboxID string) tea.Cmd {
 			return syncCompleteMsg{err: nil}
 		}
 
-		mailboxResult, err := a.syncer.SyncMailboxes()
+		mailboxResult, err := a.syncer.SyncMailboxes(mailboxID)
 		if err != nil {
 			return syncCompleteMsg{err: err}
 		}
 
 		var emailResult *storage.SyncResult
-		if mailboxID != "" {
+		if mailboxID != "" && !models.IsVirtualMailboxID(mailboxID) {
 			emailResult, err = a.syncer.SyncEmails(mailboxID, 100)
 		}
 
@@ -263,10 +561,37 @@ func (a *App) syncInBackground(mailboxID string) tea.Cmd {
 	}
 }
 
+// tickSyncProgress schedules the next redraw of the sync progress
+func (a *App) tickSyncProgress() tea.Cmd {
+	return tea.Tick(syncProgressInterval, func(time.Time) tea.Msg {
+		return syncProgressMsg{}
+	})
+}
+
 // convertToViewThreads converts app threads to view threads
 func (a *App) convertToViewThreads() []views.Thread {
-	viewThreads := make([]views.Thread, len(a.threads))
-	for i, t := range a.threads {
+	return a.toViewThreads(a.threads)
+}
+
+// columnLayout returns the message list layout from list_columns
+func (a *App) columnLayout() views.ColumnLayout {
+	c := a.cfg.ListColumns
+	return views.ColumnLayout{
+		FromPercent: c.FromPercent,
+		MinFrom:     c.FromMin,
+		MaxFrom:     c.FromMax,
+		Size:        c.Size,
+		Flags:       c.Flags,
+	}
+}
+
+// toViewThreads converts threads of the open folder for display
+func (a *App) toViewThreads(threads []Thread) []views.Thread {
+	viewThreads := make([]views.Thread, len(threads))
+	inScheduled := a.isInScheduled()
+	inOutbox := a.isInOutbox()
+	sent := a.isInSent()
+	for i, t := range threads {
 		viewThreads[i] = views.Thread{
 			ID:        t.ID,
 			Subject:   t.Subject,
@@ -276,17 +601,210 @@ func (a *App) convertToViewThreads() []views.Thread {
 			EmailCnt:  len(t.Emails),
 			UnreadCnt: t.UnreadCnt,
 			Expanded:  t.Expanded,
+			Pinned:    t.Pinned,
+			Todo:      t.hasTodo(),
+			Marked:    a.marked[t.ID],
+			Size:      t.size(),
+			Leaving:   a.leaving[t.ID],
+			Failed:    inOutbox,
+			New:       a.isArrival(t),
+			Priority:  t.isHighPriority(),
+
+			HasAttachment: t.hasAttachment(),
+		}
+		if format := a.cfg.ListColumns.DateFormat; format != "" && len(t.Emails) > 0 {
+			latest := t.latest()
+			viewThreads[i].Date = latest.Time(sent).Format(format)
+		}
+		// Scheduled messages show when they'll go out
+		if inScheduled && len(t.Emails) > 0 {
+			if s, ok := a.scheduled[t.Emails[0].ID]; ok {
+				viewThreads[i].Date = scheduledDate(s.SendAt)
+			}
 		}
 	}
 	return viewThreads
 }
 
+// applyThreadFilters rebuilds the visible thread list from the loaded emails
+func (a *App) applyThreadFilters() {
+	a.threads = a.groupEmailsIntoThreads(a.emails)
+	if a.priorityInboxActive() {
+		a.threads = a.filterPriority(a.threads)
+	}
+	if a.filterQuery != "" {
+		a.threads = filterThreads(a.threads, a.filterQuery)
+	}
+
+	// Largest first while sorting by size, to find mail worth cleaning up
+	if a.sortBySize {
+		sort.SliceStable(a.threads, func(i, j int) bool {
+			return a.threads[i].size() > a.threads[j].size()
+		})
+	}
+
+	// High priority mail comes next, with priority_sort
+	if a.cfg.PrioritySort {
+		sort.SliceStable(a.threads, func(i, j int) bool {
+			return a.threads[i].isHighPriority() && !a.threads[j].isHighPriority()
+		})
+	}
+
+	// Pinned threads float to the top, otherwise keeping date order
+	for i := range a.threads {
+		a.threads[i].Pinned = a.pinnedThreads[a.threads[i].ID]
+	}
+	sort.SliceStable(a.threads, func(i, j int) bool {
+		return a.threads[i].Pinned && !a.threads[j].Pinned
+	})
+}
+
+// togglePin pins or unpins a thread, keeping the selection on it
+func (a *App) togglePin(threadID string) tea.Cmd {
+	if a.store == nil {
+		return nil
+	}
+	pinned := !a.pinnedThreads[threadID]
+	if pinned {
+		a.pinnedThreads[threadID] = true
+	} else {
+		delete(a.pinnedThreads, threadID)
+	}
+
+	a.applyThreadFilters()
+	for i, t := range a.threads {
+		if t.ID == threadID {
+			a.selectedThread = i
+			break
+		}
+	}
+	a.clampThreadSelection()
+
+	accountID := a.client.AccountID()
+	return func() tea.Msg {
+		err := a.store.SetThreadPinned(accountID, threadID, pinned)
+		return threadPinnedMsg{err: err}
+	}
+}
+
+// openSelectedThread opens the selected thread: single emails go straight
+// to the reader, conversations to the thread view, or to their newest
+// email with thread_enter_behavior: open-latest
+func (a *App) openSelectedThread() tea.Cmd {
+	if len(a.threads) == 0 || a.selectedThread >= len(a.threads) {
+		return nil
+	}
+	thread := &a.threads[a.selectedThread]
+	if len(thread.Emails) == 1 {
+		// Single email thread - go directly to email. It may be the only
+		// part of a longer conversation that's in this folder.
+		a.selectedInThread = 0
+		a.loading = true
+		if thread.ServerCount == 0 {
+			return tea.Batch(a.loadEmail(thread.Emails[0].ID), a.checkThreadCount(thread.ID))
+		}
+		return a.loadEmail(thread.Emails[0].ID)
+	}
+
+	// With open-latest, enter reads the newest email and space expands
+	if a.cfg.ThreadEnterBehavior == config.ThreadEnterOpenLatest {
+		latest := 0
+		for i, e := range thread.Emails {
+			if e.ReceivedAt.After(thread.Emails[latest].ReceivedAt) {
+				latest = i
+			}
+		}
+		a.selectedInThread = latest
+		a.loading = true
+		return a.loadEmail(thread.Emails[latest].ID)
+	}
+
+	return a.expandSelectedThread()
+}
+
+// expandSelectedThread shows the selected conversation in the thread view
+func (a *App) expandSelectedThread() tea.Cmd {
+	if len(a.threads) == 0 || a.selectedThread >= len(a.threads) {
+		return nil
+	}
+	thread := &a.threads[a.selectedThread]
+
+	// Multi-email thread - expand and go to thread view
+	thread.Expanded = true
+	a.selectedInThread = 0
+	a.viewState = ViewThread
+	if thread.ServerCount == 0 {
+		return a.checkThreadCount(thread.ID)
+	}
+	return nil
+}
+
+// advanceSelection moves the selection off the thread an archive or delete
+// is about to remove, honouring the advance_after_action setting, and
+// returns to the list. The neighbour is only selected, never opened, so
+// it stays unread. Stay keeps the selected row, which the thread below
+// moves up into once the list refreshes.
+func (a *App) advanceSelection() {
+	a.viewState = ViewMessages
+	a.followThreadID = ""
+	if a.cfg.AdvanceAfterAction == config.AdvanceStay {
+		return
+	}
+
+	i := a.selectedThread
+	target := i + 1
+	if target >= len(a.threads) {
+		target = i - 1
+	}
+	if a.cfg.AdvanceAfterAction == config.AdvancePrevious {
+		target = i - 1
+		if target < 0 {
+			target = i + 1
+		}
+	}
+	if target < 0 || target >= len(a.threads) {
+		return
+	}
+
+	a.selectedThread = target
+	a.followThreadID = a.threads[target].ID
+	a.clampThreadSelection()
+}
+
+// clampThreadSelection keeps the selection inside the thread list
+func (a *App) clampThreadSelection() {
+	if a.selectedThread >= len(a.threads) {
+		a.selectedThread = len(a.threads) - 1
+	}
+	if a.selectedThread < 0 {
+		a.selectedThread = 0
+	}
+	if a.threadList != nil {
+		a.threadList.Select(a.selectedThread)
+	}
+}
+
 // groupEmailsIntoThreads groups emails by thread ID
 func (a *App) groupEmailsIntoThreads(emails []models.Email) []Thread {
 	threadMap := make(map[string]*Thread)
 	var threadOrder []string
+	sent := a.isInSent()
+
+	// Virtual folders gather mail from across the account, where a message
+	// filed in two folders would otherwise be listed twice
+	var seen map[string]bool
+	if mb := a.currentMailbox(); mb != nil && mb.IsVirtual() {
+		seen = make(map[string]bool, len(emails))
+	}
 
 	for _, email := range emails {
+		if seen != nil {
+			if seen[email.ID] {
+				continue
+			}
+			seen[email.ID] = true
+		}
+
 		tid := email.ThreadID
 		if tid == "" {
 			tid = email.ID // Fallback to email ID if no thread
@@ -299,7 +817,7 @@ func (a *App) groupEmailsIntoThreads(emails []models.Email) []Thread {
 			}
 			// Update thread date to most recent
 			if email.ReceivedAt.After(t.Emails[0].ReceivedAt) {
-				t.Date = email.DateDisplay()
+				t.Date = email.DateDisplay(sent)
 			}
 		} else {
 			threadOrder = append(threadOrder, tid)
@@ -312,7 +830,7 @@ func (a *App) groupEmailsIntoThreads(emails []models.Email) []Thread {
 				Subject:   email.Subject,
 				Emails:    []models.Email{email},
 				Preview:   email.Preview,
-				Date:      email.DateDisplay(),
+				Date:      email.DateDisplay(sent),
 				From:      email.FromDisplay(),
 				UnreadCnt: unread,
 				Expanded:  false,
@@ -340,7 +858,52 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.help.Width = msg.Width
 		return a, nil
 
+	case tea.MouseMsg:
+		return a.handleMouse(msg)
+
 	case tea.KeyMsg:
+		// The inline filter takes every key except ctrl+c while typing
+		if a.filtering && a.viewState == ViewMessages && msg.Type != tea.KeyCtrlC {
+			return a.handleFilterKeys(msg)
+		}
+		if a.finding && a.viewState == ViewEmail && msg.Type != tea.KeyCtrlC {
+			return a.handleFindKeys(msg)
+		}
+		// ctrl+c cancels a question rather than quitting
+		if a.confirm != nil {
+			return a.handleConfirmKeys(msg)
+		}
+		if a.blockedList && msg.Type != tea.KeyCtrlC {
+			return a.handleBlockedListKeys(msg)
+		}
+		if a.contactPicker && msg.Type != tea.KeyCtrlC {
+			return a.handleContactPickerKeys(msg)
+		}
+		if a.fileBrowser != nil && a.viewState == ViewCompose && msg.Type != tea.KeyCtrlC {
+			return a.handleFileBrowserKeys(msg)
+		}
+		if a.split != nil && a.viewState == ViewMessages && msg.Type != tea.KeyCtrlC {
+			return a.handleSplitKeys(msg)
+		}
+		if a.accountPicker && msg.Type != tea.KeyCtrlC {
+			return a.handleAccountPickerKeys(msg)
+		}
+		if a.links != nil && a.viewState == ViewEmail && msg.Type != tea.KeyCtrlC {
+			return a.handleLinkListKeys(msg)
+		}
+		if a.move != nil && msg.Type != tea.KeyCtrlC {
+			return a.handleMovePickerKeys(msg)
+		}
+		if a.snooze != nil && msg.Type != tea.KeyCtrlC {
+			return a.handleSnoozePickerKeys(msg)
+		}
+		if a.showingTranscript() && msg.Type != tea.KeyCtrlC {
+			return a.handleTranscriptKeys(msg)
+		}
+		if a.showingFolderAttachments() && msg.Type != tea.KeyCtrlC {
+			return a.handleFolderAttachmentKeys(msg)
+		}
+
 		// Global keys
 		if key.Matches(msg, a.keys.Quit) {
 			return a, tea.Quit
@@ -350,15 +913,31 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, nil
 		}
 
-		// Clear error on any key if error is showing
+		// r retries what failed, when it can be; any other key clears the
+		// error
 		if a.err != nil {
-			a.err = nil
+			return a.handleErrorKeys(msg)
+		}
+
+		// Any key closes the thread debug overlay
+		if a.debugThreadID != "" {
+			a.debugThreadID = ""
 			return a, nil
 		}
 
+		// "u" undoes an archive while the status bar offers it
+		if a.toast != nil && a.toast.undo && a.viewState != ViewCompose && key.Matches(msg, a.keys.MarkUnread) {
+			a.toast = nil
+			return a, a.undoBulk()
+		}
+
 		// Handle navigation
 		return a.handleKeyPress(msg)
 
+	case clockTickMsg:
+		a.now = time.Time(msg)
+		return a, tickClock()
+
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		a.spinner, cmd = a.spinner.Update(msg)
@@ -367,17 +946,24 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case mailboxesLoadedMsg:
 		a.loading = false
 		if msg.err != nil {
-			a.err = msg.err
+			a.fail(msg.err, a.loadMailboxes)
 			return a, nil
 		}
-		a.mailboxes = msg.mailboxes
-		a.mailboxView = views.NewMailboxView(a.mailboxes)
-
 		// Cache mailboxes if from network
 		if !msg.fromCache && a.store != nil {
-			a.store.SaveMailboxes(a.client.AccountID(), a.mailboxes)
+			a.store.SaveMailboxes(a.client.AccountID(), msg.mailboxes)
 		}
 
+		// The virtual folders always come last
+		a.mailboxes = append(msg.mailboxes, models.FollowUpMailbox())
+		if a.client.MaxDelayedSend() > 0 {
+			a.mailboxes = append(a.mailboxes, models.ScheduledMailbox())
+		}
+		if msg.outbox {
+			a.mailboxes = append(a.mailboxes, models.OutboxMailbox())
+		}
+		a.mailboxView = views.NewMailboxView(a.mailboxes)
+
 		// Find inbox and load emails
 		var inboxID string
 		for i, mb := range a.mailboxes {
@@ -399,25 +985,68 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Trigger background sync if loaded from cache
 			if msg.fromCache {
 				a.syncing = true
-				cmds = append(cmds, a.syncInBackground(inboxID))
+				cmds = append(cmds, a.syncInBackground(inboxID), a.tickSyncProgress())
 			}
 
 			return a, tea.Batch(cmds...)
 		}
 		return a, nil
 
+	case scheduledLoadedMsg:
+		a.scheduled = make(map[string]jmap.ScheduledSend)
+		for _, s := range msg.sends {
+			a.scheduled[s.EmailID] = s
+		}
+		return a.Update(emailsLoadedMsg{emails: msg.emails, mailboxID: models.ScheduledMailboxID, err: msg.err})
+
+	case outboxLoadedMsg:
+		a.outbox = make(map[string]storage.OutboxItem)
+		for _, item := range msg.items {
+			a.outbox[outboxEmailID(item.ID)] = item
+		}
+		return a.Update(emailsLoadedMsg{emails: a.outboxEmails(msg.items), mailboxID: models.OutboxMailboxID, err: msg.err})
+
 	case emailsLoadedMsg:
 		a.loading = false
 		if msg.err != nil {
-			a.err = msg.err
+			// Retry the folder that failed, which may not be the one open now
+			var retry tea.Cmd
+			if msg.mailboxID != "" {
+				retry = a.loadEmailsFresh(msg.mailboxID)
+			}
+			a.fail(msg.err, retry)
 			return a, nil
 		}
+		// Only a refresh of the folder on screen has anything to compare
+		var arrivals tea.Cmd
+		if msg.fresh && msg.mailboxID == a.emailsMailbox {
+			arrivals = a.markArrivals(a.emails, msg.emails)
+		}
 		a.emails = msg.emails
+		a.emailsMailbox = msg.mailboxID
 		oldThreadCount := len(a.threads)
-		a.threads = a.groupEmailsIntoThreads(msg.emails)
+		a.applyThreadFilters()
+
+		// Archived rows are done animating once they leave the list
+		for id := range a.leaving {
+			if a.findThread(id) == nil {
+				delete(a.leaving, id)
+			}
+		}
 
-		// Preserve selection on refresh, reset on initial load
-		if oldThreadCount == 0 {
+		// Follow a thread picked by advanceSelection, else preserve the
+		// selection on refresh and reset it on initial load
+		if a.followThreadID != "" {
+			for i, t := range a.threads {
+				if t.ID == a.followThreadID {
+					a.selectedThread = i
+					break
+				}
+			}
+			a.followThreadID = ""
+			a.clampThreadSelection()
+			a.selectedInThread = 0
+		} else if oldThreadCount == 0 {
 			a.selectedThread = 0
 			a.selectedInThread = 0
 		} else {
@@ -432,31 +1061,69 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if a.threadList == nil {
-			a.threadList = views.NewThreadListView(a.width-26, a.height-6)
+			a.threadList = views.NewThreadListView(a.width-26, a.height-6, a.cfg.MaxContentWidth)
+			a.threadList.SetColumns(a.columnLayout())
 		}
 		a.threadList.Select(a.selectedThread)
-		a.viewState = ViewMessages
+		// Refreshes mustn't pull the user out of a thread, email or draft
+		if a.viewState == ViewFolders {
+			a.viewState = ViewMessages
+			if open := a.autoOpenUnread(); open != nil {
+				return a, tea.Batch(a.prefetchBodies(), a.loadBodyPreviews(a.emails), arrivals, open)
+			}
+		}
+		return a, tea.Batch(a.prefetchBodies(), a.loadBodyPreviews(a.emails), arrivals)
+
+	case arrivalsFadedMsg:
+		if msg.gen == a.arrivalsGen {
+			a.clearArrivals()
+		}
+		return a, nil
+
+	case bodiesPrefetchedMsg:
+		// Prefetch is best-effort; a failure just means a slower open
+		if msg.count > 0 {
+			return a, a.loadBodyPreviews(a.emails)
+		}
+		return a, nil
+
+	case bodyPreviewsMsg:
+		for id, preview := range msg.previews {
+			a.bodyPreviews[id] = preview
+		}
 		return a, nil
 
 	case emailLoadedMsg:
 		a.loading = false
 		if msg.err != nil {
-			a.err = msg.err
+			a.fail(msg.err, a.loadEmail(msg.id))
 			return a, nil
 		}
 		a.currentEmail = msg.email
-		a.emailReader = views.NewEmailReaderView(msg.email, a.width-26, a.height-6)
+		// Its body is here now, so its list preview can use it
+		a.previewFor(*msg.email)
+		a.clearFind()
+		a.emailReader = views.NewEmailReaderView(msg.email, a.width-26, a.height-6, a.cfg.MaxContentWidth)
+		a.emailReader.SetShowSize(a.cfg.ShowSize)
+		a.emailReader.SetShortAddresses(a.cfg.ShortAddresses)
+		a.otpCode = ""
+		if a.cfg.DetectOTP {
+			if code, ok := extractOTP(msg.email); ok {
+				a.otpCode = code
+				a.emailReader.SetOTP(code)
+			}
+		}
+		a.restoreScroll()
 		a.viewState = ViewEmail
 
-		// Mark as read
-		if msg.email.IsUnread {
-			go a.client.MarkAsRead(msg.email.ID)
-		}
-		return a, nil
+		return a, a.scheduleMarkRead(msg.email)
+
+	case markReadMsg:
+		return a, a.handleMarkRead(msg)
 
 	case emailActionMsg:
 		if msg.err != nil {
-			a.err = msg.err
+			a.fail(msg.err, nil)
 			// Don't refresh on error - let user see the error
 			return a, nil
 		}
@@ -467,18 +1134,86 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a, nil
 
 	case emailSentMsg:
-		if msg.err != nil {
-			a.err = msg.err
+		var toastCmd tea.Cmd
+		if msg.err != nil && msg.queued {
+			a.showOutboxFolder()
+			toastCmd = a.showToast("Send failed ✗, kept in Outbox: "+msg.err.Error(), toastError, 8*time.Second)
+		} else if msg.err != nil {
+			toastCmd = a.showToast("Send failed ✗: "+msg.err.Error(), toastError, 8*time.Second)
+		} else if !msg.sendAt.IsZero() {
+			toastCmd = a.showToast("Scheduled for "+msg.sendAt.Format("Mon Jan 2 15:04")+" ✓", toastSuccess, 3*time.Second)
+		} else {
+			toastCmd = a.showToast("Sent ✓", toastSuccess, 3*time.Second)
 		}
 		// Refresh to show sent email in sent folder if viewing it
 		if len(a.mailboxes) > 0 && a.selectedMailbox < len(a.mailboxes) {
-			return a, a.loadEmails(a.mailboxes[a.selectedMailbox].ID)
+			return a, tea.Batch(toastCmd, a.loadEmails(a.mailboxes[a.selectedMailbox].ID))
+		}
+		return a, toastCmd
+
+	case draftAutosaveMsg:
+		if msg.view != a.composeView {
+			return a, nil
+		}
+		return a, tea.Batch(a.saveDraft(), a.scheduleAutosave())
+
+	case draftSavedMsg:
+		return a, a.handleDraftSaved(msg)
+
+	case senderBlockedMsg:
+		return a.handleSenderBlocked(msg)
+
+	case contactsLoadedMsg:
+		return a.handleContactsLoaded(msg)
+
+	case splitLoadedMsg:
+		return a.handleSplitLoaded(msg)
+
+	case movedMsg:
+		return a.handleMoved(msg)
+
+	case snoozedMsg:
+		return a.handleSnoozed(msg)
+
+	case bounceResendMsg:
+		return a.handleBounceResend(msg)
+
+	case splitMovedMsg:
+		return a.handleSplitMoved(msg)
+
+	case linkOpenedMsg:
+		return a.handleLinkOpened(msg)
+
+	case transcriptLoadedMsg:
+		return a.handleTranscriptLoaded(msg)
+
+	case folderAttachmentsMsg:
+		return a.handleFolderAttachments(msg)
+
+	case localDataClearedMsg:
+		return a.handleLocalDataCleared(msg)
+
+	case localDraftFlushedMsg:
+		return a, a.showToast("Recovered an unsaved draft into Drafts", toastInfo, 5*time.Second)
+
+	case toastExpiredMsg:
+		if a.toast != nil && a.toast.id == msg.id {
+			a.toast = nil
 		}
 		return a, nil
 
+	case threadExportedMsg:
+		if msg.err != nil {
+			return a, a.showToast("Export failed ✗: "+strings.TrimSpace(msg.err.Error()), toastError, 5*time.Second)
+		}
+		if msg.path != "" {
+			return a, a.showToast("Saved to "+msg.path+" ✓", toastSuccess, 5*time.Second)
+		}
+		return a, a.showToast("Conversation copied ✓", toastSuccess, 3*time.Second)
+
 	case attachmentOpenedMsg:
 		if msg.err != nil {
-			a.err = msg.err
+			a.fail(msg.err, nil)
 		}
 		// Exit attachment mode after opening
 		if a.emailReader != nil && a.emailReader.InAttachmentMode() {
@@ -486,6 +1221,126 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return a, nil
 
+	case bulkDoneMsg:
+		if msg.err != nil {
+			a.fail(msg.err, nil)
+			return a, nil
+		}
+		if msg.action != nil {
+			a.lastBulk = msg.action
+		}
+		var toastCmd tea.Cmd
+		if msg.undone {
+			toastCmd = a.showToast("Restored ✓", toastSuccess, 3*time.Second)
+		}
+		if len(a.mailboxes) > 0 && a.selectedMailbox < len(a.mailboxes) {
+			return a, tea.Batch(toastCmd, a.loadEmailsFresh(a.mailboxes[a.selectedMailbox].ID))
+		}
+		return a, toastCmd
+
+	case archivedMsg:
+		if msg.err != nil {
+			delete(a.leaving, msg.threadID)
+			a.fail(msg.err, nil)
+			return a, nil
+		}
+		a.lastBulk = msg.action
+		toastCmd := a.showToast("Archived — u to undo", toastInfo, archiveUndoWindow)
+		a.toast.undo = true
+		if len(a.mailboxes) > 0 && a.selectedMailbox < len(a.mailboxes) {
+			return a, tea.Batch(toastCmd, a.loadEmailsFresh(a.mailboxes[a.selectedMailbox].ID))
+		}
+		return a, toastCmd
+
+	case pipeFinishedMsg:
+		// Drop output for a message the user has already left
+		if a.viewState != ViewEmail || a.currentEmail == nil || a.currentEmail.ID != msg.emailID || a.emailReader == nil {
+			return a, nil
+		}
+		if msg.err != nil {
+			// Partial output is still worth reading alongside the error
+			if strings.TrimSpace(msg.output) != "" {
+				a.emailReader.SetPipeOutput(msg.command, msg.output)
+			}
+			return a, a.showToast(msg.err.Error(), toastError, 6*time.Second)
+		}
+		if strings.TrimSpace(msg.output) == "" {
+			return a, a.showToast(msg.command+" printed nothing", toastInfo, 3*time.Second)
+		}
+		a.emailReader.SetPipeOutput(msg.command, msg.output)
+		a.toast = nil
+		return a, nil
+
+	case pagerClosedMsg:
+		if msg.err != nil {
+			a.fail(msg.err, nil)
+		}
+		return a, nil
+
+	case browserOpenedMsg:
+		if msg.err != nil {
+			a.fail(msg.err, nil)
+		}
+		return a, nil
+
+	case threadCountMsg:
+		if msg.err != nil {
+			// Non-fatal - the thread just won't offer to load more
+			return a, nil
+		}
+		if t := a.findThread(msg.threadID); t != nil {
+			t.ServerCount = msg.count
+		}
+		return a, nil
+
+	case threadLoadedMsg:
+		a.loading = false
+		if msg.err != nil {
+			a.fail(msg.err, a.loadFullThread(msg.threadID))
+			return a, nil
+		}
+		if t := a.findThread(msg.threadID); t != nil {
+			mergeThreadEmails(t, msg.emails)
+		}
+		return a, nil
+
+	case priorityDataLoadedMsg:
+		if msg.err != nil {
+			// Non-fatal - everything just lands in Other
+			return a, nil
+		}
+		a.repliedSenders = msg.replied
+		a.senderPriority = msg.overrides
+		if a.priorityInboxActive() {
+			a.applyThreadFilters()
+			a.clampThreadSelection()
+		}
+		return a, nil
+
+	case pinnedThreadsLoadedMsg:
+		if msg.err != nil {
+			// Non-fatal - threads just won't be pinned
+			return a, nil
+		}
+		a.pinnedThreads = msg.pinned
+		if len(a.emails) > 0 {
+			a.applyThreadFilters()
+			a.clampThreadSelection()
+		}
+		return a, nil
+
+	case threadPinnedMsg:
+		if msg.err != nil {
+			a.fail(msg.err, nil)
+		}
+		return a, nil
+
+	case senderTrainedMsg:
+		if msg.err != nil {
+			a.fail(msg.err, nil)
+		}
+		return a, nil
+
 	case identitiesLoadedMsg:
 		if msg.err != nil {
 			// Non-fatal - just won't have identity selection
@@ -494,6 +1349,12 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.identities = msg.identities
 		return a, nil
 
+	case syncProgressMsg:
+		if a.syncing {
+			return a, a.tickSyncProgress()
+		}
+		return a, nil
+
 	case syncCompleteMsg:
 		a.syncing = false
 		if msg.err != nil {
@@ -520,13 +1381,14 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			var cmds []tea.Cmd
 
 			// Reload mailboxes if they changed
-			if msg.mailboxResult != nil &&
+			if (msg.mailboxResult != nil &&
 				(msg.mailboxResult.MailboxesCreated > 0 ||
 					msg.mailboxResult.MailboxesUpdated > 0 ||
-					msg.mailboxResult.MailboxesDestroyed > 0) {
+					msg.mailboxResult.MailboxesDestroyed > 0)) ||
+				(msg.emailResult != nil && msg.emailResult.JunkMarkedRead > 0) {
 				cmds = append(cmds, func() tea.Msg {
 					mailboxes, err := a.syncer.GetCachedMailboxes()
-					return mailboxesLoadedMsg{mailboxes: mailboxes, fromCache: true, err: err}
+					return mailboxesLoadedMsg{mailboxes: mailboxes, fromCache: true, outbox: a.hasOutbox(), err: err}
 				})
 			}
 
@@ -537,10 +1399,14 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					msg.emailResult.EmailsDestroyed > 0) {
 				if len(a.mailboxes) > 0 && a.selectedMailbox < len(a.mailboxes) {
 					mailboxID := a.mailboxes[a.selectedMailbox].ID
-					cmds = append(cmds, func() tea.Msg {
-						emails, err := a.syncer.GetCachedEmails(mailboxID, a.cfg.PageSize)
-						return emailsLoadedMsg{emails: emails, fromCache: true, err: err}
-					})
+					if models.IsVirtualMailboxID(mailboxID) {
+						cmds = append(cmds, a.loadEmails(mailboxID))
+					} else {
+						cmds = append(cmds, func() tea.Msg {
+							emails, err := a.syncer.GetCachedEmails(mailboxID, a.cfg.PageSize)
+							return emailsLoadedMsg{emails: emails, mailboxID: mailboxID, fromCache: true, err: err}
+						})
+					}
 				}
 			}
 
@@ -555,6 +1421,34 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// g and a letter jump to a folder, before the letter's own action
+	if a.viewState == ViewFolders || a.viewState == ViewMessages {
+		if cmd, ok := a.handleGoTo(msg); ok {
+			return a, cmd
+		}
+	}
+
+	// Actions the folder doesn't permit stop here with a status message
+	if a.viewState == ViewMessages || a.viewState == ViewThread || a.viewState == ViewEmail {
+		if cmd, ok := a.checkRights(msg); !ok {
+			return a, cmd
+		}
+		if key.Matches(msg, a.keys.Move) {
+			return a, a.openMovePicker()
+		}
+		if key.Matches(msg, a.keys.Snooze) {
+			return a, a.openSnoozePicker()
+		}
+	}
+
+	// Number keys and the account picker switch accounts outside of
+	// reading and writing mail
+	if a.viewState == ViewFolders || a.viewState == ViewMessages {
+		if cmd, ok := a.handleAccountKeys(msg); ok {
+			return a, cmd
+		}
+	}
+
 	// Navigation: ← goes back, → goes forward, Enter opens, Esc goes back
 	switch a.viewState {
 	case ViewFolders:
@@ -590,17 +1484,45 @@ func (a *App) handleFoldersKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, a.keys.Right), key.Matches(msg, a.keys.Enter):
 		// Open mailbox → go to thread list
 		if len(a.mailboxes) > 0 {
-			a.loading = true
-			return a, a.loadEmails(a.mailboxes[a.selectedMailbox].ID)
+			return a, a.openMailbox(a.selectedMailbox)
 		}
 	case key.Matches(msg, a.keys.Back):
 		// Already at leftmost level, quit
 		return a, tea.Quit
+	case key.Matches(msg, a.keys.BlockSender):
+		return a, a.openBlockedList()
+	case key.Matches(msg, a.keys.Logout):
+		return a.confirmLogout()
 	}
 	return a, nil
 }
 
+// openMailbox selects the mailbox at index i and loads its thread list
+func (a *App) openMailbox(i int) tea.Cmd {
+	a.selectedMailbox = i
+	if a.mailboxView != nil {
+		a.mailboxView.Select(i)
+	}
+	a.viewState = ViewFolders
+	a.loading = true
+	a.showOther = false
+	a.clearFilter()
+	a.marked = make(map[string]bool)
+	return a.loadEmails(a.mailboxes[i].ID)
+}
+
 func (a *App) handleMessagesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// New-mail markers from a refresh last until the selection moves
+	if key.Matches(msg, a.keys.Up, a.keys.Down, a.keys.Top, a.keys.Bottom, a.keys.Left, a.keys.Right, a.keys.Enter, a.keys.Back) {
+		a.clearArrivals()
+	}
+
+	if a.isInOutbox() {
+		if cmd, handled := a.handleOutboxKeys(msg); handled {
+			return a, cmd
+		}
+	}
+
 	switch {
 	case key.Matches(msg, a.keys.Up):
 		if a.selectedThread > 0 {
@@ -627,21 +1549,14 @@ func (a *App) handleMessagesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			a.threadList.Select(a.selectedThread)
 		}
 	case key.Matches(msg, a.keys.Right), key.Matches(msg, a.keys.Enter):
-		// Open thread
-		if len(a.threads) > 0 && a.selectedThread < len(a.threads) {
-			thread := &a.threads[a.selectedThread]
-			if len(thread.Emails) == 1 {
-				// Single email thread - go directly to email
-				a.loading = true
-				return a, a.loadEmail(thread.Emails[0].ID)
-			} else {
-				// Multi-email thread - expand and go to thread view
-				thread.Expanded = true
-				a.selectedInThread = 0
-				a.viewState = ViewThread
-			}
-		}
+		return a, a.openSelectedThread()
 	case key.Matches(msg, a.keys.Expand):
+		// Enter opens the newest email with open-latest, so expanding is
+		// left to this key
+		if a.cfg.ThreadEnterBehavior == config.ThreadEnterOpenLatest &&
+			a.selectedThread < len(a.threads) && len(a.threads[a.selectedThread].Emails) > 1 {
+			return a, a.expandSelectedThread()
+		}
 		// Toggle expand/collapse
 		if len(a.threads) > 0 && a.selectedThread < len(a.threads) {
 			a.threads[a.selectedThread].Expanded = !a.threads[a.selectedThread].Expanded
@@ -649,14 +1564,48 @@ func (a *App) handleMessagesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				a.threadList.UpdateThreads(a.convertToViewThreads())
 			}
 		}
+	case key.Matches(msg, a.keys.Search):
+		return a, a.startFilter()
 	case key.Matches(msg, a.keys.Left), key.Matches(msg, a.keys.Back):
-		// Go back to folders
+		// Clear an active filter or selection first, then go back to folders
+		if a.filterQuery != "" {
+			a.clearFilter()
+			a.refilterThreads()
+			return a, nil
+		}
+		if len(a.marked) > 0 {
+			a.marked = make(map[string]bool)
+			return a, nil
+		}
 		a.viewState = ViewFolders
+	case key.Matches(msg, a.keys.Mark):
+		if len(a.threads) > 0 && a.selectedThread < len(a.threads) {
+			a.toggleMark(a.threads[a.selectedThread].ID)
+			if a.selectedThread < len(a.threads)-1 {
+				a.selectedThread++
+				a.clampThreadSelection()
+			}
+		}
+	case key.Matches(msg, a.keys.Undo):
+		return a, a.undoBulk()
+	case key.Matches(msg, a.keys.ArchiveRead):
+		return a.archiveAllRead()
 	case key.Matches(msg, a.keys.Delete):
+		if len(a.marked) > 0 {
+			return a, a.bulkMove("delete", "trash")
+		}
 		if len(a.threads) > 0 && a.selectedThread < len(a.threads) {
 			thread := a.threads[a.selectedThread]
+			// In Scheduled, "d" cancels the send instead
+			if a.isInScheduled() {
+				a.advanceSelection()
+				return a, a.cancelScheduled(thread.Emails)
+			}
 			// Delete first email in thread (or all?)
 			if len(thread.Emails) > 0 {
+				if len(thread.Emails) == 1 {
+					a.advanceSelection()
+				}
 				return a, a.deleteEmail(thread.Emails[0].ID)
 			}
 		}
@@ -666,9 +1615,7 @@ func (a *App) handleMessagesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if len(thread.Emails) > 0 {
 				// In trash, "u" undeletes (moves to inbox)
 				if a.isInTrash() {
-					if a.selectedThread >= len(a.threads)-1 && a.selectedThread > 0 {
-						a.selectedThread--
-					}
+					a.advanceSelection()
 					return a, a.undeleteThread(thread.Emails)
 				}
 				// Otherwise toggle read/unread
@@ -676,19 +1623,14 @@ func (a *App) handleMessagesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 	case key.Matches(msg, a.keys.Archive):
+		if len(a.marked) > 0 {
+			return a, a.bulkMove("archive", "archive")
+		}
 		if len(a.threads) > 0 && a.selectedThread < len(a.threads) {
 			thread := a.threads[a.selectedThread]
 			if len(thread.Emails) > 0 {
-				// If we're at the last thread, move selection up
-				if a.selectedThread >= len(a.threads)-1 && a.selectedThread > 0 {
-					a.selectedThread--
-				}
-				// Archive all emails in the thread
-				emailIDs := make([]string, len(thread.Emails))
-				for i, e := range thread.Emails {
-					emailIDs[i] = e.ID
-				}
-				return a, a.archiveThread(emailIDs)
+				a.advanceSelection()
+				return a, a.archiveThread(thread.ID, thread.Emails)
 			}
 		}
 	case key.Matches(msg, a.keys.Compose):
@@ -720,6 +1662,52 @@ func (a *App) handleMessagesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			a.loading = true
 			return a, a.loadEmailsFresh(a.mailboxes[a.selectedMailbox].ID)
 		}
+	case key.Matches(msg, a.keys.Todo):
+		if len(a.threads) > 0 && a.selectedThread < len(a.threads) {
+			thread := a.threads[a.selectedThread]
+			if len(thread.Emails) > 0 {
+				return a, a.toggleTodo(thread.Emails)
+			}
+		}
+	case key.Matches(msg, a.keys.Pin):
+		if len(a.threads) > 0 && a.selectedThread < len(a.threads) {
+			return a, a.togglePin(a.threads[a.selectedThread].ID)
+		}
+	case key.Matches(msg, a.keys.ThreadInfo):
+		a.toggleThreadDebug()
+	case key.Matches(msg, a.keys.SplitView):
+		return a, a.openSplit()
+	case key.Matches(msg, a.keys.Attachments):
+		return a, a.openFolderAttachments()
+	case key.Matches(msg, a.keys.SortSize):
+		// Toggle between date and size order, keeping the selected thread
+		var selectedID string
+		if a.selectedThread < len(a.threads) {
+			selectedID = a.threads[a.selectedThread].ID
+		}
+		a.sortBySize = !a.sortBySize
+		a.applyThreadFilters()
+		for i, t := range a.threads {
+			if t.ID == selectedID {
+				a.selectedThread = i
+				break
+			}
+		}
+		a.clampThreadSelection()
+	case key.Matches(msg, a.keys.ToggleOther):
+		// Switch between the Focused and Other sections
+		if a.priorityInboxActive() {
+			a.showOther = !a.showOther
+			a.applyThreadFilters()
+			a.selectedThread = 0
+			if a.threadList != nil {
+				a.threadList.Select(0)
+			}
+		}
+	case key.Matches(msg, a.keys.TrainSender):
+		if a.priorityInboxActive() {
+			return a, a.trainSender()
+		}
 	}
 	return a, nil
 }
@@ -753,31 +1741,42 @@ func (a *App) handleThreadKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Collapse and go back
 		thread.Expanded = false
 		a.viewState = ViewMessages
+	case key.Matches(msg, a.keys.Expand):
+		// Show or hide own replies in full
+		if a.cfg.CollapseOwnReplies {
+			a.expandOwnReplies = !a.expandOwnReplies
+		}
+	case key.Matches(msg, a.keys.ThreadInfo):
+		a.toggleThreadDebug()
+	case key.Matches(msg, a.keys.Export):
+		return a, a.exportSelectedThread()
+	case key.Matches(msg, a.keys.Transcript):
+		return a, a.openTranscript()
+	case key.Matches(msg, a.keys.LoadThread):
+		// Fetch conversation members that aren't in the loaded folder
+		if thread.ServerCount > len(thread.Emails) {
+			a.loading = true
+			return a, a.loadFullThread(thread.ID)
+		}
 	case key.Matches(msg, a.keys.Archive):
 		// Archive entire thread, go back to messages
 		if len(thread.Emails) > 0 {
 			thread.Expanded = false
-			a.viewState = ViewMessages
-			// Adjust selection if at end
-			if a.selectedThread >= len(a.threads)-1 && a.selectedThread > 0 {
-				a.selectedThread--
-			}
-			// Archive all emails in the thread
-			emailIDs := make([]string, len(thread.Emails))
-			for i, e := range thread.Emails {
-				emailIDs[i] = e.ID
-			}
-			return a, a.archiveThread(emailIDs)
+			a.advanceSelection()
+			return a, a.archiveThread(thread.ID, thread.Emails)
 		}
 	case key.Matches(msg, a.keys.Delete):
 		// Delete selected email in thread, go back to messages
 		if a.selectedInThread < len(thread.Emails) {
 			thread.Expanded = false
-			a.viewState = ViewMessages
-			if a.selectedThread >= len(a.threads)-1 && a.selectedThread > 0 {
-				a.selectedThread--
+			emailID := thread.Emails[a.selectedInThread].ID
+			// The thread only goes away with its last email
+			if len(thread.Emails) == 1 {
+				a.advanceSelection()
+				return a, a.deleteEmail(emailID)
 			}
-			return a, a.deleteEmail(thread.Emails[a.selectedInThread].ID)
+			a.viewState = ViewMessages
+			return a, a.deleteEmail(emailID)
 		}
 	}
 	return a, nil
@@ -798,41 +1797,61 @@ func (a *App) handleEmailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if a.emailReader != nil {
 			a.emailReader.ScrollDown()
 		}
+	case key.Matches(msg, a.keys.NextEmail):
+		return a, a.readAdjacent(1)
+	case key.Matches(msg, a.keys.PrevEmail):
+		return a, a.readAdjacent(-1)
 	case key.Matches(msg, a.keys.Left), key.Matches(msg, a.keys.Back):
+		// Clear a search, then leave command output, then the message
+		if a.emailReader != nil && a.emailReader.HasFind() {
+			a.clearFind()
+			return a, nil
+		}
+		if a.emailReader != nil && a.emailReader.HasPipeOutput() {
+			a.emailReader.ClearPipeOutput()
+			return a, nil
+		}
 		// Go back
+		a.rememberScroll()
 		a.currentEmail = nil
-		// Check if thread has multiple emails
-		if a.selectedThread < len(a.threads) && len(a.threads[a.selectedThread].Emails) > 1 {
+		// Check if thread has multiple emails, and came from the thread
+		// view rather than straight from the list with open-latest
+		if a.selectedThread < len(a.threads) && len(a.threads[a.selectedThread].Emails) > 1 &&
+			(a.cfg.ThreadEnterBehavior != config.ThreadEnterOpenLatest || a.threads[a.selectedThread].Expanded) {
 			a.viewState = ViewThread
 		} else {
 			a.viewState = ViewMessages
 		}
+	case key.Matches(msg, a.keys.LoadThread):
+		// Fetch the rest of the conversation; back then shows all of it
+		if a.selectedThread < len(a.threads) {
+			thread := &a.threads[a.selectedThread]
+			if thread.ServerCount > len(thread.Emails) {
+				thread.Expanded = true
+				a.loading = true
+				return a, a.loadFullThread(thread.ID)
+			}
+		}
 	case key.Matches(msg, a.keys.Delete):
 		if a.currentEmail != nil {
 			emailID := a.currentEmail.ID
+			a.rememberScroll()
 			a.currentEmail = nil
-			a.viewState = ViewMessages
-			// Adjust selection if at end
-			if a.selectedThread >= len(a.threads)-1 && a.selectedThread > 0 {
-				a.selectedThread--
+			if a.selectedThread < len(a.threads) && len(a.threads[a.selectedThread].Emails) > 1 {
+				// Other messages keep the thread in the list
+				a.viewState = ViewMessages
+				return a, a.deleteEmail(emailID)
 			}
+			a.advanceSelection()
 			return a, a.deleteEmail(emailID)
 		}
 	case key.Matches(msg, a.keys.Archive):
 		if a.selectedThread < len(a.threads) {
 			thread := a.threads[a.selectedThread]
+			a.rememberScroll()
 			a.currentEmail = nil
-			a.viewState = ViewMessages
-			// Adjust selection if at end
-			if a.selectedThread >= len(a.threads)-1 && a.selectedThread > 0 {
-				a.selectedThread--
-			}
-			// Archive all emails in the thread
-			emailIDs := make([]string, len(thread.Emails))
-			for i, e := range thread.Emails {
-				emailIDs[i] = e.ID
-			}
-			return a, a.archiveThread(emailIDs)
+			a.advanceSelection()
+			return a, a.archiveThread(thread.ID, thread.Emails)
 		}
 	case key.Matches(msg, a.keys.Compose):
 		return a.startCompose(nil, views.ModeCompose)
@@ -853,6 +1872,62 @@ func (a *App) handleEmailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if a.emailReader != nil && a.emailReader.HasAttachments() {
 			a.emailReader.ToggleAttachmentMode()
 		}
+	case key.Matches(msg, a.keys.Todo):
+		if a.currentEmail != nil {
+			email := *a.currentEmail
+			a.currentEmail.IsTodo = !email.IsTodo
+			return a, a.toggleTodo([]models.Email{email})
+		}
+	case key.Matches(msg, a.keys.CopyOTP):
+		if a.otpCode != "" {
+			return a, a.copyOTP(a.otpCode)
+		}
+	case key.Matches(msg, a.keys.CopyLink):
+		return a, a.copyWebURL()
+	case key.Matches(msg, a.keys.Resend):
+		return a, a.resendBounce()
+	case key.Matches(msg, a.keys.Pager):
+		if a.emailReader != nil {
+			return a, a.openInPager(a.emailReader.RenderedBody())
+		}
+	case key.Matches(msg, a.keys.PipeBody):
+		return a, a.pipeBody()
+	case key.Matches(msg, a.keys.Search):
+		return a, a.startFind()
+	case key.Matches(msg, a.keys.FindNext):
+		if a.emailReader != nil {
+			a.emailReader.NextMatch()
+		}
+	case key.Matches(msg, a.keys.FindPrev):
+		if a.emailReader != nil {
+			a.emailReader.PrevMatch()
+		}
+	case key.Matches(msg, a.keys.OpenBrowser):
+		if a.currentEmail != nil {
+			return a.openHTML(a.currentEmail)
+		}
+	case key.Matches(msg, a.keys.Links):
+		return a, a.openLinkList()
+	case key.Matches(msg, a.keys.BodyPart):
+		if a.emailReader != nil {
+			a.emailReader.ToggleBodyPart()
+		}
+	case key.Matches(msg, a.keys.BlockSender):
+		return a, a.blockSender()
+	case key.Matches(msg, a.keys.Addresses):
+		// Remembered for every message from now on
+		if a.emailReader != nil {
+			a.cfg.ShortAddresses = !a.cfg.ShortAddresses
+			a.emailReader.SetShortAddresses(a.cfg.ShortAddresses)
+			if err := a.cfg.Save(); err != nil {
+				return a, a.showToast("Couldn't save the setting: "+err.Error(), toastError, 4*time.Second)
+			}
+		}
+	case key.Matches(msg, a.keys.Expand):
+		// Show or collapse the full recipient list
+		if a.emailReader != nil {
+			a.emailReader.ToggleRecipients()
+		}
 	}
 	return a, nil
 }
@@ -876,9 +1951,155 @@ func (a *App) handleAttachmentKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+// findThread returns the loaded thread with the given ID, or nil
+func (a *App) findThread(threadID string) *Thread {
+	for i := range a.threads {
+		if a.threads[i].ID == threadID {
+			return &a.threads[i]
+		}
+	}
+	return nil
+}
+
+// checkThreadCount asks the server how many emails a thread really has
+func (a *App) checkThreadCount(threadID string) tea.Cmd {
+	return func() tea.Msg {
+		ids, err := a.client.GetThreadEmailIDs(threadID)
+		return threadCountMsg{threadID: threadID, count: len(ids), err: err}
+	}
+}
+
+// loadFullThread fetches every email in a thread from the server and caches them
+func (a *App) loadFullThread(threadID string) tea.Cmd {
+	return func() tea.Msg {
+		emails, err := a.client.GetThread(threadID)
+		if err == nil && a.store != nil && len(emails) > 0 {
+			a.store.SaveEmails(a.client.AccountID(), emails)
+		}
+		return threadLoadedMsg{threadID: threadID, emails: emails, err: err}
+	}
+}
+
+// mergeThreadEmails adds fetched emails to a thread, skipping ones already
+// present, and keeps the newest-first order used by the message list
+func mergeThreadEmails(t *Thread, emails []models.Email) {
+	seen := make(map[string]bool, len(t.Emails))
+	for _, e := range t.Emails {
+		seen[e.ID] = true
+	}
+	for _, e := range emails {
+		if !seen[e.ID] {
+			t.Emails = append(t.Emails, e)
+			seen[e.ID] = true
+		}
+	}
+
+	sort.SliceStable(t.Emails, func(i, j int) bool {
+		return t.Emails[i].ReceivedAt.After(t.Emails[j].ReceivedAt)
+	})
+
+	t.UnreadCnt = 0
+	for _, e := range t.Emails {
+		if e.IsUnread {
+			t.UnreadCnt++
+		}
+	}
+	if len(t.Emails) > t.ServerCount {
+		t.ServerCount = len(t.Emails)
+	}
+}
+
+// rememberScroll records the reader's scroll position for the current email
+func (a *App) rememberScroll() {
+	if a.currentEmail == nil || a.emailReader == nil || a.cfg.ScrollMemory <= 0 {
+		return
+	}
+	offset := a.emailReader.ScrollOffset()
+	if offset == 0 {
+		delete(a.scrollMemory, a.currentEmail.ID)
+		return
+	}
+	a.scrollMemory[a.currentEmail.ID] = scrollPosition{
+		offset:   offset,
+		bodyHash: hashBody(a.currentEmail),
+		savedAt:  time.Now(),
+	}
+}
+
+// restoreScroll reapplies a remembered scroll position to the reader.
+// Positions expire after the configured time or when the body has changed.
+func (a *App) restoreScroll() {
+	if a.currentEmail == nil || a.emailReader == nil {
+		return
+	}
+	pos, ok := a.scrollMemory[a.currentEmail.ID]
+	if !ok {
+		return
+	}
+	maxAge := time.Duration(a.cfg.ScrollMemory) * time.Minute
+	if time.Since(pos.savedAt) > maxAge || pos.bodyHash != hashBody(a.currentEmail) {
+		delete(a.scrollMemory, a.currentEmail.ID)
+		return
+	}
+	a.emailReader.SetScrollOffset(pos.offset)
+}
+
+// hashBody fingerprints an email's body so stale scroll positions can be detected
+func hashBody(email *models.Email) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(email.TextBody))
+	h.Write([]byte(email.HTMLBody))
+	return h.Sum64()
+}
+
+// clockTickMsg updates the status bar clock
+type clockTickMsg time.Time
+
+// tickClock schedules the next clock update on the minute boundary
+func tickClock() tea.Cmd {
+	now := time.Now()
+	next := now.Truncate(time.Minute).Add(time.Minute)
+	return tea.Tick(next.Sub(now), func(t time.Time) tea.Msg {
+		return clockTickMsg(t)
+	})
+}
+
+// renderConnection renders a dot showing how requests to the server are going
+func (a *App) renderConnection() string {
+	color := ColorDim
+	switch a.client.ConnectionState() {
+	case jmap.ConnOK:
+		color = ColorOnline
+	case jmap.ConnFailed:
+		color = ColorAccent
+	case jmap.ConnPending:
+		color = ColorPending
+	}
+	return lipgloss.NewStyle().Foreground(color).Render("●")
+}
+
 // startCompose initializes the compose view
 func (a *App) startCompose(email *models.Email, mode views.ComposeMode) (tea.Model, tea.Cmd) {
+	// Guard against an accidental reply to a big list
+	if mode == views.ModeReplyAll && email != nil && a.cfg.ReplyAllConfirm > 0 {
+		if n := replyAllRecipients(email); n > a.cfg.ReplyAllConfirm {
+			return a.askConfirm(fmt.Sprintf("Reply to all %d recipients?", n), func() (tea.Model, tea.Cmd) {
+				return a.openCompose(email, mode)
+			})
+		}
+	}
+	return a.openCompose(email, mode)
+}
+
+// openCompose switches to the compose view set up for the given mode
+func (a *App) openCompose(email *models.Email, mode views.ComposeMode) (tea.Model, tea.Cmd) {
+	if !a.client.SupportsSubmission() {
+		a.fail(fmt.Errorf("this server does not support sending email"), nil)
+		return a, nil
+	}
+
 	// Convert jmap identities to view identities
+	account := a.cfg.FindAccount(a.client.Email())
 	viewIdentities := make([]views.Identity, len(a.identities))
 	for i, id := range a.identities {
 		viewIdentities[i] = views.Identity{
@@ -886,14 +2107,26 @@ func (a *App) startCompose(email *models.Email, mode views.ComposeMode) (tea.Mod
 			Name:  id.Name,
 			Email: id.Email,
 		}
+		if account != nil {
+			viewIdentities[i].Signature = account.SignatureFor(id.Email)
+			viewIdentities[i].ReplyTo = account.ReplyToFor(id.Email)
+		}
 	}
 
 	a.composeView = views.NewComposeView(a.width-26, a.height-8, viewIdentities)
+	a.composeView.SetScheduling(a.client.MaxDelayedSend() > 0)
+	a.composeView.SetBottomPosting(a.cfg.ReplyStyle == config.ReplyStyleBottom)
+
+	// Start from the folder's identity; replies below still prefer the
+	// address the email was sent to
+	if addr := a.folderIdentity(); addr != "" {
+		a.composeView.SelectIdentityByEmail(addr)
+	}
 
 	switch mode {
 	case views.ModeReply:
 		a.composeView.SetReply(email, false)
-		a.composeView.RemoveSelfFromCC(a.client.Email())
+		a.composeView.RemoveSelfFromRecipients(a.client.Email())
 		// Select identity that received the email
 		if email != nil {
 			for _, to := range email.To {
@@ -902,7 +2135,7 @@ func (a *App) startCompose(email *models.Email, mode views.ComposeMode) (tea.Mod
 		}
 	case views.ModeReplyAll:
 		a.composeView.SetReply(email, true)
-		a.composeView.RemoveSelfFromCC(a.client.Email())
+		a.composeView.RemoveSelfFromRecipients(a.client.Email())
 		// Select identity that received the email
 		if email != nil {
 			for _, to := range email.To {
@@ -919,49 +2152,58 @@ func (a *App) startCompose(email *models.Email, mode views.ComposeMode) (tea.Mod
 		}
 	}
 
+	if id := a.composeView.GetIdentity(); id != nil {
+		a.composeView.SetSignature(id.Signature)
+	} else if account != nil {
+		a.composeView.SetSignature(account.Signature)
+	}
+
 	a.prevViewState = a.viewState
 	a.viewState = ViewCompose
+	a.draftSnapshot = ""
+	a.draftStatus = ""
 
-	return a, nil
+	return a, a.scheduleAutosave()
 }
 
 // handleComposeKeys handles input in compose view
 func (a *App) handleComposeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "esc":
-		// Cancel compose
+		// Cancel compose. Once autosave has kept a copy, it's brought up
+		// to date rather than left stale.
+		var cmd tea.Cmd
+		if a.composeView != nil && a.draftSaving {
+			// A save is running; the last edits follow once it lands, so
+			// they replace its copy instead of making a second one
+			a.closedDraft = &pendingDraft{view: a.composeView, message: a.composeMessage()}
+			cmd = a.showToast("Kept in Drafts", toastInfo, 3*time.Second)
+		} else if a.composeView != nil && a.draftStatus != "" {
+			if save := a.saveDraft(); save != nil {
+				cmd = tea.Batch(save, a.showToast("Kept in Drafts", toastInfo, 3*time.Second))
+			}
+		}
 		a.viewState = a.prevViewState
 		a.composeView = nil
-		return a, nil
+		a.composeOutbox = nil
+		return a, cmd
+	case "ctrl+r":
+		return a, a.openContactPicker()
+	case "ctrl+o":
+		return a, a.openFileBrowser()
 	case "ctrl+s":
 		// Send email
 		if a.composeView == nil {
 			return a, nil
 		}
 
-		// Validate
-		if !a.composeView.HasRecipients() {
-			return a, nil
-		}
-		if a.composeView.IsEmpty() {
+		// Validate, moving to the field that needs fixing
+		if field, err := a.composeView.Validate(); err != nil {
+			a.composeView.FocusField(field)
+			a.composeView.SetError(err.Error())
 			return a, nil
 		}
-
-		to, cc, subject, body := a.composeView.GetValues()
-		original := a.composeView.Original
-		identity := a.composeView.GetIdentity()
-
-		// Get identity ID (or empty for default)
-		identityID := ""
-		if identity != nil {
-			identityID = identity.ID
-		}
-
-		// Return to previous view
-		a.viewState = a.prevViewState
-		a.composeView = nil
-
-		return a, a.sendEmail(to, cc, subject, body, original, identityID)
+		return a.confirmSend()
 	}
 
 	// Pass to compose view
@@ -970,6 +2212,48 @@ func (a *App) handleComposeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, cmd
 }
 
+// confirmSend asks before sending a message without a subject or body,
+// which is more often a slip than meant, unless confirm_empty_send is off
+func (a *App) confirmSend() (tea.Model, tea.Cmd) {
+	var missing []string
+	if !a.composeView.HasSubject() {
+		missing = append(missing, "subject")
+	}
+	if a.composeView.IsEmpty() {
+		missing = append(missing, "message body")
+	}
+	if len(missing) == 0 || !a.cfg.ConfirmEmptySend {
+		return a.sendCompose()
+	}
+	question := fmt.Sprintf("No %s. Send anyway?", strings.Join(missing, " or "))
+	return a.askConfirm(question, a.sendCompose)
+}
+
+// sendCompose sends the open message and returns to where compose was
+// started from
+func (a *App) sendCompose() (tea.Model, tea.Cmd) {
+	message := a.composeMessage()
+	sendAt := a.composeView.GetSendAt()
+	draftID := a.composeView.DraftID()
+	var outboxID int64
+	if a.composeOutbox != nil {
+		outboxID = a.composeOutbox.ID
+	}
+	if a.draftSaving {
+		a.sentCompose = a.composeView
+	}
+
+	// Return to previous view
+	a.viewState = a.prevViewState
+	a.composeView = nil
+	a.composeOutbox = nil
+
+	return a, tea.Batch(
+		a.showToast("Sending…", toastInfo, 0),
+		a.sendEmail(message, sendAt, outboxID, draftID),
+	)
+}
+
 func (a *App) deleteEmail(emailID string) tea.Cmd {
 	return func() tea.Msg {
 		var trashID string
@@ -999,15 +2283,15 @@ func (a *App) toggleUnread(email models.Email) tea.Cmd {
 	}
 }
 
-func (a *App) archiveThread(emailIDs []string) tea.Cmd {
+// archiveThread archives every email in a thread in one request. The row
+// stays struck through until the list refreshes, and the move can be undone
+// from the status bar for a few seconds.
+func (a *App) archiveThread(threadID string, emails []models.Email) tea.Cmd {
+	a.leaving[threadID] = true
+	action := a.newBulkAction("archive", emails)
+	archiveID := a.mailboxIDByRole("archive")
+
 	return func() tea.Msg {
-		var archiveID string
-		for _, mb := range a.mailboxes {
-			if mb.Role == "archive" {
-				archiveID = mb.ID
-				break
-			}
-		}
 		if archiveID == "" {
 			// Debug: list available roles
 			var roles []string
@@ -1016,15 +2300,17 @@ func (a *App) archiveThread(emailIDs []string) tea.Cmd {
 					roles = append(roles, fmt.Sprintf("%s=%s", mb.Name, mb.Role))
 				}
 			}
-			return emailActionMsg{err: fmt.Errorf("archive mailbox not found (roles: %v)", roles)}
+			return archivedMsg{threadID: threadID, err: fmt.Errorf("archive mailbox not found (roles: %v)", roles)}
 		}
-		// Archive all emails in the thread
-		for _, emailID := range emailIDs {
-			if err := a.client.MoveEmail(emailID, "", archiveID); err != nil {
-				return emailActionMsg{err: err}
-			}
+
+		ids := make([]string, len(emails))
+		for i, e := range emails {
+			ids[i] = e.ID
 		}
-		return emailActionMsg{err: nil}
+		if err := a.client.MoveEmails(ids, archiveID); err != nil {
+			return archivedMsg{threadID: threadID, err: err}
+		}
+		return archivedMsg{threadID: threadID, action: action}
 	}
 }
 
@@ -1059,29 +2345,55 @@ func (a *App) undeleteThread(emails []models.Email) tea.Cmd {
 	}
 }
 
-func (a *App) openAttachment(att *models.Attachment) tea.Cmd {
-	return func() tea.Msg {
-		// Create cache directory
-		cacheDir := filepath.Join(os.TempDir(), "anneal", "attachments")
-		if err := os.MkdirAll(cacheDir, 0755); err != nil {
-			return attachmentOpenedMsg{err: fmt.Errorf("failed to create cache dir: %w", err)}
+// attachmentFile returns a local file holding the attachment, reusing the
+// blob cache when the attachment was downloaded before
+func (a *App) attachmentFile(att *models.Attachment) (string, error) {
+	if a.blobs != nil {
+		if path, ok := a.blobs.Lookup(att.BlobID, att.Name, int64(att.Size)); ok {
+			return path, nil
 		}
+	}
+
+	data, err := a.client.DownloadBlob(att.BlobID, att.Name)
+	if err != nil {
+		return "", err
+	}
+
+	if a.blobs != nil {
+		return a.blobs.Put(att.BlobID, att.Name, data)
+	}
+
+	// No cache available; fall back to a temp file
+	cacheDir := storage.TempDir(a.cfg.CacheDir, "attachments")
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	filePath := filepath.Join(cacheDir, fmt.Sprintf("%s-%s", att.BlobID, filepath.Base(att.Name)))
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save file: %w", err)
+	}
+	return filePath, nil
+}
+
+// attachmentData returns the attachment's contents, from the blob cache
+// when possible
+func (a *App) attachmentData(att *models.Attachment) ([]byte, error) {
+	path, err := a.attachmentFile(att)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
 
-		// Download blob
-		data, err := a.client.DownloadBlob(att.BlobID, att.Name)
+func (a *App) openAttachment(att *models.Attachment) tea.Cmd {
+	return func() tea.Msg {
+		filePath, err := a.attachmentFile(att)
 		if err != nil {
 			return attachmentOpenedMsg{err: err}
 		}
 
-		// Save to temp file
-		filePath := filepath.Join(cacheDir, fmt.Sprintf("%s-%s", att.BlobID, att.Name))
-		if err := os.WriteFile(filePath, data, 0644); err != nil {
-			return attachmentOpenedMsg{err: fmt.Errorf("failed to save file: %w", err)}
-		}
-
 		// Open with system default (non-blocking)
-		cmd := exec.Command("open", filePath)
-		if err := cmd.Start(); err != nil {
+		if err := openFile(filePath); err != nil {
 			return attachmentOpenedMsg{err: fmt.Errorf("failed to open file: %w", err)}
 		}
 
@@ -1089,26 +2401,62 @@ func (a *App) openAttachment(att *models.Attachment) tea.Cmd {
 	}
 }
 
-func (a *App) sendEmail(to, cc []string, subject, body string, original *models.Email, identityID string) tea.Cmd {
+// sendEmail sends a message. If an immediate send fails, the message is
+// kept in the outbox to retry or edit; outboxID is the entry it came from,
+// or 0 for a new message.
+func (a *App) sendEmail(m storage.OutboxMessage, sendAt time.Time, outboxID int64, draftID string) tea.Cmd {
 	return func() tea.Msg {
-		var inReplyTo, references []string
+		var references []string
+		// Could add references chain here if needed
 
-		// Set reply headers if this is a reply
-		if original != nil {
-			inReplyTo = []string{original.ID}
-			// Could add references chain here if needed
+		var uploads []*jmap.Upload
+		var err error
+		for _, path := range m.Attachments {
+			var upload *jmap.Upload
+			if upload, err = a.client.UploadFile(path); err != nil {
+				break
+			}
+			uploads = append(uploads, upload)
+		}
+		if err == nil {
+			err = a.client.SendEmailWithIdentity(m.To, m.CC, m.Subject, m.Body, m.InReplyTo, references, m.IdentityID, m.FromName, sendAt, uploads)
+		}
+		if err == nil {
+			a.deleteDrafts(draftID)
+		}
+		if a.store == nil || !sendAt.IsZero() {
+			return emailSentMsg{sendAt: sendAt, err: err}
 		}
 
-		err := a.client.SendEmailWithIdentity(to, cc, subject, body, inReplyTo, references, identityID)
-		return emailSentMsg{err: err}
+		accountID := a.client.AccountID()
+		if err != nil {
+			_, storeErr := a.store.RecordSendFailure(accountID, outboxID, m, err)
+			return emailSentMsg{err: err, queued: storeErr == nil}
+		}
+		if outboxID != 0 {
+			a.store.DeleteOutboxItem(accountID, outboxID)
+		}
+		return emailSentMsg{}
 	}
 }
 
+// minWidth and minHeight are the smallest terminal the layout fits in;
+// anything smaller is asked to grow instead of being drawn garbled
+const (
+	minWidth  = 40
+	minHeight = 10
+)
+
 // View renders the application
 func (a *App) View() string {
 	if a.width == 0 {
 		return LoadingStyle.Render("  ◇ initializing...")
 	}
+	if a.width < minWidth || a.height < minHeight {
+		msg := fmt.Sprintf("terminal too small (need at least %dx%d)", minWidth, minHeight)
+		return lipgloss.Place(max(a.width, 0), max(a.height, 0), lipgloss.Center, lipgloss.Center,
+			ErrorStyle.Width(max(a.width, 1)).Align(lipgloss.Center).Render(msg))
+	}
 
 	header := a.renderHeader()
 	content := a.renderContent()
@@ -1118,7 +2466,7 @@ func (a *App) View() string {
 	headerHeight := lipgloss.Height(header)
 	statusHeight := lipgloss.Height(statusBar)
 	helpHeight := lipgloss.Height(helpView)
-	contentHeight := a.height - headerHeight - statusHeight - helpHeight
+	contentHeight := max(a.height-headerHeight-statusHeight-helpHeight, 0)
 
 	content = lipgloss.NewStyle().Height(contentHeight).Render(content)
 
@@ -1175,6 +2523,9 @@ func (a *App) renderHeader() string {
 }
 
 func (a *App) renderHelp() string {
+	if a.confirm != nil {
+		return a.renderConfirm()
+	}
 	if a.help.ShowAll {
 		return HelpStyle.Width(a.width).Render(a.help.View(a.keys))
 	}
@@ -1187,9 +2538,27 @@ func (a *App) renderHelp() string {
 			{"↑/↓", "select"},
 			{"→/enter", "open"},
 			{"q", "quit"},
+			{"X", "log out"},
 			{"?", "help"},
 		}
+		if len(a.cfg.BlockedSenders()) > 0 {
+			keys = append(keys, struct{ key, desc string }{"B", "blocked senders"})
+		}
 	case ViewMessages:
+		if a.isInOutbox() {
+			keys = []struct{ key, desc string }{
+				{"↑/↓", "select"},
+				{"→/enter", "edit"},
+				{"r", "retry now"},
+				{"d", "discard"},
+				{"←/esc", "folders"},
+			}
+			// The last error stands in for a tooltip on the selected row
+			if status := a.outboxStatus(); status != "" {
+				keys = append(keys, struct{ key, desc string }{"", status})
+			}
+			break
+		}
 		keys = []struct{ key, desc string }{
 			{"↑/↓", "select"},
 			{"→/enter", "open"},
@@ -1197,12 +2566,34 @@ func (a *App) renderHelp() string {
 		}
 		if a.isInTrash() {
 			keys = append(keys, struct{ key, desc string }{"u", "undelete"})
+		} else if a.isInScheduled() {
+			keys = append(keys, struct{ key, desc string }{"d", "cancel send"})
 		} else {
-			keys = append(keys,
-				struct{ key, desc string }{"c", "compose"},
-				struct{ key, desc string }{"r", "reply"},
-				struct{ key, desc string }{"a", "archive"},
-			)
+			if a.client.SupportsSubmission() {
+				keys = append(keys,
+					struct{ key, desc string }{"c", "compose"},
+					struct{ key, desc string }{"r", "reply"},
+				)
+			}
+			if mb := a.currentMailbox(); mb == nil || mb.CanRemove() {
+				keys = append(keys, struct{ key, desc string }{"a", "archive"})
+			} else {
+				keys = append(keys, struct{ key, desc string }{"", "read-only"})
+			}
+		}
+		if a.priorityInboxActive() {
+			if a.showOther {
+				keys = append(keys, struct{ key, desc string }{"i", "focused"})
+			} else {
+				keys = append(keys, struct{ key, desc string }{"i", "other"})
+			}
+		}
+		if a.lastBulk != nil {
+			keys = append(keys, struct{ key, desc string }{"ctrl+z", "undo " + a.lastBulk.name})
+		}
+		keys = append(keys, struct{ key, desc string }{"/", "filter"})
+		if a.sortBySize {
+			keys = append(keys, struct{ key, desc string }{"S", "sort by date"})
 		}
 		keys = append(keys, struct{ key, desc string }{"?", "help"})
 	case ViewThread:
@@ -1211,8 +2602,23 @@ func (a *App) renderHelp() string {
 			{"→/enter", "read"},
 			{"←/esc", "messages"},
 			{"a", "archive"},
-			{"?", "help"},
 		}
+		if a.selectedThread < len(a.threads) {
+			t := a.threads[a.selectedThread]
+			if t.ServerCount > len(t.Emails) {
+				keys = append(keys, struct{ key, desc string }{"L", "load conversation"})
+			}
+		}
+		if a.cfg.CollapseOwnReplies {
+			if a.expandOwnReplies {
+				keys = append(keys, struct{ key, desc string }{"space", "collapse replies"})
+			} else {
+				keys = append(keys, struct{ key, desc string }{"space", "expand replies"})
+			}
+		}
+		keys = append(keys, struct{ key, desc string }{"T", "transcript"})
+		keys = append(keys, struct{ key, desc string }{"E", "export"})
+		keys = append(keys, struct{ key, desc string }{"?", "help"})
 	case ViewEmail:
 		if a.emailReader != nil && a.emailReader.InAttachmentMode() {
 			keys = []struct{ key, desc string }{
@@ -1224,31 +2630,83 @@ func (a *App) renderHelp() string {
 			keys = []struct{ key, desc string }{
 				{"↑/↓", "scroll"},
 				{"←/esc", "back"},
-				{"r", "reply"},
-				{"R", "reply all"},
-				{"f", "forward"},
-				{"a", "archive"},
+			}
+			if a.client.SupportsSubmission() {
+				keys = append(keys,
+					struct{ key, desc string }{"r", "reply"},
+					struct{ key, desc string }{"R", "reply all"},
+					struct{ key, desc string }{"f", "forward"},
+				)
+			}
+			keys = append(keys, struct{ key, desc string }{"a", "archive"})
+			if a.selectedThread < len(a.threads) {
+				if t := a.threads[a.selectedThread]; t.ServerCount > len(t.Emails) {
+					keys = append(keys, struct{ key, desc string }{"L", "load conversation"})
+				}
 			}
 			// Show attachments hint if email has attachments
 			if a.emailReader != nil && a.emailReader.HasAttachments() {
 				keys = append(keys, struct{ key, desc string }{"→", "attachments"})
 			}
+			if a.currentEmail != nil && a.currentEmail.HTMLBody != "" {
+				keys = append(keys, struct{ key, desc string }{"O", "browser"})
+			}
+			if a.emailReader != nil && a.emailReader.HasLinks() {
+				keys = append(keys, struct{ key, desc string }{"U", "links"})
+			}
+			if _, ok := a.webURLForEmail(a.currentEmail); ok {
+				keys = append(keys, struct{ key, desc string }{"Y", "web link"})
+			}
+			if isBounce(a.currentEmail) {
+				keys = append(keys, struct{ key, desc string }{"e", "resend"})
+			}
+			if len(a.threads) > 1 || (a.selectedThread < len(a.threads) && len(a.threads[a.selectedThread].Emails) > 1) {
+				keys = append(keys, struct{ key, desc string }{"J/K", "next/prev"})
+			}
+			if a.emailReader != nil && a.emailReader.CompactHeader() {
+				keys = append(keys, struct{ key, desc string }{"space", "full header"})
+			} else if a.emailReader != nil && a.emailReader.HasHiddenRecipients() {
+				keys = append(keys, struct{ key, desc string }{"space", "all recipients"})
+			}
+			if a.emailReader != nil && a.emailReader.HasDivergentParts() {
+				keys = append(keys, struct{ key, desc string }{"H", "text/html"})
+			}
+			if a.cfg.PipeCommand != "" {
+				keys = append(keys, struct{ key, desc string }{"!", "pipe"})
+			}
+			if a.emailReader != nil && a.emailReader.HasFind() {
+				keys = append(keys, struct{ key, desc string }{"n/N", "next/prev match"})
+			} else {
+				keys = append(keys, struct{ key, desc string }{"/", "find"})
+			}
 			keys = append(keys, struct{ key, desc string }{"?", "help"})
 		}
 	case ViewCompose:
 		keys = []struct{ key, desc string }{
 			{"tab", "next field"},
+			{"ctrl+o", "attach"},
 			{"ctrl+s", "send"},
 			{"esc", "cancel"},
 		}
+		if a.composeView != nil && a.composeView.Original != nil {
+			keys = append(keys, struct{ key, desc string }{"alt+↑/↓", "top/above quote"})
+		}
+		if a.draftStatus != "" {
+			keys = append(keys, struct{ key, desc string }{"", a.draftStatus})
+		}
 	}
 
 	var parts []string
 	for _, k := range keys {
+		// Entries without a key are notes, like a folder being read-only
+		if k.key == "" {
+			parts = append(parts, HelpDescStyle.Render(i18n.T(k.desc)))
+			continue
+		}
 		parts = append(parts,
 			HelpKeyStyle.Render(k.key)+
 				HelpSepStyle.Render(":")+
-				HelpDescStyle.Render(k.desc))
+				HelpDescStyle.Render(i18n.T(k.desc)))
 	}
 
 	helpText := ""
@@ -1269,10 +2727,17 @@ func (a *App) renderHelp() string {
 
 func (a *App) renderContent() string {
 	if a.err != nil {
+		hint := HelpKeyStyle.Render("any key") + HelpSepStyle.Render(":") + HelpDescStyle.Render("dismiss")
+		if a.retry != nil {
+			hint = HelpKeyStyle.Render("r") + HelpSepStyle.Render(":") + HelpDescStyle.Render("retry") +
+				HelpSepStyle.Render(" │ ") + HelpKeyStyle.Render("esc") + HelpSepStyle.Render(":") + HelpDescStyle.Render("dismiss")
+		}
 		errBox := lipgloss.JoinVertical(lipgloss.Center,
 			ErrorStyle.Render("◇ something went wrong"),
 			"",
 			lipgloss.NewStyle().Foreground(ColorSecondary).Render(fmt.Sprintf("%v", a.err)),
+			"",
+			hint,
 		)
 		return lipgloss.Place(a.width, 10, lipgloss.Center, lipgloss.Center, errBox)
 	}
@@ -1306,6 +2771,41 @@ func (a *App) renderContent() string {
 		main = a.renderComposeView(mainWidth)
 	}
 
+	if a.debugThreadID != "" {
+		main = a.renderThreadDebug(mainWidth)
+	}
+	if a.blockedList {
+		main = a.renderBlockedList(mainWidth)
+	}
+	if a.contactPicker && a.viewState == ViewCompose {
+		main = a.renderContactPicker(mainWidth)
+	}
+	if a.fileBrowser != nil && a.viewState == ViewCompose {
+		a.fileBrowser.SetSize(mainWidth, a.height-6)
+		main = a.fileBrowser.View()
+	}
+	if a.split != nil && a.viewState == ViewMessages {
+		main = a.renderSplit(mainWidth)
+	}
+	if a.accountPicker {
+		main = a.renderAccountPicker(mainWidth)
+	}
+	if a.links != nil && a.viewState == ViewEmail {
+		main = a.renderLinkList(mainWidth)
+	}
+	if a.move != nil {
+		main = a.renderMovePicker(mainWidth)
+	}
+	if a.snooze != nil {
+		main = a.renderSnoozePicker(mainWidth)
+	}
+	if a.showingTranscript() {
+		main = a.renderTranscript(mainWidth)
+	}
+	if a.showingFolderAttachments() {
+		main = a.renderFolderAttachments(mainWidth)
+	}
+
 	return lipgloss.JoinHorizontal(lipgloss.Top, sidebar, main)
 }
 
@@ -1318,7 +2818,7 @@ func (a *App) renderSidebar(width int) string {
 	}
 
 	if a.mailboxView == nil {
-		return style.Render(StatusDescStyle.Render("No mailboxes"))
+		return style.Render(StatusDescStyle.Render(i18n.T("No mailboxes")))
 	}
 
 	return style.Render(a.mailboxView.View())
@@ -1328,7 +2828,7 @@ func (a *App) renderEmptyMain(width int, msg string) string {
 	return lipgloss.Place(
 		width, a.height-8,
 		lipgloss.Center, lipgloss.Center,
-		StatusDescStyle.Render(msg),
+		StatusDescStyle.Render(i18n.T(msg)),
 	)
 }
 
@@ -1336,8 +2836,14 @@ func (a *App) renderMessageList(width int) string {
 	if a.threadList == nil {
 		return a.renderEmptyMain(width, "No messages")
 	}
-	a.threadList.SetSize(width, a.height-6)
 	a.threadList.UpdateThreads(a.convertToViewThreads())
+	a.threadList.SetHighlight(a.filterQuery)
+	a.threadList.SetShowSize(a.sortBySize)
+	if a.filtering || a.filterQuery != "" {
+		a.threadList.SetSize(width, a.height-7)
+		return a.renderFilterBar(width) + "\n" + a.threadList.View()
+	}
+	a.threadList.SetSize(width, a.height-6)
 	return a.threadList.View()
 }
 
@@ -1360,6 +2866,9 @@ func (a *App) renderThreadContents(width int) string {
 	countStyle := lipgloss.NewStyle().
 		Foreground(ColorDim)
 	b.WriteString(countStyle.Render(fmt.Sprintf("  %d messages in thread", len(thread.Emails))))
+	if missing := thread.ServerCount - len(thread.Emails); missing > 0 {
+		b.WriteString(countStyle.Render(fmt.Sprintf(" ◇ %d more on server (L to load full conversation)", missing)))
+	}
 	b.WriteString("\n\n")
 
 	// Render emails in thread with indentation
@@ -1380,6 +2889,21 @@ func (a *App) renderThreadContents(width int) string {
 			b.WriteString(indent)
 		}
 
+		// Own replies collapse to a single dim line
+		if a.isCollapsedReply(email) {
+			line := "you replied · " + email.DateDisplay(true)
+			if preview := strings.TrimSpace(a.previewFor(email)); preview != "" {
+				line += " · " + truncatePreview(preview, 40)
+			}
+			style := lipgloss.NewStyle().Foreground(ColorDim)
+			if isSelected {
+				style = style.Background(ColorBgSelect)
+			}
+			b.WriteString(style.Render(line))
+			b.WriteString("\n")
+			continue
+		}
+
 		// Email info
 		fromStyle := lipgloss.NewStyle().Foreground(ColorSecondary)
 		if email.IsUnread {
@@ -1390,7 +2914,7 @@ func (a *App) renderThreadContents(width int) string {
 
 		line := fromStyle.Render(email.FromDisplay()) +
 			"  " +
-			dateStyle.Render(email.DateDisplay())
+			dateStyle.Render(email.DateDisplay(a.isInSent()))
 
 		if isSelected {
 			line = lipgloss.NewStyle().
@@ -1403,10 +2927,7 @@ func (a *App) renderThreadContents(width int) string {
 
 		// Preview for selected
 		if isSelected {
-			preview := email.Preview
-			if len(preview) > 60 {
-				preview = preview[:57] + "..."
-			}
+			preview := truncatePreview(a.previewFor(email), a.cfg.PreviewLength)
 			previewStyle := lipgloss.NewStyle().
 				Foreground(ColorDim).
 				PaddingLeft(len(indent))
@@ -1418,10 +2939,41 @@ func (a *App) renderThreadContents(width int) string {
 	return b.String()
 }
 
+// isCollapsedReply returns true if the email is one of ours and own
+// replies are currently collapsed
+func (a *App) isCollapsedReply(email models.Email) bool {
+	if !a.cfg.CollapseOwnReplies || a.expandOwnReplies || len(email.From) == 0 {
+		return false
+	}
+	return a.isOwnAddress(email.From[0].Email)
+}
+
+// compactHeaderRows is the terminal height below which compact_header:
+// auto squeezes the reader header
+const compactHeaderRows = 30
+
+// compactHeader returns true if the reader header should be squeezed to
+// leave more of a short terminal for the body
+func (a *App) compactHeader() bool {
+	switch a.cfg.CompactHeader {
+	case config.CompactHeaderAlways:
+		return true
+	case config.CompactHeaderNever:
+		return false
+	}
+	return a.height < compactHeaderRows
+}
+
 func (a *App) renderEmailReader(width int) string {
 	if a.emailReader == nil {
 		return a.renderEmptyMain(width, "No email selected")
 	}
+	a.emailReader.SetCompactHeader(a.compactHeader())
+	a.emailReader.SetSent(a.isInSent())
+	if a.finding || a.emailReader.HasFind() {
+		a.emailReader.SetSize(width, a.height-7)
+		return a.renderFindBar(width) + "\n" + a.emailReader.View()
+	}
 	a.emailReader.SetSize(width, a.height-6)
 	return a.emailReader.View()
 }
@@ -1434,6 +2986,22 @@ func (a *App) renderComposeView(width int) string {
 	return a.composeView.View()
 }
 
+// groupDigits formats n with commas between thousands, as in 1,240
+func groupDigits(n int) string {
+	if n < 0 {
+		return "-" + groupDigits(-n)
+	}
+	digits := strconv.Itoa(n)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
 func (a *App) renderStatusBar() string {
 	var leftPart, rightPart string
 
@@ -1444,6 +3012,25 @@ func (a *App) renderStatusBar() string {
 		threadCount := StatusDescStyle.Render(fmt.Sprintf(" ◇ %d threads", len(a.threads)))
 		leftPart = mailboxName + threadCount
 
+		// Only a page of the folder is loaded; say how much of it
+		if !mb.IsVirtual() && len(a.emails) > 0 && len(a.emails) < mb.TotalEmails {
+			leftPart += StatusDescStyle.Render(fmt.Sprintf(" ◇ showing %s of %s",
+				groupDigits(len(a.emails)), groupDigits(mb.TotalEmails)))
+		}
+
+		if a.priorityInboxActive() {
+			section := "focused"
+			if a.showOther {
+				section = "other"
+			}
+			leftPart += StatusDescStyle.Render(" ◇ ") + StatusKeyStyle.Render(section)
+		}
+
+		if len(a.marked) > 0 {
+			leftPart += StatusDescStyle.Render(" ◇ ") +
+				StatusKeyStyle.Render(fmt.Sprintf("%d marked", len(a.marked)))
+		}
+
 		if mb.UnreadCount > 0 {
 			unread := lipgloss.NewStyle().
 				Foreground(ColorPrimary).
@@ -1452,34 +3039,48 @@ func (a *App) renderStatusBar() string {
 		}
 	}
 
+	// A sync fetching in batches says how far it has got
+	if a.syncing && a.syncer != nil {
+		if done, total := a.syncer.Progress(); total > 0 {
+			leftPart += StatusDescStyle.Render(fmt.Sprintf(" ◇ syncing %s/%s", groupDigits(done), groupDigits(total)))
+		}
+	}
+
 	// Breadcrumb navigation indicator
+	folders, messages, thread, email := i18n.T("folders"), i18n.T("messages"), i18n.T("thread"), i18n.T("email")
 	breadcrumb := ""
 	switch a.viewState {
 	case ViewFolders:
-		breadcrumb = StatusKeyStyle.Render("folders")
+		breadcrumb = StatusKeyStyle.Render(folders)
 	case ViewMessages:
-		breadcrumb = StatusDescStyle.Render("folders ") +
-			StatusKeyStyle.Render("→ messages")
+		breadcrumb = StatusDescStyle.Render(folders+" ") +
+			StatusKeyStyle.Render("→ "+messages)
 	case ViewThread:
-		breadcrumb = StatusDescStyle.Render("folders → messages ") +
-			StatusKeyStyle.Render("→ thread")
+		breadcrumb = StatusDescStyle.Render(folders+" → "+messages+" ") +
+			StatusKeyStyle.Render("→ "+thread)
 	case ViewEmail:
 		// Check if in attachment mode
 		if a.emailReader != nil && a.emailReader.InAttachmentMode() {
-			breadcrumb = StatusDescStyle.Render("... → email ") +
-				StatusKeyStyle.Render("→ attachments")
+			breadcrumb = StatusDescStyle.Render("... → "+email+" ") +
+				StatusKeyStyle.Render("→ "+i18n.T("attachments"))
 		} else if a.selectedThread < len(a.threads) && len(a.threads[a.selectedThread].Emails) > 1 {
-			breadcrumb = StatusDescStyle.Render("... → thread ") +
-				StatusKeyStyle.Render("→ email")
+			breadcrumb = StatusDescStyle.Render("... → "+thread+" ") +
+				StatusKeyStyle.Render("→ "+email)
 		} else {
-			breadcrumb = StatusDescStyle.Render("... → messages ") +
-				StatusKeyStyle.Render("→ email")
+			breadcrumb = StatusDescStyle.Render("... → "+messages+" ") +
+				StatusKeyStyle.Render("→ "+email)
 		}
 	case ViewCompose:
 		breadcrumb = StatusDescStyle.Render("... ") +
-			StatusKeyStyle.Render("→ compose")
+			StatusKeyStyle.Render("→ "+i18n.T("compose"))
 	}
-	rightPart = breadcrumb
+	// A toast temporarily takes over the left side
+	if a.toast != nil {
+		leftPart = a.renderToast()
+	}
+
+	rightPart = breadcrumb + "  " + a.renderConnection() + " " +
+		StatusDescStyle.Render(a.now.Format("15:04"))
 
 	gap := a.width - lipgloss.Width(leftPart) - lipgloss.Width(rightPart) - 6
 	if gap < 0 {
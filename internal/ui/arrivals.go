@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/models"
+)
+
+// arrivalMarkerTTL is how long messages that came in with a refresh stay
+// marked as new if the selection doesn't move first
+const arrivalMarkerTTL = 8 * time.Second
+
+// arrivalsFadedMsg clears the new-mail markers set by the refresh with the
+// given generation, unless a later refresh replaced them
+type arrivalsFadedMsg struct {
+	gen int
+}
+
+// markArrivals remembers which emails in fresh weren't in prev, so the
+// rows they land in are marked after a refresh. A first load has nothing
+// to compare against and marks nothing, and with mark_junk_read neither
+// does spam.
+func (a *App) markArrivals(prev, fresh []models.Email) tea.Cmd {
+	if len(prev) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(prev))
+	for _, e := range prev {
+		seen[e.ID] = true
+	}
+	var junkID string
+	if a.cfg.MarkJunkRead {
+		for _, mb := range a.mailboxes {
+			if mb.Role == "junk" {
+				junkID = mb.ID
+			}
+		}
+	}
+	arrived := make(map[string]bool)
+	for _, e := range fresh {
+		if !seen[e.ID] && (junkID == "" || !inMailbox(e, junkID)) {
+			arrived[e.ID] = true
+		}
+	}
+	if len(arrived) == 0 {
+		return nil
+	}
+
+	a.arrived = arrived
+	a.arrivalsGen++
+	gen := a.arrivalsGen
+	return tea.Tick(arrivalMarkerTTL, func(time.Time) tea.Msg {
+		return arrivalsFadedMsg{gen: gen}
+	})
+}
+
+// inMailbox returns true if the email is filed in the given mailbox
+func inMailbox(e models.Email, mailboxID string) bool {
+	for _, id := range e.MailboxIDs {
+		if id == mailboxID {
+			return true
+		}
+	}
+	return false
+}
+
+// clearArrivals drops the new-mail markers
+func (a *App) clearArrivals() {
+	a.arrived = nil
+}
+
+// isArrival returns true if the thread holds an email that came in with
+// the last refresh
+func (a *App) isArrival(t Thread) bool {
+	for _, e := range t.Emails {
+		if a.arrived[e.ID] {
+			return true
+		}
+	}
+	return false
+}
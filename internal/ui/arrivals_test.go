@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/models"
+)
+
+func TestArrivalsOnlyWithinOneMailbox(t *testing.T) {
+	emails := func(ids ...string) []models.Email {
+		var out []models.Email
+		for _, id := range ids {
+			out = append(out, models.Email{ID: id, ThreadID: id, MailboxIDs: []string{"mb"}})
+		}
+		return out
+	}
+
+	a := &App{cfg: config.DefaultConfig(), leaving: map[string]bool{}}
+	a.Update(emailsLoadedMsg{emails: emails("a1", "a2"), mailboxID: "archive"})
+
+	a.Update(emailsLoadedMsg{emails: emails("i1", "i2"), mailboxID: "inbox", fresh: true})
+	if len(a.arrived) != 0 {
+		t.Errorf("switching folders marked %v as new", a.arrived)
+	}
+
+	a.Update(emailsLoadedMsg{emails: emails("i0", "i1", "i2"), mailboxID: "inbox", fresh: true})
+	if len(a.arrived) != 1 || !a.arrived["i0"] {
+		t.Errorf("refresh marked %v as new, want only i0", a.arrived)
+	}
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/ui/views"
+)
+
+// openFileBrowser lists files to attach to the message being written,
+// starting where the last one was picked
+func (a *App) openFileBrowser() tea.Cmd {
+	if a.composeView == nil {
+		return nil
+	}
+	a.fileBrowser = views.NewFileBrowserView(a.attachDir, a.composeView.Attachments(), a.width-26, a.height-6)
+	return nil
+}
+
+// handleFileBrowserKeys moves through the file browser; picking a file
+// attaches it, or takes it off again if it was already attached
+func (a *App) handleFileBrowserKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	a.fileBrowser, cmd = a.fileBrowser.Update(msg)
+	a.attachDir = a.fileBrowser.Dir()
+
+	if path, ok := a.fileBrowser.Chosen(); ok {
+		a.fileBrowser = nil
+		if a.composeView == nil {
+			return a, cmd
+		}
+		text := "Removed " + filepath.Base(path)
+		if a.composeView.ToggleAttachment(path) {
+			text = "Attached " + filepath.Base(path)
+		}
+		return a, tea.Batch(cmd, a.showToast(text, toastSuccess, 3*time.Second))
+	}
+	if a.fileBrowser.Closed() {
+		a.fileBrowser = nil
+	}
+	return a, cmd
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/config"
+)
+
+// autoOpenUnread opens unread mail in a folder that was just entered, as
+// auto_open_unread asks: the only unread conversation with "single", or
+// the top one with "first". Returns nil when there's nothing to open.
+func (a *App) autoOpenUnread() tea.Cmd {
+	mode := a.cfg.AutoOpenUnread
+	if mode != config.AutoOpenSingle && mode != config.AutoOpenFirst {
+		return nil
+	}
+
+	var unread []int
+	for i, t := range a.threads {
+		if t.UnreadCnt > 0 {
+			unread = append(unread, i)
+		}
+	}
+	if len(unread) == 0 || (mode == config.AutoOpenSingle && len(unread) > 1) {
+		return nil
+	}
+
+	a.selectedThread = unread[0]
+	if a.threadList != nil {
+		a.threadList.Select(a.selectedThread)
+	}
+	return a.openSelectedThread()
+}
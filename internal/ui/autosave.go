@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/storage"
+	"github.com/the9x/anneal/internal/ui/views"
+)
+
+// draftAutosaveMsg fires when it's time to save the compose view it was
+// scheduled for. It's dropped once that compose is closed.
+type draftAutosaveMsg struct {
+	view *views.ComposeView
+}
+
+// draftSavedMsg reports an autosave, to the server or, failing that, to
+// the local store
+type draftSavedMsg struct {
+	view     *views.ComposeView
+	id       string // email ID of the saved draft, empty if saved locally
+	snapshot string
+	local    bool
+	err      error
+}
+
+// pendingDraft is the last content of a compose closed while its
+// autosave was running
+type pendingDraft struct {
+	view    *views.ComposeView
+	message storage.OutboxMessage
+}
+
+// localDraftFlushedMsg reports that a draft left over from an offline
+// session was moved to the server's Drafts
+type localDraftFlushedMsg struct{}
+
+// composeMessage collects what's in the compose view, with the reply
+// headers of the original or of a message reopened from the outbox
+func (a *App) composeMessage() storage.OutboxMessage {
+	to, cc, subject, body := a.composeView.GetValues()
+	m := storage.OutboxMessage{
+		To:          to,
+		CC:          cc,
+		Subject:     subject,
+		Body:        body,
+		FromName:    a.composeView.GetFromName(),
+		Attachments: a.composeView.Attachments(),
+	}
+	// Get identity ID (or empty for default)
+	if identity := a.composeView.GetIdentity(); identity != nil {
+		m.IdentityID = identity.ID
+	}
+	if original := a.composeView.Original; original != nil {
+		m.InReplyTo = []string{original.ID}
+	} else if a.composeOutbox != nil {
+		m.InReplyTo = a.composeOutbox.Message.InReplyTo
+	}
+	return m
+}
+
+// scheduleAutosave queues the next autosave of the open compose
+func (a *App) scheduleAutosave() tea.Cmd {
+	if a.cfg.DraftAutosave <= 0 || a.composeView == nil {
+		return nil
+	}
+	view := a.composeView
+	return tea.Tick(time.Duration(a.cfg.DraftAutosave)*time.Second, func(time.Time) tea.Msg {
+		return draftAutosaveMsg{view: view}
+	})
+}
+
+// saveDraft saves the open compose to Drafts if it changed since the last
+// save, replacing the previous copy. When the server can't be reached the
+// content is kept in the local store instead.
+func (a *App) saveDraft() tea.Cmd {
+	if a.composeView == nil || a.draftSaving {
+		return nil
+	}
+	m := a.composeMessage()
+	if len(m.To) == 0 && len(m.CC) == 0 && strings.TrimSpace(m.Subject) == "" && a.composeView.IsEmpty() {
+		return nil
+	}
+	snapshot := fmt.Sprintf("%#v", m)
+	if snapshot == a.draftSnapshot {
+		return nil
+	}
+
+	a.draftSaving = true
+	return a.storeDraft(a.composeView, m, snapshot)
+}
+
+// storeDraft saves m as the view's draft, replacing its previous copy
+func (a *App) storeDraft(view *views.ComposeView, m storage.OutboxMessage, snapshot string) tea.Cmd {
+	replaceID := view.DraftID()
+	accountID := a.client.AccountID()
+
+	return func() tea.Msg {
+		id, err := a.client.SaveDraft(m.To, m.CC, m.Subject, m.Body, m.InReplyTo, m.IdentityID, m.FromName, replaceID)
+		if a.store == nil {
+			return draftSavedMsg{view: view, id: id, snapshot: snapshot, err: err}
+		}
+		if err != nil {
+			if storeErr := a.store.SaveLocalDraft(accountID, m); storeErr == nil {
+				return draftSavedMsg{view: view, snapshot: snapshot, local: true}
+			}
+			return draftSavedMsg{view: view, err: err}
+		}
+		// The server has it now, so an older offline copy can go
+		a.store.DeleteLocalDraft(accountID)
+		return draftSavedMsg{view: view, id: id, snapshot: snapshot}
+	}
+}
+
+// handleDraftSaved records where the open compose was saved
+func (a *App) handleDraftSaved(msg draftSavedMsg) tea.Cmd {
+	a.draftSaving = false
+
+	// Sent while this save was running; the draft it made is left over
+	if msg.view == a.sentCompose {
+		a.sentCompose = nil
+		if msg.id == "" && !msg.local {
+			return nil
+		}
+		id := msg.id
+		return func() tea.Msg {
+			a.deleteDrafts(id)
+			return nil
+		}
+	}
+	// Closed while this save was running; save what it ended with
+	if pending := a.closedDraft; pending != nil && msg.view == pending.view {
+		a.closedDraft = nil
+		if msg.err == nil && !msg.local {
+			msg.view.SetDraftID(msg.id)
+		}
+		snapshot := fmt.Sprintf("%#v", pending.message)
+		if msg.err == nil && snapshot == msg.snapshot {
+			return nil
+		}
+		a.draftSaving = true
+		return a.storeDraft(pending.view, pending.message, snapshot)
+	}
+	if msg.view != a.composeView || msg.err != nil {
+		// Autosave is silent; the next tick tries again
+		return nil
+	}
+
+	a.draftSnapshot = msg.snapshot
+	if msg.local {
+		a.draftStatus = "offline, saved locally " + time.Now().Format("15:04")
+		return nil
+	}
+	msg.view.SetDraftID(msg.id)
+	a.draftStatus = "draft saved " + time.Now().Format("15:04")
+	return nil
+}
+
+// deleteDrafts removes the autosaved copies of a message that was sent
+func (a *App) deleteDrafts(draftID string) {
+	if draftID != "" {
+		a.client.DeleteDraft(draftID)
+	}
+	if a.store != nil {
+		a.store.DeleteLocalDraft(a.client.AccountID())
+	}
+}
+
+// flushLocalDraft moves a draft saved while offline to the server's Drafts
+func (a *App) flushLocalDraft() tea.Msg {
+	accountID := a.client.AccountID()
+	m, err := a.store.GetLocalDraft(accountID)
+	if err != nil || m == nil {
+		return nil
+	}
+	if _, err := a.client.SaveDraft(m.To, m.CC, m.Subject, m.Body, m.InReplyTo, m.IdentityID, m.FromName, ""); err != nil {
+		return nil // Still offline; try again next start
+	}
+	a.store.DeleteLocalDraft(accountID)
+	return localDraftFlushedMsg{}
+}
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/jmap"
+	"github.com/the9x/anneal/internal/ui/views"
+)
+
+func TestCloseDuringAutosaveSavesAfterIt(t *testing.T) {
+	a := &App{cfg: config.DefaultConfig(), client: &jmap.Client{}}
+	view := views.NewComposeView(80, 24, nil)
+	a.composeView = view
+	a.viewState = ViewCompose
+	a.prevViewState = ViewMessages
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ann@example.com")})
+	if a.saveDraft() == nil {
+		t.Fatal("autosave didn't start")
+	}
+
+	// More typing, then esc while the save is still running
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(",bob@example.com")})
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.composeView != nil {
+		t.Fatal("compose still open")
+	}
+	if a.closedDraft == nil {
+		t.Fatal("closing during a save kept nothing to save")
+	}
+
+	_, cmd := a.Update(draftSavedMsg{view: view, id: "d1", snapshot: "only ann"})
+	if cmd == nil {
+		t.Fatal("no final save once the running save landed")
+	}
+	if view.DraftID() != "d1" {
+		t.Errorf("final save replaces %q, want d1", view.DraftID())
+	}
+	if !a.draftSaving || a.closedDraft != nil {
+		t.Errorf("draftSaving=%v closedDraft=%v after issuing the final save", a.draftSaving, a.closedDraft)
+	}
+}
@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/the9x/anneal/internal/config"
+)
+
+// senderBlockedMsg carries the cached messages from a newly blocked sender
+// that aren't in the block's folder yet
+type senderBlockedMsg struct {
+	addr     string
+	folder   string // display name of the folder blocked mail goes to
+	targetID string
+	ids      []string
+}
+
+// blockSender adds a rule sending future mail from the open email's sender
+// to Junk, or Trash when there's no Junk folder, then offers to move what's
+// already cached from them
+func (a *App) blockSender() tea.Cmd {
+	if a.currentEmail == nil || len(a.currentEmail.From) == 0 {
+		return nil
+	}
+	addr := strings.ToLower(a.currentEmail.From[0].Email)
+	if addr == "" {
+		return nil
+	}
+	if strings.EqualFold(addr, a.client.Email()) {
+		return a.showToast("Can't block your own address", toastError, 3*time.Second)
+	}
+
+	target := a.mailboxByRole("junk")
+	if target == nil {
+		target = a.mailboxByRole("trash")
+	}
+	if target == nil {
+		return a.showToast("No Junk or Trash folder to send blocked mail to", toastError, 4*time.Second)
+	}
+
+	if !a.cfg.BlockSender(addr, target.Role) {
+		return a.showToast(addr+" is already blocked", toastInfo, 3*time.Second)
+	}
+	if err := a.cfg.Save(); err != nil {
+		a.cfg.UnblockSender(addr)
+		return a.showToast("Block failed ✗: "+err.Error(), toastError, 5*time.Second)
+	}
+	if a.syncer != nil {
+		a.syncer.SetRules(a.cfg.Rules)
+	}
+
+	toast := a.showToast(fmt.Sprintf("Blocked %s, future mail goes to %s", addr, target.DisplayName()), toastSuccess, 4*time.Second)
+	if a.store == nil {
+		return toast
+	}
+
+	folder, targetID := target.DisplayName(), target.ID
+	accountID := a.client.AccountID()
+	return tea.Batch(toast, func() tea.Msg {
+		ids, _ := a.store.GetEmailIDsFrom(accountID, addr, targetID)
+		return senderBlockedMsg{addr: addr, folder: folder, targetID: targetID, ids: ids}
+	})
+}
+
+// handleSenderBlocked asks before moving a blocked sender's existing mail
+func (a *App) handleSenderBlocked(msg senderBlockedMsg) (tea.Model, tea.Cmd) {
+	if len(msg.ids) == 0 {
+		return a, nil
+	}
+	question := fmt.Sprintf("Also move %d existing messages from %s to %s?", len(msg.ids), msg.addr, msg.folder)
+	if len(msg.ids) == 1 {
+		question = fmt.Sprintf("Also move the existing message from %s to %s?", msg.addr, msg.folder)
+	}
+	return a.askConfirm(question, func() (tea.Model, tea.Cmd) {
+		return a, func() tea.Msg {
+			return emailActionMsg{err: a.client.MoveEmails(msg.ids, msg.targetID)}
+		}
+	})
+}
+
+// openBlockedList shows the blocked senders so a block can be undone
+func (a *App) openBlockedList() tea.Cmd {
+	if len(a.cfg.BlockedSenders()) == 0 {
+		return a.showToast("No blocked senders", toastInfo, 3*time.Second)
+	}
+	a.blockedList = true
+	a.blockedSelected = 0
+	return nil
+}
+
+// handleBlockedListKeys moves through the blocked senders; u, d or enter
+// unblocks the selected one
+func (a *App) handleBlockedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	senders := a.cfg.BlockedSenders()
+	switch msg.String() {
+	case "up", "k":
+		if a.blockedSelected > 0 {
+			a.blockedSelected--
+		}
+	case "down", "j":
+		if a.blockedSelected < len(senders)-1 {
+			a.blockedSelected++
+		}
+	case "u", "d", "enter":
+		if a.blockedSelected >= len(senders) {
+			return a, nil
+		}
+		addr := senders[a.blockedSelected]
+		prev := append([]config.Rule(nil), a.cfg.Rules...)
+		a.cfg.UnblockSender(addr)
+		if err := a.cfg.Save(); err != nil {
+			a.cfg.Rules = prev
+			return a, a.showToast("Unblock failed ✗: "+err.Error(), toastError, 5*time.Second)
+		}
+		if a.syncer != nil {
+			a.syncer.SetRules(a.cfg.Rules)
+		}
+		if len(a.cfg.BlockedSenders()) == 0 {
+			a.blockedList = false
+		}
+		a.blockedSelected = min(a.blockedSelected, max(len(senders)-2, 0))
+		return a, a.showToast("Unblocked "+addr, toastSuccess, 3*time.Second)
+	case "esc", "q", "B":
+		a.blockedList = false
+	}
+	return a, nil
+}
+
+// renderBlockedList renders the blocked senders in place of the main pane
+func (a *App) renderBlockedList(width int) string {
+	label := lipgloss.NewStyle().Foreground(ColorDim)
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render("◇ blocked senders"))
+	b.WriteString("\n\n")
+
+	for i, addr := range a.cfg.BlockedSenders() {
+		folder := "Junk"
+		for _, r := range a.cfg.Rules {
+			if r.Blocked && r.From == addr && r.MoveTo == "trash" {
+				folder = "Trash"
+			}
+		}
+		line := fmt.Sprintf("  %s  → %s", addr, folder)
+		style := lipgloss.NewStyle().Foreground(ColorSecondary)
+		if i == a.blockedSelected {
+			line = "▶" + line[1:]
+			style = lipgloss.NewStyle().Foreground(ColorPrimary).Background(ColorBgSelect)
+		}
+		b.WriteString(style.MaxWidth(width-4).Render(line) + "\n")
+	}
+
+	b.WriteString("\n" + label.Render("u: unblock  esc: close"))
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Padding(1, 2).
+		Render(b.String())
+}
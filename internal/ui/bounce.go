@@ -0,0 +1,365 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/models"
+	"github.com/the9x/anneal/internal/ui/views"
+)
+
+var (
+	// dsnRecipientRe matches the recipient fields of a delivery status
+	// notification
+	dsnRecipientRe = regexp.MustCompile(`(?im)^\s*(?:final|original)-recipient:\s*rfc822;\s*<?([^\s<>]+@[^\s<>]+?)>?\s*$`)
+
+	// bounceRecipientRe matches the "<address>: reason" lines Postfix and
+	// similar servers write in the readable part of a bounce
+	bounceRecipientRe = regexp.MustCompile(`(?m)^\s*<([^\s<>]+@[^\s<>]+)>:`)
+
+	// bounceSubjectRe matches the original subject when a bounce quotes
+	// the original headers in its text
+	bounceSubjectRe = regexp.MustCompile(`(?m)^\s*Subject:\s*(.+?)\s*$`)
+)
+
+// bounceSubjects are how delivery failure reports commonly start their
+// subject
+var bounceSubjects = []string{
+	"undelivered mail",
+	"undeliverable",
+	"delivery status notification",
+	"delivery failure",
+	"mail delivery failed",
+	"returned mail",
+	"failure notice",
+}
+
+// resendDraft is the original of a bounced message, ready to send again
+type resendDraft struct {
+	from    string
+	to, cc  []string
+	subject string
+	body    string
+}
+
+// bounceResendMsg carries the original of a bounce and the addresses it
+// couldn't be delivered to
+type bounceResendMsg struct {
+	draft  *resendDraft
+	failed []string
+	err    error
+}
+
+// isBounce returns true if email looks like a report that mail the user
+// sent couldn't be delivered
+func isBounce(email *models.Email) bool {
+	if email == nil {
+		return false
+	}
+	if len(email.From) > 0 {
+		local, _, _ := strings.Cut(strings.ToLower(email.From[0].Email), "@")
+		if local == "mailer-daemon" || local == "postmaster" {
+			return true
+		}
+	}
+	subject := strings.ToLower(email.Subject)
+	for _, prefix := range bounceSubjects {
+		if strings.HasPrefix(subject, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// bouncedRecipients returns the addresses a bounce reports as failed
+func bouncedRecipients(text string) []string {
+	var addrs []string
+	seen := make(map[string]bool)
+	for _, re := range []*regexp.Regexp{dsnRecipientRe, bounceRecipientRe} {
+		for _, m := range re.FindAllStringSubmatch(text, -1) {
+			addr := strings.ToLower(m[1])
+			if !seen[addr] {
+				seen[addr] = true
+				addrs = append(addrs, m[1])
+			}
+		}
+	}
+	return addrs
+}
+
+// resendBounce finds the original of the open bounce, from the message
+// attached to it or else from the copy in Sent, and opens it in compose
+// so the recipient can be corrected
+func (a *App) resendBounce() tea.Cmd {
+	email := a.currentEmail
+	if !isBounce(email) {
+		return a.showToast("Not a bounced message", toastInfo, 3*time.Second)
+	}
+	if !a.client.SupportsSubmission() {
+		return a.showToast("This server does not support sending email", toastError, 3*time.Second)
+	}
+	sent := a.mailboxByRole("sent")
+
+	return func() tea.Msg {
+		report := email.TextBody
+		var draft *resendDraft
+		for _, att := range email.Attachments {
+			switch strings.ToLower(att.Type) {
+			case "message/delivery-status", "message/global-delivery-status":
+				if data, err := a.client.DownloadBlob(att.BlobID, att.Name); err == nil {
+					report += "\n" + string(data)
+				}
+			case "message/rfc822", "message/global", "text/rfc822-headers":
+				if draft != nil {
+					continue
+				}
+				data, err := a.client.DownloadBlob(att.BlobID, att.Name)
+				if err != nil {
+					return bounceResendMsg{err: err}
+				}
+				if d, err := parseOriginal(data); err == nil {
+					draft = d
+				}
+			}
+		}
+		failed := bouncedRecipients(report)
+
+		// Only the headers came back, or none at all: the copy in Sent
+		// has the body
+		if draft == nil || draft.body == "" {
+			subject := ""
+			if draft != nil {
+				subject = draft.subject
+			} else if m := bounceSubjectRe.FindStringSubmatch(report); m != nil {
+				subject = m[1]
+			}
+			if original := a.findSentCopy(sent, subject, failed); original != nil {
+				draft = draftFromEmail(original)
+			}
+		}
+		if draft == nil {
+			return bounceResendMsg{err: fmt.Errorf("couldn't find the original message in the bounce or in Sent")}
+		}
+		return bounceResendMsg{draft: draft, failed: failed}
+	}
+}
+
+// findSentCopy returns the newest message in Sent with the given subject,
+// sent to one of the failed addresses when any are known, with its body
+func (a *App) findSentCopy(sent *models.Mailbox, subject string, failed []string) *models.Email {
+	if sent == nil || strings.TrimSpace(subject) == "" {
+		return nil
+	}
+	var emails []models.Email
+	var err error
+	if a.store != nil {
+		emails, err = a.store.GetEmails(sent.ID, 500)
+	}
+	if a.store == nil || err != nil || len(emails) == 0 {
+		emails, err = a.client.GetEmails(sent.ID, a.cfg.PageSize)
+		if err != nil {
+			return nil
+		}
+	}
+
+	for _, e := range emails {
+		if !strings.EqualFold(strings.TrimSpace(e.Subject), strings.TrimSpace(subject)) || !sentToAny(&e, failed) {
+			continue
+		}
+		full, err := a.client.GetEmail(e.ID)
+		if err != nil {
+			return nil
+		}
+		return full
+	}
+	return nil
+}
+
+// sentToAny returns true if email went to one of addrs, or if there are
+// none to check
+func sentToAny(email *models.Email, addrs []string) bool {
+	if len(addrs) == 0 {
+		return true
+	}
+	for _, list := range [][]models.EmailAddress{email.To, email.CC, email.BCC} {
+		for _, r := range list {
+			for _, addr := range addrs {
+				if strings.EqualFold(r.Email, addr) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// draftFromEmail makes a resend draft from a message the user sent
+func draftFromEmail(email *models.Email) *resendDraft {
+	d := &resendDraft{subject: email.Subject, body: email.TextBody}
+	if len(email.From) > 0 {
+		d.from = email.From[0].Email
+	}
+	for _, r := range email.To {
+		d.to = append(d.to, r.String())
+	}
+	for _, r := range email.CC {
+		d.cc = append(d.cc, r.String())
+	}
+	return d
+}
+
+// parseOriginal reads the message attached to a bounce: its recipients,
+// subject and plain-text body. The body is empty when only the headers
+// were returned.
+func parseOriginal(raw []byte) (*resendDraft, error) {
+	// text/rfc822-headers has no body; give it an empty one to parse
+	if !bytes.Contains(raw, []byte("\n\n")) && !bytes.Contains(raw, []byte("\r\n\r\n")) {
+		raw = append(raw, "\r\n\r\n"...)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	dec := new(mime.WordDecoder)
+	subject, err := dec.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+
+	d := &resendDraft{
+		to:      headerAddresses(msg.Header, "To"),
+		cc:      headerAddresses(msg.Header, "Cc"),
+		subject: subject,
+	}
+	if from := headerAddresses(msg.Header, "From"); len(from) > 0 {
+		if addr, err := mail.ParseAddress(from[0]); err == nil {
+			d.from = addr.Address
+		}
+	}
+	d.body = plainText(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	return d, nil
+}
+
+// headerAddresses returns the addresses in a header, as written when they
+// don't parse
+func headerAddresses(h mail.Header, name string) []string {
+	value := h.Get(name)
+	if value == "" {
+		return nil
+	}
+	list, err := h.AddressList(name)
+	if err != nil {
+		return splitHeader(value)
+	}
+	addrs := make([]string, len(list))
+	for i, addr := range list {
+		addrs[i] = addr.Address
+		if addr.Name != "" {
+			addrs[i] = addr.Name + " <" + addr.Address + ">"
+		}
+	}
+	return addrs
+}
+
+// splitHeader splits a comma-separated header into trimmed entries
+func splitHeader(value string) []string {
+	var out []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// plainText returns the first text/plain part of a message body,
+// decoded, or "" if it has none
+func plainText(contentType, encoding string, body io.Reader) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if contentType == "" || err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err != nil {
+				return ""
+			}
+			if text := plainText(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part); text != "" {
+				return text
+			}
+		}
+	}
+	if mediaType != "text/plain" {
+		return ""
+	}
+
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, &lineJoiner{r: body})
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil && len(data) == 0 {
+		return ""
+	}
+	return strings.ReplaceAll(string(data), "\r\n", "\n")
+}
+
+// lineJoiner drops the line breaks base64 bodies are wrapped with
+type lineJoiner struct {
+	r io.Reader
+}
+
+func (l *lineJoiner) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	j := 0
+	for _, c := range p[:n] {
+		if c != '\r' && c != '\n' {
+			p[j] = c
+			j++
+		}
+	}
+	return j, err
+}
+
+// handleBounceResend opens the original of a bounce in compose with the
+// cursor on its recipients, and says which addresses failed
+func (a *App) handleBounceResend(msg bounceResendMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return a, a.showToast("Couldn't resend: "+msg.err.Error(), toastError, 5*time.Second)
+	}
+	if a.viewState != ViewEmail {
+		return a, nil
+	}
+
+	_, cmd := a.startCompose(nil, views.ModeCompose)
+	if a.composeView == nil {
+		return a, cmd
+	}
+	d := msg.draft
+	a.composeView.SetDraft(d.to, d.cc, d.subject, d.body, "", "")
+	if d.from != "" {
+		a.composeView.SelectIdentityByEmail(d.from)
+	}
+	a.composeView.FocusField(views.FieldTo)
+
+	text := "Fix the recipient and send again"
+	if len(msg.failed) > 0 {
+		text = "Couldn't be delivered to " + strings.Join(msg.failed, ", ") + " — fix the address and send again"
+	}
+	return a, tea.Batch(cmd, a.showToast(text, toastInfo, 8*time.Second))
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/the9x/anneal/internal/models"
+)
+
+// postfixBounce is the readable part of a Postfix bounce
+const postfixBounce = `This is the mail system at host mail.example.com.
+
+I'm sorry to have to inform you that your message could not
+be delivered to one or more recipients. It's attached below.
+
+                   The mail system
+
+<bob@exmaple.org>: host mx.exmaple.org[192.0.2.1] said: 550 5.1.1
+    <bob@exmaple.org>: Recipient address rejected: User unknown (in reply to
+    RCPT TO command)
+<Carol@Exmaple.org>: host mx.exmaple.org[192.0.2.1] said: 550 5.1.1 unknown
+`
+
+// dsnReport is an RFC 3464 multipart/report, with the readable text, the
+// delivery status and the returned headers
+const dsnReport = "Content-Type: multipart/report; report-type=delivery-status; boundary=\"b1\"\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"Delivery to the following recipient failed permanently:\r\n" +
+	"\r\n" +
+	"     dave@example.net\r\n" +
+	"--b1\r\n" +
+	"Content-Type: message/delivery-status\r\n" +
+	"\r\n" +
+	"Reporting-MTA: dns; mx.example.com\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; dave@example.net\r\n" +
+	"Original-Recipient: rfc822;<dave@example.net>\r\n" +
+	"Action: failed\r\n" +
+	"Status: 5.1.1\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/rfc822-headers\r\n" +
+	"\r\n" +
+	"Subject: Lunch\r\n" +
+	"--b1--\r\n"
+
+func TestIsBounce(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		subject string
+		want    bool
+	}{
+		{"postfix", "MAILER-DAEMON@mail.example.com", "Undelivered Mail Returned to Sender", true},
+		{"postmaster", "postmaster@example.com", "Warning", true},
+		{"gmail dsn", "mailer-daemon@googlemail.com", "Delivery Status Notification (Failure)", true},
+		{"subject only", "noreply@example.com", "Mail delivery failed: returning message to sender", true},
+		{"delivery update", "orders@shop.example", "Your delivery is on its way", false},
+		{"reply about a failure", "ann@example.com", "Re: delivery failure of the parcel", false},
+		{"mentions delivery", "ann@example.com", "Delivery times for the new office", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email := &models.Email{From: []models.EmailAddress{{Email: tt.from}}, Subject: tt.subject}
+			if got := isBounce(email); got != tt.want {
+				t.Errorf("isBounce(%q, %q) = %v, want %v", tt.from, tt.subject, got, tt.want)
+			}
+		})
+	}
+	if isBounce(nil) {
+		t.Error("isBounce(nil) = true")
+	}
+}
+
+func TestBouncedRecipients(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"postfix", postfixBounce, []string{"bob@exmaple.org", "Carol@Exmaple.org"}},
+		{"dsn", dsnReport, []string{"dave@example.net"}},
+		{"not a bounce", "Your parcel is out for delivery to <ann@example.com> today.", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := bouncedRecipients(tt.text)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("bouncedRecipients = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOriginal(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want resendDraft
+	}{
+		{
+			"plain",
+			"From: Ann <ann@example.com>\r\nTo: Bob <bob@exmaple.org>, carol@exmaple.org\r\n" +
+				"Cc: dave@example.net\r\nSubject: Lunch\r\n\r\nFriday?\r\n",
+			resendDraft{from: "ann@example.com", to: []string{"Bob <bob@exmaple.org>", "carol@exmaple.org"},
+				cc: []string{"dave@example.net"}, subject: "Lunch", body: "Friday?\n"},
+		},
+		{
+			"headers only",
+			"From: ann@example.com\r\nTo: bob@exmaple.org\r\nSubject: =?UTF-8?Q?Caf=C3=A9?=",
+			resendDraft{from: "ann@example.com", to: []string{"bob@exmaple.org"}, subject: "Café"},
+		},
+		{
+			"multipart quoted-printable",
+			"From: ann@example.com\r\nTo: bob@exmaple.org\r\nSubject: Hi\r\n" +
+				"Content-Type: multipart/alternative; boundary=\"x\"\r\n\r\n" +
+				"--x\r\nContent-Type: text/html\r\n\r\n<p>Hi</p>\r\n" +
+				"--x\r\nContent-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n" +
+				"caf=C3=A9 at noon=\r\n?\r\n--x--\r\n",
+			resendDraft{from: "ann@example.com", to: []string{"bob@exmaple.org"}, subject: "Hi", body: "café at noon?"},
+		},
+		{
+			"wrapped base64",
+			"From: ann@example.com\r\nTo: bob@exmaple.org\r\nSubject: Hi\r\n" +
+				"Content-Transfer-Encoding: base64\r\n\r\n" +
+				"SGVsbG8s\r\nIEJvYiE=\r\n",
+			resendDraft{from: "ann@example.com", to: []string{"bob@exmaple.org"}, subject: "Hi", body: "Hello, Bob!"},
+		},
+		{
+			"unparsable recipients",
+			"From: ann@example.com\r\nTo: bob@exmaple.org, <broken\r\nSubject: Hi\r\n\r\n",
+			resendDraft{from: "ann@example.com", to: []string{"bob@exmaple.org", "<broken"}, subject: "Hi"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOriginal([]byte(tt.raw))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.from != tt.want.from || got.subject != tt.want.subject || got.body != tt.want.body ||
+				strings.Join(got.to, "|") != strings.Join(tt.want.to, "|") ||
+				strings.Join(got.cc, "|") != strings.Join(tt.want.cc, "|") {
+				t.Errorf("parseOriginal = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainTextOfDSN(t *testing.T) {
+	got := plainText(`multipart/report; report-type=delivery-status; boundary="b1"`, "",
+		strings.NewReader(dsnReport[strings.Index(dsnReport, "--b1"):]))
+	want := "Delivery to the following recipient failed permanently:\n\n     dave@example.net"
+	if got != want {
+		t.Errorf("plainText = %q, want %q", got, want)
+	}
+}
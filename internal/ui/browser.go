@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/models"
+	"github.com/the9x/anneal/internal/storage"
+)
+
+// cidPattern matches cid: references in HTML attributes and CSS urls
+var cidPattern = regexp.MustCompile(`cid:([^"'\s)>]+)`)
+
+var (
+	// remotePattern matches attributes and CSS urls that load from the web
+	remotePattern = regexp.MustCompile(`(?i)(?:\b(?:src|srcset|background|poster)\s*=\s*["']?\s*|url\(\s*["']?\s*)(?:https?:)?//`)
+
+	headPattern = regexp.MustCompile(`(?i)<head[^>]*>`)
+	htmlPattern = regexp.MustCompile(`(?i)<html[^>]*>`)
+)
+
+// blockRemotePolicy stops the browser fetching anything from the network,
+// which keeps tracking pixels from reporting the message was opened.
+// Embedded images and inline styles still work.
+const blockRemotePolicy = `<meta http-equiv="Content-Security-Policy" content="default-src 'none'; ` +
+	`img-src data:; style-src 'unsafe-inline' data:; font-src data:; media-src data:">`
+
+// browserOpenedMsg reports whether the HTML view could be opened
+type browserOpenedMsg struct {
+	err error
+}
+
+// openFile opens a path with the platform's default application
+func openFile(path string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", path)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+	return cmd.Start()
+}
+
+// openHTML opens the email's HTML in the browser. Remote images load
+// right away for trusted senders; otherwise the user is asked whether to
+// load them this once, always for this sender, or not at all.
+func (a *App) openHTML(email *models.Email) (tea.Model, tea.Cmd) {
+	sender := ""
+	if len(email.From) > 0 {
+		sender = strings.ToLower(email.From[0].Email)
+	}
+	if !remotePattern.MatchString(email.HTMLBody) || (sender != "" && a.cfg.IsTrustedSender(sender)) {
+		return a, a.openInBrowser(email, true)
+	}
+
+	a.confirm = &confirmation{
+		question: "Load remote images? They can tell the sender you opened it.",
+		onYes: func() (tea.Model, tea.Cmd) {
+			return a, a.openInBrowser(email, true)
+		},
+		noLabel: "open without",
+		onNo: func() (tea.Model, tea.Cmd) {
+			return a, a.openInBrowser(email, false)
+		},
+	}
+	if sender != "" {
+		a.confirm.altKey = "a"
+		a.confirm.altLabel = "always for " + sender
+		a.confirm.onAlt = func() (tea.Model, tea.Cmd) {
+			a.cfg.TrustSender(sender)
+			if err := a.cfg.Save(); err != nil {
+				return a, tea.Batch(a.openInBrowser(email, true),
+					a.showToast("Couldn't save trusted sender: "+err.Error(), toastError, 5*time.Second))
+			}
+			return a, a.openInBrowser(email, true)
+		}
+	}
+	return a, nil
+}
+
+// openInBrowser writes the email's original HTML to a temp file and opens
+// it in the default browser, with remote content blocked unless allowed
+func (a *App) openInBrowser(email *models.Email, allowRemote bool) tea.Cmd {
+	return func() tea.Msg {
+		data, err := a.buildStandaloneHTML(email, allowRemote)
+		if err != nil {
+			return browserOpenedMsg{err: err}
+		}
+
+		dir := storage.TempDir(a.cfg.CacheDir, "html")
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return browserOpenedMsg{err: fmt.Errorf("failed to create temp dir: %w", err)}
+		}
+
+		path := filepath.Join(dir, email.ID+".html")
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return browserOpenedMsg{err: fmt.Errorf("failed to save HTML: %w", err)}
+		}
+
+		if err := openFile(path); err != nil {
+			return browserOpenedMsg{err: fmt.Errorf("failed to open browser: %w", err)}
+		}
+		return browserOpenedMsg{}
+	}
+}
+
+// buildStandaloneHTML returns the email's HTML body with cid: images
+// replaced by data URIs, so it renders without access to the mail server.
+// Images that can't be downloaded keep their cid: reference. Without
+// allowRemote, a content policy stops anything loading from the web.
+func (a *App) buildStandaloneHTML(email *models.Email, allowRemote bool) ([]byte, error) {
+	if email.HTMLBody == "" {
+		return nil, fmt.Errorf("this email has no HTML version")
+	}
+
+	byCID := make(map[string]models.Attachment)
+	for _, att := range email.Attachments {
+		if att.CID != "" {
+			byCID[strings.Trim(att.CID, "<>")] = att
+		}
+	}
+
+	dataURIs := make(map[string]string)
+	body := cidPattern.ReplaceAllStringFunc(email.HTMLBody, func(ref string) string {
+		cid := strings.TrimPrefix(ref, "cid:")
+		if uri, ok := dataURIs[cid]; ok {
+			return uri
+		}
+
+		att, ok := byCID[cid]
+		if !ok {
+			return ref
+		}
+		data, err := a.attachmentData(&att)
+		if err != nil {
+			return ref
+		}
+
+		uri := "data:" + att.Type + ";base64," + base64.StdEncoding.EncodeToString(data)
+		dataURIs[cid] = uri
+		return uri
+	})
+
+	// Fragments need a charset so the browser doesn't guess
+	if !strings.Contains(strings.ToLower(body), "<html") {
+		body = "<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>" +
+			html.EscapeString(email.Subject) + "</title></head><body>\n" + body + "\n</body></html>\n"
+	}
+	if !allowRemote {
+		body = blockRemote(body)
+	}
+
+	return []byte(body), nil
+}
+
+// blockRemote adds the content policy to the document's head, creating
+// one if there isn't any
+func blockRemote(doc string) string {
+	if loc := headPattern.FindStringIndex(doc); loc != nil {
+		return doc[:loc[1]] + blockRemotePolicy + doc[loc[1]:]
+	}
+	if loc := htmlPattern.FindStringIndex(doc); loc != nil {
+		return doc[:loc[1]] + "<head>" + blockRemotePolicy + "</head>" + doc[loc[1]:]
+	}
+	return blockRemotePolicy + doc
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/models"
+)
+
+// bulkAction remembers the last multi-thread move so it can be undone
+type bulkAction struct {
+	name     string              // "delete" or "archive", for messages
+	original map[string][]string // email ID → mailbox IDs before the move
+}
+
+// bulkDoneMsg reports the result of a bulk move or its undo
+type bulkDoneMsg struct {
+	action *bulkAction // set when the move can be undone
+	undone bool
+	err    error
+}
+
+// toggleMark adds or removes a thread from the multi-selection
+func (a *App) toggleMark(threadID string) {
+	if a.marked[threadID] {
+		delete(a.marked, threadID)
+	} else {
+		a.marked[threadID] = true
+	}
+}
+
+// markedEmails returns every email in the marked threads
+func (a *App) markedEmails() []models.Email {
+	var emails []models.Email
+	for _, t := range a.threads {
+		if a.marked[t.ID] {
+			emails = append(emails, t.Emails...)
+		}
+	}
+	return emails
+}
+
+// newBulkAction records where each email is before a move. Cached emails
+// don't carry their mailboxes; they came from the current folder.
+func (a *App) newBulkAction(name string, emails []models.Email) *bulkAction {
+	var currentID string
+	if a.selectedMailbox < len(a.mailboxes) && !a.mailboxes[a.selectedMailbox].IsVirtual() {
+		currentID = a.mailboxes[a.selectedMailbox].ID
+	}
+
+	action := &bulkAction{name: name, original: make(map[string][]string)}
+	for _, e := range emails {
+		switch {
+		case len(e.MailboxIDs) > 0:
+			action.original[e.ID] = e.MailboxIDs
+		case currentID != "":
+			action.original[e.ID] = []string{currentID}
+		}
+	}
+	return action
+}
+
+// mailboxIDByRole returns the ID of the mailbox with the given role
+func (a *App) mailboxIDByRole(role string) string {
+	if mb := a.mailboxByRole(role); mb != nil {
+		return mb.ID
+	}
+	return ""
+}
+
+// bulkMove moves every email in the marked threads to the mailbox with
+// the given role in one request, remembering where each email was so the
+// move can be undone
+func (a *App) bulkMove(name, role string) tea.Cmd {
+	emails := a.markedEmails()
+	a.marked = make(map[string]bool)
+	return a.moveEmails(name, role, emails)
+}
+
+// moveEmails moves emails to the mailbox with the given role in one
+// request, remembering where each was so the move can be undone
+func (a *App) moveEmails(name, role string, emails []models.Email) tea.Cmd {
+	if len(emails) == 0 {
+		return nil
+	}
+
+	action := a.newBulkAction(name, emails)
+	targetID := a.mailboxIDByRole(role)
+
+	return func() tea.Msg {
+		if targetID == "" {
+			return bulkDoneMsg{err: fmt.Errorf("%s mailbox not found", role)}
+		}
+
+		ids := make([]string, 0, len(emails))
+		for _, e := range emails {
+			ids = append(ids, e.ID)
+		}
+
+		if err := a.client.MoveEmails(ids, targetID); err != nil {
+			return bulkDoneMsg{err: err}
+		}
+		return bulkDoneMsg{action: action}
+	}
+}
+
+// archiveAllRead asks before archiving every conversation in the list
+// that has no unread mail left, in one request
+func (a *App) archiveAllRead() (tea.Model, tea.Cmd) {
+	mb := a.currentMailbox()
+	if mb == nil || mb.IsVirtual() || mb.Role == "archive" {
+		return a, nil
+	}
+
+	var threadIDs []string
+	var emails []models.Email
+	for _, t := range a.threads {
+		if t.UnreadCnt == 0 && !a.leaving[t.ID] {
+			threadIDs = append(threadIDs, t.ID)
+			emails = append(emails, t.Emails...)
+		}
+	}
+	if len(threadIDs) == 0 {
+		return a, a.showToast("No read conversations to archive", toastInfo, 3*time.Second)
+	}
+
+	question := fmt.Sprintf("Archive %d read conversations?", len(threadIDs))
+	if len(threadIDs) == 1 {
+		question = "Archive 1 read conversation?"
+	}
+	return a.askConfirm(question, func() (tea.Model, tea.Cmd) {
+		mb.TotalEmails = max(mb.TotalEmails-len(emails), 0)
+		return a, a.moveEmails("archive", "archive", emails)
+	})
+}
+
+// undoBulk puts the emails of the last bulk move back where they were
+func (a *App) undoBulk() tea.Cmd {
+	action := a.lastBulk
+	a.lastBulk = nil
+	if action == nil {
+		return nil
+	}
+	a.leaving = make(map[string]bool)
+
+	return func() tea.Msg {
+		err := a.client.SetEmailMailboxes(action.original)
+		return bulkDoneMsg{undone: err == nil, err: err}
+	}
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/the9x/anneal/internal/models"
+)
+
+// confirmation is a yes/no question shown in place of the help bar. The
+// action runs only if the answer is yes.
+type confirmation struct {
+	question string
+	onYes    func() (tea.Model, tea.Cmd)
+
+	// An optional third answer, offered beside yes
+	altKey   string
+	altLabel string
+	onAlt    func() (tea.Model, tea.Cmd)
+
+	// An optional action for n, when no does more than cancel
+	noLabel string
+	onNo    func() (tea.Model, tea.Cmd)
+}
+
+// askConfirm holds an action until the user answers the question
+func (a *App) askConfirm(question string, onYes func() (tea.Model, tea.Cmd)) (tea.Model, tea.Cmd) {
+	a.confirm = &confirmation{question: question, onYes: onYes}
+	return a, nil
+}
+
+// handleConfirmKeys answers the open question. y or enter runs the action,
+// and n the question's own no if it has one; any other key, esc and
+// ctrl+c included, cancels, so a stray keypress is always safe.
+func (a *App) handleConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := a.confirm
+	a.confirm = nil
+	switch msg.String() {
+	case "y", "Y", "enter":
+		return c.onYes()
+	case "n", "N":
+		if c.onNo != nil {
+			return c.onNo()
+		}
+	}
+	if c.onAlt != nil && msg.String() == c.altKey {
+		return c.onAlt()
+	}
+	return a, nil
+}
+
+// renderConfirm renders the open question in the help bar
+func (a *App) renderConfirm() string {
+	return lipgloss.NewStyle().
+		Background(ColorBg).
+		Padding(0, 2).
+		Width(a.width).
+		Render(lipgloss.NewStyle().Foreground(ColorAccent).Bold(true).Render(a.confirm.question) +
+			"  " + HelpKeyStyle.Render("y/enter") + HelpSepStyle.Render(":") + HelpDescStyle.Render("yes") +
+			a.confirmAltHelp() + a.confirmNoHelp() +
+			HelpSepStyle.Render(" │ ") +
+			HelpKeyStyle.Render("any other key") + HelpSepStyle.Render(":") + HelpDescStyle.Render("cancel"))
+}
+
+// confirmAltHelp describes the third answer, if the question has one
+func (a *App) confirmAltHelp() string {
+	if a.confirm.onAlt == nil {
+		return ""
+	}
+	return HelpSepStyle.Render(" │ ") +
+		HelpKeyStyle.Render(a.confirm.altKey) + HelpSepStyle.Render(":") + HelpDescStyle.Render(a.confirm.altLabel)
+}
+
+// confirmNoHelp describes what n does, if the question has its own no
+func (a *App) confirmNoHelp() string {
+	if a.confirm.onNo == nil {
+		return ""
+	}
+	return HelpSepStyle.Render(" │ ") +
+		HelpKeyStyle.Render("n") + HelpSepStyle.Render(":") + HelpDescStyle.Render(a.confirm.noLabel)
+}
+
+// replyAllRecipients counts the distinct To and Cc addresses of an email
+func replyAllRecipients(email *models.Email) int {
+	seen := make(map[string]bool)
+	for _, addr := range append(append([]models.EmailAddress(nil), email.To...), email.CC...) {
+		seen[strings.ToLower(addr.Email)] = true
+	}
+	return len(seen)
+}
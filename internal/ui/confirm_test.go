@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestConfirmOnlyNRunsNo(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     tea.KeyMsg
+		wantYes bool
+		wantNo  bool
+	}{
+		{"y", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}, true, false},
+		{"enter", tea.KeyMsg{Type: tea.KeyEnter}, true, false},
+		{"n", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")}, false, true},
+		{"esc", tea.KeyMsg{Type: tea.KeyEsc}, false, false},
+		{"ctrl+c", tea.KeyMsg{Type: tea.KeyCtrlC}, false, false},
+		{"other", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var yes, no bool
+			a := &App{}
+			a.confirm = &confirmation{
+				question: "Load remote images?",
+				onYes:    func() (tea.Model, tea.Cmd) { yes = true; return a, nil },
+				noLabel:  "open without",
+				onNo:     func() (tea.Model, tea.Cmd) { no = true; return a, nil },
+			}
+			a.Update(tt.key)
+			if yes != tt.wantYes || no != tt.wantNo {
+				t.Errorf("yes=%v no=%v, want yes=%v no=%v", yes, no, tt.wantYes, tt.wantNo)
+			}
+			if a.confirm != nil {
+				t.Error("question still open")
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/the9x/anneal/internal/storage"
+)
+
+// contactPickerLimit caps how many cached contacts the picker lists
+const contactPickerLimit = 200
+
+// contactsLoadedMsg carries the cached contacts for the recipient picker
+type contactsLoadedMsg struct {
+	contacts []storage.Contact
+	err      error
+}
+
+// openContactPicker loads frequent contacts from the cache so several can
+// be added to the focused recipient field at once
+func (a *App) openContactPicker() tea.Cmd {
+	if a.store == nil {
+		return a.showToast("Contacts need the cache, which is off", toastError, 3*time.Second)
+	}
+	accountID := a.client.AccountID()
+	return func() tea.Msg {
+		contacts, err := a.store.SuggestContacts(accountID, "", contactPickerLimit)
+		return contactsLoadedMsg{contacts: contacts, err: err}
+	}
+}
+
+// handleContactsLoaded shows the picker, leaving out the user's own
+// addresses
+func (a *App) handleContactsLoaded(msg contactsLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return a, a.showToast("Couldn't load contacts: "+msg.err.Error(), toastError, 4*time.Second)
+	}
+	if a.viewState != ViewCompose || a.composeView == nil {
+		return a, nil
+	}
+
+	var contacts []storage.Contact
+	for _, c := range msg.contacts {
+		if !a.isOwnAddress(c.Email) {
+			contacts = append(contacts, c)
+		}
+	}
+	if len(contacts) == 0 {
+		return a, a.showToast("No contacts in the cache yet", toastInfo, 3*time.Second)
+	}
+
+	a.contacts = contacts
+	a.contactSelected = 0
+	a.contactChecked = make(map[string]bool)
+	a.contactPicker = true
+	return a, nil
+}
+
+// handleContactPickerKeys moves through the contacts; space checks one and
+// enter adds the checked contacts, or the selected one if none are
+func (a *App) handleContactPickerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if a.contactSelected > 0 {
+			a.contactSelected--
+		}
+	case "down", "j":
+		if a.contactSelected < len(a.contacts)-1 {
+			a.contactSelected++
+		}
+	case " ", "x":
+		if a.contactSelected < len(a.contacts) {
+			addr := a.contacts[a.contactSelected].Email
+			a.contactChecked[addr] = !a.contactChecked[addr]
+			if a.contactSelected < len(a.contacts)-1 {
+				a.contactSelected++
+			}
+		}
+	case "enter":
+		var addrs []string
+		for _, c := range a.contacts {
+			if a.contactChecked[c.Email] {
+				addrs = append(addrs, c.Email)
+			}
+		}
+		if len(addrs) == 0 && a.contactSelected < len(a.contacts) {
+			addrs = []string{a.contacts[a.contactSelected].Email}
+		}
+		a.closeContactPicker()
+		if a.composeView == nil || len(addrs) == 0 {
+			return a, nil
+		}
+		field := a.composeView.AddRecipients(addrs)
+		text := fmt.Sprintf("Added %d recipients to %s", len(addrs), field)
+		if len(addrs) == 1 {
+			text = fmt.Sprintf("Added %s to %s", addrs[0], field)
+		}
+		return a, a.showToast(text, toastSuccess, 3*time.Second)
+	case "esc", "q", "ctrl+r":
+		a.closeContactPicker()
+	}
+	return a, nil
+}
+
+// closeContactPicker hides the picker and forgets its selection
+func (a *App) closeContactPicker() {
+	a.contactPicker = false
+	a.contacts = nil
+	a.contactChecked = nil
+}
+
+// renderContactPicker renders the contacts in place of the compose form,
+// scrolled to keep the selection in view
+func (a *App) renderContactPicker(width int) string {
+	label := lipgloss.NewStyle().Foreground(ColorDim)
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render("◇ add recipients"))
+	checked := 0
+	for _, on := range a.contactChecked {
+		if on {
+			checked++
+		}
+	}
+	if checked > 0 {
+		b.WriteString(label.Render(fmt.Sprintf("  %d selected", checked)))
+	}
+	b.WriteString("\n\n")
+
+	rows := max(a.height-8, 3)
+	start := 0
+	if a.contactSelected >= rows {
+		start = a.contactSelected - rows + 1
+	}
+	end := min(start+rows, len(a.contacts))
+
+	for i := start; i < end; i++ {
+		c := a.contacts[i]
+		box := "[ ]"
+		if a.contactChecked[c.Email] {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("  %s %s", box, c.Email)
+		if c.Name != "" && !strings.EqualFold(c.Name, c.Email) {
+			line = fmt.Sprintf("  %s %s <%s>", box, c.Name, c.Email)
+		}
+		style := lipgloss.NewStyle().Foreground(ColorSecondary)
+		if i == a.contactSelected {
+			line = "▶" + line[1:]
+			style = lipgloss.NewStyle().Foreground(ColorPrimary).Background(ColorBgSelect)
+		}
+		b.WriteString(style.MaxWidth(width-4).Render(line) + "\n")
+	}
+
+	b.WriteString("\n" + label.Render("space: select  enter: add  esc: close"))
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Padding(1, 2).
+		Render(b.String())
+}
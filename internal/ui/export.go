@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/models"
+	"github.com/the9x/anneal/internal/ui/views"
+)
+
+// threadExportedMsg is sent once a thread was copied or written out
+type threadExportedMsg struct {
+	path string // file written, empty when copied to the clipboard
+	err  error
+}
+
+// exportSelectedThread exports the open conversation to the clipboard, or
+// to a file in export_dir when that's set. Bodies that aren't cached yet
+// are fetched first so every message is complete.
+func (a *App) exportSelectedThread() tea.Cmd {
+	if a.selectedThread >= len(a.threads) {
+		return nil
+	}
+	thread := a.threads[a.selectedThread]
+	thread.Emails = append([]models.Email(nil), thread.Emails...)
+
+	export := func() tea.Msg {
+		if err := a.loadThreadBodies(thread.Emails); err != nil {
+			return threadExportedMsg{err: err}
+		}
+		doc := a.exportThread(thread)
+
+		if a.cfg.ExportDir == "" {
+			return threadExportedMsg{err: clipboard.WriteAll(doc)}
+		}
+		path, err := writeExport(a.cfg.ExportDir, thread.Subject, a.cfg.ExportFormat, doc)
+		return threadExportedMsg{path: path, err: err}
+	}
+
+	return tea.Batch(a.showToast("Exporting conversation…", toastInfo, 0), export)
+}
+
+// loadThreadBodies fills in the body of each email, from the cache where
+// possible and from the server for the rest
+func (a *App) loadThreadBodies(emails []models.Email) error {
+	var missing []string
+	for i := range emails {
+		if emails[i].TextBody != "" || emails[i].HTMLBody != "" {
+			continue
+		}
+		if a.store != nil {
+			if cached, err := a.store.GetEmailBody(emails[i].ID); err == nil && cached != nil &&
+				(cached.TextBody != "" || cached.HTMLBody != "") {
+				emails[i].TextBody = cached.TextBody
+				emails[i].HTMLBody = cached.HTMLBody
+				continue
+			}
+		}
+		missing = append(missing, emails[i].ID)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	fetched, err := a.client.GetEmailBodies(missing)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]*models.Email, len(fetched))
+	for _, email := range fetched {
+		byID[email.ID] = email
+		if a.store != nil {
+			a.store.SaveEmailBody(email)
+		}
+	}
+	for i := range emails {
+		if email, ok := byID[emails[i].ID]; ok {
+			emails[i].TextBody = email.TextBody
+			emails[i].HTMLBody = email.HTMLBody
+		}
+	}
+	return nil
+}
+
+// exportThread renders a conversation as one document, oldest message
+// first, each with its From and Date. Quoted text is kept or collapsed
+// according to export_quotes.
+func (a *App) exportThread(thread Thread) string {
+	emails := append([]models.Email(nil), thread.Emails...)
+	sort.SliceStable(emails, func(i, j int) bool {
+		return emails[i].ReceivedAt.Before(emails[j].ReceivedAt)
+	})
+
+	markdown := a.cfg.ExportFormat != config.ExportFormatText
+	subject := thread.Subject
+	if subject == "" {
+		subject = "(no subject)"
+	}
+
+	var b strings.Builder
+	if markdown {
+		b.WriteString("# " + subject + "\n")
+	} else {
+		b.WriteString(subject + "\n" + strings.Repeat("=", len([]rune(subject))) + "\n")
+	}
+
+	for _, email := range emails {
+		body := email.TextBody
+		if body == "" && email.HTMLBody != "" {
+			body = views.HTMLToText(email.HTMLBody)
+		}
+		if !a.cfg.ExportQuotes {
+			body = collapseQuotes(body)
+		}
+		body = strings.TrimSpace(body)
+
+		date := email.ReceivedAt.Format("Mon, Jan 2, 2006 at 3:04 PM")
+		if markdown {
+			// Two trailing spaces keep From and Date on separate lines
+			fmt.Fprintf(&b, "\n**From:** %s  \n**Date:** %s\n\n%s\n\n---\n", fromHeader(email), date, body)
+		} else {
+			fmt.Fprintf(&b, "\nFrom: %s\nDate: %s\n\n%s\n\n%s\n", fromHeader(email), date, body, strings.Repeat("-", 40))
+		}
+	}
+
+	return b.String()
+}
+
+// fromHeader formats the sender as "Name <email>"
+func fromHeader(email models.Email) string {
+	if len(email.From) == 0 {
+		return "unknown"
+	}
+	if email.From[0].Name == "" {
+		return email.From[0].Email
+	}
+	return fmt.Sprintf("%s <%s>", email.From[0].Name, email.From[0].Email)
+}
+
+// collapseQuotes replaces each run of quoted lines, and the attribution
+// line above it, with a short marker. The earlier messages are in the
+// export already.
+func collapseQuotes(body string) string {
+	lines := strings.Split(body, "\n")
+	var out []string
+	inQuote := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, ">") {
+			if !inQuote {
+				// Drop the "On ... wrote:" line introducing the quote
+				if n := len(out); n > 0 && attributionRe.MatchString(strings.TrimSpace(out[n-1])) {
+					out = out[:n-1]
+				}
+				out = append(out, "[quoted text hidden]")
+				inQuote = true
+			}
+			continue
+		}
+		inQuote = false
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// exportNameRe matches characters left out of export file names
+var exportNameRe = regexp.MustCompile(`[^\w\- ]+`)
+
+// writeExport saves an exported conversation in dir, named after the
+// subject and the export time
+func writeExport(dir, subject, format, doc string) (string, error) {
+	if strings.HasPrefix(dir, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, dir[2:])
+		}
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create export dir: %w", err)
+	}
+
+	name := strings.TrimSpace(exportNameRe.ReplaceAllString(subject, ""))
+	if len(name) > 60 {
+		name = strings.TrimSpace(name[:60])
+	}
+	if name == "" {
+		name = "conversation"
+	}
+	ext := ".md"
+	if format == config.ExportFormatText {
+		ext = ".txt"
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s %s%s", name, time.Now().Format("2006-01-02 1504"), ext))
+	if err := os.WriteFile(path, []byte(doc), 0600); err != nil {
+		return "", fmt.Errorf("failed to write export: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newFilterInput creates the text input used for the inline list filter
+func newFilterInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/ "
+	ti.Placeholder = "filter by sender or subject"
+	ti.CharLimit = 100
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(ColorAccent)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(ColorPrimary)
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(ColorDim)
+	return ti
+}
+
+// startFilter opens the inline filter over the loaded threads
+func (a *App) startFilter() tea.Cmd {
+	a.filtering = true
+	a.filterInput.SetValue(a.filterQuery)
+	a.filterInput.CursorEnd()
+	return a.filterInput.Focus()
+}
+
+// clearFilter removes the inline filter and shows every thread again
+func (a *App) clearFilter() {
+	a.filtering = false
+	a.filterQuery = ""
+	a.filterInput.SetValue("")
+	a.filterInput.Blur()
+}
+
+// handleFilterKeys handles typing while the inline filter has focus. Enter
+// keeps the filter and returns to the list, esc clears it.
+func (a *App) handleFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		a.clearFilter()
+		a.refilterThreads()
+		return a, nil
+	case tea.KeyEnter:
+		a.filtering = false
+		a.filterInput.Blur()
+		return a, nil
+	case tea.KeyUp, tea.KeyDown:
+		// Allow moving through the matches without leaving the filter
+		return a.handleMessagesKeys(msg)
+	}
+
+	var cmd tea.Cmd
+	a.filterInput, cmd = a.filterInput.Update(msg)
+	if a.filterInput.Value() != a.filterQuery {
+		a.filterQuery = a.filterInput.Value()
+		a.refilterThreads()
+	}
+	return a, cmd
+}
+
+// refilterThreads rebuilds the list after the filter changed, selecting
+// the first match
+func (a *App) refilterThreads() {
+	a.applyThreadFilters()
+	a.selectedThread = 0
+	a.clampThreadSelection()
+}
+
+// filterThreads keeps threads whose sender or subject contains the query
+func filterThreads(threads []Thread, query string) []Thread {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return threads
+	}
+
+	var filtered []Thread
+	for _, t := range threads {
+		if strings.Contains(strings.ToLower(t.From), query) ||
+			strings.Contains(strings.ToLower(t.Subject), query) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// renderFilterBar renders the filter prompt shown above the message list
+func (a *App) renderFilterBar(width int) string {
+	if a.filtering {
+		a.filterInput.Width = width - 4
+		return a.filterInput.View()
+	}
+	return lipgloss.NewStyle().Foreground(ColorDim).Render("/ ") +
+		lipgloss.NewStyle().Foreground(ColorSecondary).Render(a.filterQuery) +
+		lipgloss.NewStyle().Foreground(ColorDim).Render("  (esc to clear)")
+}
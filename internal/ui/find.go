@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newFindInput creates the text input used to find text in the reader
+func newFindInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/ "
+	ti.Placeholder = "find in message"
+	ti.CharLimit = 100
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(ColorAccent)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(ColorPrimary)
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(ColorDim)
+	return ti
+}
+
+// startFind opens the find prompt in the reader
+func (a *App) startFind() tea.Cmd {
+	if a.emailReader == nil {
+		return nil
+	}
+	a.finding = true
+	a.findInput.CursorEnd()
+	return a.findInput.Focus()
+}
+
+// clearFind closes the find prompt and removes the highlights
+func (a *App) clearFind() {
+	a.finding = false
+	a.findInput.SetValue("")
+	a.findInput.Blur()
+	if a.emailReader != nil {
+		a.emailReader.ClearFind()
+	}
+}
+
+// handleFindKeys handles typing while the find prompt has focus. Matches
+// update as you type; enter keeps them for n/N, esc clears them.
+func (a *App) handleFindKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		a.clearFind()
+		return a, nil
+	case tea.KeyEnter:
+		a.finding = false
+		a.findInput.Blur()
+		if a.findInput.Value() == "" {
+			a.clearFind()
+		}
+		return a, nil
+	}
+
+	var cmd tea.Cmd
+	a.findInput, cmd = a.findInput.Update(msg)
+	if a.emailReader != nil {
+		if term := a.findInput.Value(); term != "" {
+			a.emailReader.Find(term)
+		} else {
+			a.emailReader.ClearFind()
+		}
+	}
+	return a, cmd
+}
+
+// renderFindBar renders the find prompt shown above the reader
+func (a *App) renderFindBar(width int) string {
+	status := lipgloss.NewStyle().Foreground(ColorDim).Render("  " + a.emailReader.FindStatus())
+	if a.finding {
+		a.findInput.Width = width - 4 - lipgloss.Width(status)
+		return a.findInput.View() + status
+	}
+	return lipgloss.NewStyle().Foreground(ColorDim).Render("/ ") +
+		lipgloss.NewStyle().Foreground(ColorSecondary).Render(a.findInput.Value()) +
+		status +
+		lipgloss.NewStyle().Foreground(ColorDim).Render("  (n/N next/prev, esc to clear)")
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/the9x/anneal/internal/storage"
+	"github.com/the9x/anneal/internal/ui/views"
+)
+
+// folderAttachments lists every attachment found in the open folder, in
+// place of the message list
+type folderAttachments struct {
+	mailboxID string
+	folder    string
+	items     []storage.AttachmentRef
+	selected  int
+}
+
+// folderAttachmentsMsg carries the attachments found in a folder
+type folderAttachmentsMsg struct {
+	mailboxID string
+	folder    string
+	items     []storage.AttachmentRef
+	err       error
+}
+
+// openFolderAttachments lists the attachments in the open folder. The
+// bodies of loaded emails with attachments are fetched first if they
+// aren't cached, since attachments are indexed as bodies are saved.
+func (a *App) openFolderAttachments() tea.Cmd {
+	mb := a.currentMailbox()
+	if mb == nil || mb.IsVirtual() {
+		return a.showToast("Attachments can only be listed for a folder", toastInfo, 3*time.Second)
+	}
+	if a.store == nil {
+		return a.showToast("Listing attachments needs the local cache", toastInfo, 3*time.Second)
+	}
+
+	var withAttachments []string
+	for _, e := range a.emails {
+		if e.HasAttachment {
+			withAttachments = append(withAttachments, e.ID)
+		}
+	}
+	mailboxID, folder := mb.ID, mb.DisplayName()
+
+	a.loading = true
+	return func() tea.Msg {
+		var missing []string
+		for _, id := range withAttachments {
+			if ok, err := a.store.HasEmailBody(id); err == nil && !ok {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) > 0 {
+			fetched, err := a.client.GetEmailBodies(missing)
+			if err != nil {
+				return folderAttachmentsMsg{err: err}
+			}
+			for _, email := range fetched {
+				a.store.SaveEmailBody(email)
+			}
+		}
+		items, err := a.store.GetAttachments(mailboxID)
+		return folderAttachmentsMsg{mailboxID: mailboxID, folder: folder, items: items, err: err}
+	}
+}
+
+// handleFolderAttachments shows the list, unless another folder was opened
+// in the meantime
+func (a *App) handleFolderAttachments(msg folderAttachmentsMsg) (tea.Model, tea.Cmd) {
+	a.loading = false
+	if msg.err != nil {
+		return a, a.showToast("Couldn't list attachments: "+msg.err.Error(), toastError, 4*time.Second)
+	}
+	if mb := a.currentMailbox(); mb == nil || mb.ID != msg.mailboxID || a.viewState != ViewMessages {
+		return a, nil
+	}
+	if len(msg.items) == 0 {
+		return a, a.showToast("No attachments in "+msg.folder, toastInfo, 3*time.Second)
+	}
+	a.attachments = &folderAttachments{mailboxID: msg.mailboxID, folder: msg.folder, items: msg.items}
+	return a, nil
+}
+
+// showingFolderAttachments returns true while the open folder's
+// attachments are listed
+func (a *App) showingFolderAttachments() bool {
+	if a.attachments == nil || a.viewState != ViewMessages {
+		return false
+	}
+	mb := a.currentMailbox()
+	return mb != nil && mb.ID == a.attachments.mailboxID
+}
+
+// handleFolderAttachmentKeys moves through the attachments; enter
+// downloads and opens the selected one
+func (a *App) handleFolderAttachmentKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := a.attachments
+	switch msg.String() {
+	case "up", "k":
+		if l.selected > 0 {
+			l.selected--
+		}
+	case "down", "j":
+		if l.selected < len(l.items)-1 {
+			l.selected++
+		}
+	case "g", "home":
+		l.selected = 0
+	case "G", "end":
+		l.selected = len(l.items) - 1
+	case "enter", "o":
+		att := l.items[l.selected].Attachment
+		return a, tea.Batch(
+			a.showToast("Opening "+att.Name+"…", toastInfo, 3*time.Second),
+			a.openAttachment(&att),
+		)
+	case "esc", "q", "F":
+		a.attachments = nil
+	}
+	return a, nil
+}
+
+// renderFolderAttachments renders the attachments in place of the message
+// list: name, sender, date and size, scrolled to keep the selection in view
+func (a *App) renderFolderAttachments(width int) string {
+	l := a.attachments
+	label := lipgloss.NewStyle().Foreground(ColorDim)
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render("◇ attachments"))
+	b.WriteString(label.Render(fmt.Sprintf("  %d in %s", len(l.items), l.folder)))
+	b.WriteString("\n\n")
+
+	const fromW, dateW, sizeW = 18, 6, 9
+	nameW := max(width-4-2-fromW-dateW-sizeW-3, 10)
+
+	rows := max(a.height-8, 3)
+	start := 0
+	if l.selected >= rows {
+		start = l.selected - rows + 1
+	}
+	end := min(start+rows, len(l.items))
+
+	for i := start; i < end; i++ {
+		item := l.items[i]
+		line := fmt.Sprintf("  %-*s %-*s %*s %*s",
+			nameW, truncatePreview(item.Name, nameW),
+			fromW, truncatePreview(item.From.ShortName(), fromW),
+			dateW, item.ReceivedAt.Local().Format("Jan 2"),
+			sizeW, views.FormatSize(item.Size))
+		style := lipgloss.NewStyle().Foreground(ColorSecondary)
+		if i == l.selected {
+			line = "▶" + line[1:]
+			style = lipgloss.NewStyle().Foreground(ColorPrimary).Background(ColorBgSelect)
+		}
+		b.WriteString(style.MaxWidth(width-4).Render(line) + "\n")
+	}
+
+	b.WriteString("\n" + label.Render("enter: open  esc: close"))
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Padding(1, 2).
+		Render(b.String())
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/jmap"
+	"github.com/the9x/anneal/internal/models"
+)
+
+// loadFollowUps lists $todo-tagged emails from every folder. The server is
+// asked first since the keyword may have been set on another client; the
+// cache is used when offline.
+func (a *App) loadFollowUps() tea.Cmd {
+	return func() tea.Msg {
+		emails, err := a.client.GetEmailsWithKeyword(jmap.TodoKeyword, a.cfg.PageSize)
+		if err == nil {
+			if a.store != nil && len(emails) > 0 {
+				a.store.SaveEmails(a.client.AccountID(), emails)
+			}
+			return emailsLoadedMsg{emails: emails, mailboxID: models.FollowUpMailboxID, fromCache: false, err: nil}
+		}
+
+		if a.store != nil {
+			cached, cacheErr := a.store.GetTodoEmails(a.client.AccountID(), a.cfg.PageSize)
+			if cacheErr == nil && len(cached) > 0 {
+				return emailsLoadedMsg{emails: cached, mailboxID: models.FollowUpMailboxID, fromCache: true, err: nil}
+			}
+		}
+		return emailsLoadedMsg{mailboxID: models.FollowUpMailboxID, err: err}
+	}
+}
+
+// toggleTodo flips the follow-up keyword on a set of emails, as one: it's
+// cleared from all of them if any has it, and otherwise set on all
+func (a *App) toggleTodo(emails []models.Email) tea.Cmd {
+	todo := !Thread{Emails: emails}.hasTodo()
+	ids := make([]string, len(emails))
+	for i, e := range emails {
+		ids[i] = e.ID
+	}
+	return func() tea.Msg {
+		err := a.client.SetEmailsKeywords(ids, map[string]bool{jmap.TodoKeyword: todo})
+		if err == nil && a.store != nil {
+			for _, id := range ids {
+				a.store.SetEmailTodo(id, todo)
+			}
+		}
+		return emailActionMsg{err: err}
+	}
+}
+
+// hasTodo returns true if any email in the thread is marked for follow-up
+func (t Thread) hasTodo() bool {
+	for _, e := range t.Emails {
+		if e.IsTodo {
+			return true
+		}
+	}
+	return false
+}
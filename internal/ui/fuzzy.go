@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fuzzyMatch is one item that matched a fuzzy pattern
+type fuzzyMatch struct {
+	index     int   // position of the item in the list searched
+	score     int   // higher is a better match
+	positions []int // rune indexes of the matched characters
+}
+
+// fuzzyFilter returns the items that contain every character of pattern
+// in order, ignoring case, best match first. Ties keep the list order, as
+// does an empty pattern, which matches everything.
+func fuzzyFilter(pattern string, items []string) []fuzzyMatch {
+	var matches []fuzzyMatch
+	for i, item := range items {
+		if score, positions, ok := fuzzyScore(pattern, item); ok {
+			matches = append(matches, fuzzyMatch{index: i, score: score, positions: positions})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	return matches
+}
+
+// fuzzyScore matches pattern against text from each place its first
+// character appears and keeps the best. Matches at the start of the text
+// or of a word and runs of consecutive characters score higher; gaps
+// between matched characters cost a little, so "arch" ranks Archive above
+// Search Results.
+func fuzzyScore(pattern, text string) (score int, positions []int, ok bool) {
+	p := []rune(strings.ToLower(pattern))
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+	t := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(t) {
+		lower = t
+	}
+
+	best := -1
+	for start := range lower {
+		if lower[start] != p[0] {
+			continue
+		}
+		s, pos, matched := fuzzyScoreFrom(p, t, lower, start)
+		if matched && s > best {
+			best, positions = s, pos
+		}
+	}
+	if best < 0 {
+		return 0, nil, false
+	}
+	return best, positions, true
+}
+
+// fuzzyScoreFrom matches p greedily from position start
+func fuzzyScoreFrom(p, t, lower []rune, start int) (int, []int, bool) {
+	score := 0
+	positions := make([]int, 0, len(p))
+	j := 0
+	for i := start; i < len(lower) && j < len(p); i++ {
+		if lower[i] != p[j] {
+			continue
+		}
+		score++
+		switch {
+		case i == 0:
+			score += 10
+		case wordStart(t, i):
+			score += 8
+		}
+		if j > 0 {
+			prev := positions[j-1]
+			if prev == i-1 {
+				score += 5
+			} else {
+				score -= min(i-prev-1, 5)
+			}
+		}
+		positions = append(positions, i)
+		j++
+	}
+	return score, positions, j == len(p)
+}
+
+// wordStart returns true if the rune at i begins a word: it follows a
+// separator, or is an upper-case letter after a lower-case one
+func wordStart(t []rune, i int) bool {
+	prev := t[i-1]
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsUpper(t[i]) && unicode.IsLower(prev)
+}
+
+// highlightMatches renders text in style with the characters at positions
+// picked out in bold and underlined
+func highlightMatches(text string, positions []int, style lipgloss.Style) string {
+	if len(positions) == 0 {
+		return style.Render(text)
+	}
+	hit := style.Bold(true).Underline(true)
+	matched := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		matched[p] = true
+	}
+
+	var b strings.Builder
+	var run []rune
+	runMatched := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runMatched {
+			b.WriteString(hit.Render(string(run)))
+		} else {
+			b.WriteString(style.Render(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range []rune(text) {
+		if matched[i] != runMatched {
+			flush()
+			runMatched = matched[i]
+		}
+		run = append(run, r)
+	}
+	flush()
+	return b.String()
+}
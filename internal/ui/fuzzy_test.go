@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFuzzyFilterRanking(t *testing.T) {
+	folders := []string{"Inbox", "Drafts", "Sent", "Search", "Archive", "Trash", "Junk Mail", "Sent Items/2023"}
+	tests := []struct {
+		pattern string
+		want    []string // best first
+	}{
+		{"snt", []string{"Sent", "Sent Items/2023"}},
+		{"arch", []string{"Archive", "Search"}},
+		{"jm", []string{"Junk Mail"}},
+		{"SENT", []string{"Sent", "Sent Items/2023"}},
+		{"xyz", nil},
+		{"tns", nil}, // the letters are there, but not in order
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			var got []string
+			for _, m := range fuzzyFilter(tt.pattern, folders) {
+				got = append(got, folders[m.index])
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("fuzzyFilter(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuzzyFilterEmptyPatternKeepsOrder(t *testing.T) {
+	items := []string{"b", "a", "c"}
+	got := fuzzyFilter("", items)
+	if len(got) != 3 || got[0].index != 0 || got[1].index != 1 || got[2].index != 2 {
+		t.Errorf("fuzzyFilter(\"\") = %+v, want every item in order", got)
+	}
+}
+
+func TestFuzzyScorePositions(t *testing.T) {
+	tests := []struct {
+		pattern, text string
+		want          []int
+	}{
+		{"snt", "Sent", []int{0, 2, 3}},
+		{"arch", "Search", []int{2, 3, 4, 5}},
+		{"im", "Sent Items", []int{5, 8}},
+		{"ss", "Spam Sent", []int{0, 5}},
+	}
+	for _, tt := range tests {
+		_, positions, ok := fuzzyScore(tt.pattern, tt.text)
+		if !ok {
+			t.Errorf("fuzzyScore(%q, %q) didn't match", tt.pattern, tt.text)
+			continue
+		}
+		if len(positions) != len(tt.want) {
+			t.Errorf("fuzzyScore(%q, %q) positions = %v, want %v", tt.pattern, tt.text, positions, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if positions[i] != tt.want[i] {
+				t.Errorf("fuzzyScore(%q, %q) positions = %v, want %v", tt.pattern, tt.text, positions, tt.want)
+				break
+			}
+		}
+	}
+
+	word, _, _ := fuzzyScore("m", "Junk Mail")
+	inner, _, _ := fuzzyScore("m", "Spam")
+	if word <= inner {
+		t.Errorf("word start scored %d, not above a mid-word match at %d", word, inner)
+	}
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// goToRoles maps the letter typed after g to the role of the folder it
+// jumps to
+var goToRoles = map[string]string{
+	"i": "inbox",
+	"s": "sent",
+	"d": "drafts",
+	"a": "archive",
+	"t": "trash",
+}
+
+// goToTimeout is how soon after g the folder letter must follow. Later, the
+// letter does its own thing, as d deleting the thread g moved to.
+const goToTimeout = time.Second
+
+// handleGoTo opens the folder for a role letter typed straight after g.
+// g itself still moves to the top of the list. ok is false for keys that
+// aren't part of a jump.
+func (a *App) handleGoTo(msg tea.KeyMsg) (cmd tea.Cmd, ok bool) {
+	pending := time.Now().Before(a.goPendingUntil)
+	a.goPendingUntil = time.Time{}
+	if pending {
+		if role, found := goToRoles[msg.String()]; found {
+			return a.goToRole(role), true
+		}
+	}
+	if msg.String() == "g" && key.Matches(msg, a.keys.Top) {
+		a.goPendingUntil = time.Now().Add(goToTimeout)
+	}
+	return nil, false
+}
+
+// goToRole opens the folder with the given role
+func (a *App) goToRole(role string) tea.Cmd {
+	for i := range a.mailboxes {
+		if a.mailboxes[i].Role == role {
+			return a.openMailbox(i)
+		}
+	}
+	return a.showToast("No "+role+" folder", toastInfo, 3*time.Second)
+}
@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestGoToExpires(t *testing.T) {
+	g := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")}
+	archive := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}
+	a := &App{keys: DefaultKeyMap()}
+
+	a.handleGoTo(g)
+	if _, ok := a.handleGoTo(archive); !ok {
+		t.Error("g then a straight away didn't jump")
+	}
+
+	a.handleGoTo(g)
+	a.goPendingUntil = time.Now().Add(-time.Millisecond)
+	if _, ok := a.handleGoTo(archive); ok {
+		t.Error("a long after g jumped instead of archiving")
+	}
+
+	a.handleGoTo(g)
+	a.handleGoTo(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if _, ok := a.handleGoTo(archive); ok {
+		t.Error("a after another key jumped")
+	}
+}
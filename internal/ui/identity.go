@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/the9x/anneal/internal/models"
+)
+
+// folderIdentity picks the From address for mail composed from the open
+// folder, for setups that file each alias or list in its own folder. An
+// entry in the account's folder_identities wins; otherwise an identity
+// matches when the folder is named after its address, the part before the
+// @, or a +tag. Returns "" to keep the default identity.
+func (a *App) folderIdentity() string {
+	mb := a.currentMailbox()
+	if mb == nil || models.IsVirtualMailboxID(mb.ID) || mb.Role == "inbox" {
+		return ""
+	}
+
+	if account := a.cfg.FindAccount(a.client.Email()); account != nil {
+		for folder, addr := range account.FolderIdentities {
+			if strings.EqualFold(folder, mb.Name) {
+				return addr
+			}
+		}
+	}
+
+	name := strings.ToLower(mb.Name)
+	for _, id := range a.identities {
+		addr := strings.ToLower(id.Email)
+		local, _, _ := strings.Cut(addr, "@")
+		base, tag, hasTag := strings.Cut(local, "+")
+		if name == addr || (hasTag && name == tag) || (!hasTag && name == base) {
+			return id.Email
+		}
+	}
+	return ""
+}
@@ -1,6 +1,9 @@
 package ui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/the9x/anneal/internal/i18n"
+)
 
 // KeyMap defines the keybindings for the application
 type KeyMap struct {
@@ -20,13 +23,44 @@ type KeyMap struct {
 	Delete      key.Binding
 	Archive     key.Binding
 	Move        key.Binding
+	Snooze      key.Binding
 	Star        key.Binding
 	MarkUnread  key.Binding
 	Search      key.Binding
 	Refresh     key.Binding
 	Expand      key.Binding
 	Collapse    key.Binding
+	LoadThread  key.Binding
+	ToggleOther key.Binding
+	Pin         key.Binding
+	Todo        key.Binding
+	OpenBrowser key.Binding
+	Links       key.Binding
+	Mark        key.Binding
+	Pager       key.Binding
+	PipeBody    key.Binding
+	CopyOTP     key.Binding
+	CopyLink    key.Binding
+	Resend      key.Binding
+	Undo        key.Binding
+	ArchiveRead key.Binding
+	TrainSender key.Binding
+	ThreadInfo  key.Binding
+	Export      key.Binding
+	Transcript  key.Binding
+	BodyPart    key.Binding
+	Addresses   key.Binding
+	BlockSender key.Binding
+	Logout      key.Binding
+	SplitView   key.Binding
+	Attachments key.Binding
+	FindNext    key.Binding
+	FindPrev    key.Binding
+	NextEmail   key.Binding
+	PrevEmail   key.Binding
+	SortSize    key.Binding
 	Help        key.Binding
+	Accounts    key.Binding
 	Account1    key.Binding
 	Account2    key.Binding
 	Account3    key.Binding
@@ -39,115 +73,239 @@ func DefaultKeyMap() KeyMap {
 	return KeyMap{
 		Up: key.NewBinding(
 			key.WithKeys("up", "k"),
-			key.WithHelp("k/↑", "up"),
+			key.WithHelp("k/↑", i18n.T("up")),
 		),
 		Down: key.NewBinding(
 			key.WithKeys("down", "j"),
-			key.WithHelp("j/↓", "down"),
+			key.WithHelp("j/↓", i18n.T("down")),
 		),
 		Left: key.NewBinding(
 			key.WithKeys("left", "h"),
-			key.WithHelp("←/h", "back"),
+			key.WithHelp("←/h", i18n.T("back")),
 		),
 		Right: key.NewBinding(
 			key.WithKeys("right", "l"),
-			key.WithHelp("→/l", "open"),
+			key.WithHelp("→/l", i18n.T("open")),
 		),
 		Top: key.NewBinding(
 			key.WithKeys("g", "home"),
-			key.WithHelp("g", "top"),
+			key.WithHelp("g", i18n.T("top")),
 		),
 		Bottom: key.NewBinding(
 			key.WithKeys("G", "end"),
-			key.WithHelp("G", "bottom"),
+			key.WithHelp("G", i18n.T("bottom")),
 		),
 		Enter: key.NewBinding(
 			key.WithKeys("enter"),
-			key.WithHelp("enter", "open/expand"),
+			key.WithHelp("enter", i18n.T("open/expand")),
 		),
 		Back: key.NewBinding(
 			key.WithKeys("esc", "q"),
-			key.WithHelp("esc/q", "back"),
+			key.WithHelp("esc/q", i18n.T("back")),
 		),
 		Quit: key.NewBinding(
 			key.WithKeys("ctrl+c", "Q"),
-			key.WithHelp("Q", "quit"),
+			key.WithHelp("Q", i18n.T("quit")),
 		),
 		Compose: key.NewBinding(
 			key.WithKeys("c"),
-			key.WithHelp("c", "compose"),
+			key.WithHelp("c", i18n.T("compose")),
 		),
 		Reply: key.NewBinding(
 			key.WithKeys("r"),
-			key.WithHelp("r", "reply"),
+			key.WithHelp("r", i18n.T("reply")),
 		),
 		ReplyAll: key.NewBinding(
 			key.WithKeys("R"),
-			key.WithHelp("R", "reply all"),
+			key.WithHelp("R", i18n.T("reply all")),
 		),
 		Forward: key.NewBinding(
 			key.WithKeys("f"),
-			key.WithHelp("f", "forward"),
+			key.WithHelp("f", i18n.T("forward")),
 		),
 		Delete: key.NewBinding(
 			key.WithKeys("d", "delete"),
-			key.WithHelp("d", "delete"),
+			key.WithHelp("d", i18n.T("delete")),
 		),
 		Archive: key.NewBinding(
 			key.WithKeys("a"),
-			key.WithHelp("a", "archive"),
+			key.WithHelp("a", i18n.T("archive")),
 		),
 		Move: key.NewBinding(
 			key.WithKeys("m"),
-			key.WithHelp("m", "move"),
+			key.WithHelp("m", i18n.T("move")),
+		),
+		Snooze: key.NewBinding(
+			key.WithKeys("Z"),
+			key.WithHelp("Z", i18n.T("snooze")),
 		),
 		Star: key.NewBinding(
 			key.WithKeys("s"),
-			key.WithHelp("s", "star"),
+			key.WithHelp("s", i18n.T("star")),
 		),
 		MarkUnread: key.NewBinding(
 			key.WithKeys("u"),
-			key.WithHelp("u", "mark unread"),
+			key.WithHelp("u", i18n.T("mark unread")),
 		),
 		Search: key.NewBinding(
 			key.WithKeys("/"),
-			key.WithHelp("/", "search"),
+			key.WithHelp("/", i18n.T("filter")),
 		),
 		Refresh: key.NewBinding(
 			key.WithKeys("ctrl+r"),
-			key.WithHelp("ctrl+r", "refresh"),
+			key.WithHelp("ctrl+r", i18n.T("refresh")),
 		),
 		Expand: key.NewBinding(
 			key.WithKeys("space", "tab"),
-			key.WithHelp("space", "expand thread"),
+			key.WithHelp("space", i18n.T("expand thread")),
 		),
 		Collapse: key.NewBinding(
 			key.WithKeys("shift+tab"),
-			key.WithHelp("shift+tab", "collapse"),
+			key.WithHelp("shift+tab", i18n.T("collapse")),
+		),
+		LoadThread: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", i18n.T("load conversation")),
+		),
+		Pin: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", i18n.T("pin")),
+		),
+		Todo: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", i18n.T("follow-up")),
+		),
+		CopyOTP: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", i18n.T("copy code")),
+		),
+		CopyLink: key.NewBinding(
+			key.WithKeys("Y"),
+			key.WithHelp("Y", i18n.T("copy web link")),
+		),
+		Resend: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", i18n.T("resend bounce")),
+		),
+		Pager: key.NewBinding(
+			key.WithKeys("|"),
+			key.WithHelp("|", i18n.T("open in pager")),
+		),
+		PipeBody: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", i18n.T("pipe to command")),
+		),
+		Mark: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", i18n.T("mark")),
+		),
+		Undo: key.NewBinding(
+			key.WithKeys("ctrl+z"),
+			key.WithHelp("ctrl+z", i18n.T("undo bulk action")),
+		),
+		ArchiveRead: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", i18n.T("archive all read")),
+		),
+		OpenBrowser: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", i18n.T("open in browser")),
+		),
+		ToggleOther: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", i18n.T("focused/other")),
+		),
+		TrainSender: key.NewBinding(
+			key.WithKeys("I"),
+			key.WithHelp("I", i18n.T("move sender")),
+		),
+		SortSize: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", i18n.T("sort by size")),
+		),
+		ThreadInfo: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", i18n.T("thread grouping")),
+		),
+		FindNext: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", i18n.T("next match")),
+		),
+		FindPrev: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", i18n.T("previous match")),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", i18n.T("export conversation")),
+		),
+		Transcript: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", i18n.T("read as transcript")),
+		),
+		BodyPart: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", i18n.T("text/html part")),
+		),
+		Addresses: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", i18n.T("short/full addresses")),
+		),
+		BlockSender: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", i18n.T("block sender / blocked list")),
+		),
+		Logout: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", i18n.T("log out")),
+		),
+		Attachments: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", i18n.T("attachments in folder")),
+		),
+		SplitView: key.NewBinding(
+			key.WithKeys("V"),
+			key.WithHelp("V", i18n.T("folders side by side")),
+		),
+		NextEmail: key.NewBinding(
+			key.WithKeys("J"),
+			key.WithHelp("J", i18n.T("next message")),
+		),
+		PrevEmail: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", i18n.T("previous message")),
+		),
+		Links: key.NewBinding(
+			key.WithKeys("U"),
+			key.WithHelp("U", i18n.T("links")),
 		),
 		Help: key.NewBinding(
 			key.WithKeys("?"),
-			key.WithHelp("?", "help"),
+			key.WithHelp("?", i18n.T("help")),
+		),
+		Accounts: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", i18n.T("switch account")),
 		),
 		Account1: key.NewBinding(
 			key.WithKeys("1"),
-			key.WithHelp("1", "account 1"),
+			key.WithHelp("1", i18n.T("account 1")),
 		),
 		Account2: key.NewBinding(
 			key.WithKeys("2"),
-			key.WithHelp("2", "account 2"),
+			key.WithHelp("2", i18n.T("account 2")),
 		),
 		Account3: key.NewBinding(
 			key.WithKeys("3"),
-			key.WithHelp("3", "account 3"),
+			key.WithHelp("3", i18n.T("account 3")),
 		),
 		Account4: key.NewBinding(
 			key.WithKeys("4"),
-			key.WithHelp("4", "account 4"),
+			key.WithHelp("4", i18n.T("account 4")),
 		),
 		Account5: key.NewBinding(
 			key.WithKeys("5"),
-			key.WithHelp("5", "account 5"),
+			key.WithHelp("5", i18n.T("account 5")),
 		),
 	}
 }
@@ -160,10 +318,14 @@ func (k KeyMap) ShortHelp() []key.Binding {
 // FullHelp returns keybindings for the expanded help view
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Left, k.Right},
-		{k.Enter, k.Back, k.Expand},
+		{k.Up, k.Down, k.Left, k.Right, k.NextEmail, k.PrevEmail},
+		{k.Enter, k.Back, k.Expand, k.LoadThread},
 		{k.Compose, k.Reply, k.ReplyAll, k.Forward},
-		{k.Delete, k.Archive, k.Star, k.MarkUnread},
-		{k.Search, k.Refresh, k.Help, k.Quit},
+		{k.Delete, k.Archive, k.Move, k.Snooze, k.Star, k.MarkUnread, k.Pin, k.Todo},
+		{k.Mark, k.Undo, k.ArchiveRead, k.SplitView, k.Attachments},
+		{k.ToggleOther, k.TrainSender, k.OpenBrowser, k.Links, k.Pager, k.PipeBody, k.CopyOTP, k.CopyLink, k.Resend},
+		{k.SortSize, k.ThreadInfo, k.Export, k.Transcript, k.BodyPart, k.Addresses, k.BlockSender},
+		{k.Search, k.FindNext, k.FindPrev, k.Refresh, k.Help, k.Logout, k.Quit},
+		{k.Accounts, k.Account1, k.Account2, k.Account3, k.Account4, k.Account5},
 	}
 }
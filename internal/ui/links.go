@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// linkOpenedMsg reports whether a link could be handed to the browser
+type linkOpenedMsg struct {
+	err error
+}
+
+// openLinkList lists the links in the open email to pick one to open
+func (a *App) openLinkList() tea.Cmd {
+	if a.emailReader == nil {
+		return nil
+	}
+	links := a.emailReader.Links()
+	if len(links) == 0 {
+		return a.showToast("No links in this message", toastInfo, 3*time.Second)
+	}
+	a.links = links
+	a.linkSelected = 0
+	return nil
+}
+
+// handleLinkListKeys moves through the links; enter opens the selected one
+// in the browser
+func (a *App) handleLinkListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if a.linkSelected > 0 {
+			a.linkSelected--
+		}
+	case "down", "j":
+		if a.linkSelected < len(a.links)-1 {
+			a.linkSelected++
+		}
+	case "enter", "o":
+		link := a.links[a.linkSelected]
+		a.links = nil
+		return a, func() tea.Msg {
+			return linkOpenedMsg{err: openFile(link)}
+		}
+	case "esc", "q", "U":
+		a.links = nil
+	}
+	return a, nil
+}
+
+// handleLinkOpened reports a link that couldn't be opened
+func (a *App) handleLinkOpened(msg linkOpenedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return a, a.showToast("Couldn't open link: "+msg.err.Error(), toastError, 4*time.Second)
+	}
+	return a, nil
+}
+
+// linkListWindow returns the range of links shown, scrolled to keep the
+// selection in view
+func (a *App) linkListWindow() (start, end int) {
+	rows := max(a.height-8, 3)
+	if a.linkSelected >= rows {
+		start = a.linkSelected - rows + 1
+	}
+	return start, min(start+rows, len(a.links))
+}
+
+// renderLinkList renders the email's links in place of the reader,
+// scrolled to keep the selection in view
+func (a *App) renderLinkList(width int) string {
+	label := lipgloss.NewStyle().Foreground(ColorDim)
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render("◇ links"))
+	b.WriteString(label.Render(fmt.Sprintf("  %d in this message", len(a.links))))
+	b.WriteString("\n\n")
+
+	start, end := a.linkListWindow()
+	for i := start; i < end; i++ {
+		line := "  " + a.links[i]
+		style := lipgloss.NewStyle().Foreground(ColorSecondary)
+		if i == a.linkSelected {
+			line = "▶" + line[1:]
+			style = lipgloss.NewStyle().Foreground(ColorPrimary).Background(ColorBgSelect)
+		}
+		b.WriteString(style.MaxWidth(width-4).Render(line) + "\n")
+	}
+
+	b.WriteString("\n" + label.Render("enter: open in browser  esc: close"))
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Padding(1, 2).
+		Render(b.String())
+}
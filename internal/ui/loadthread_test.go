@@ -0,0 +1,30 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/jmap"
+	"github.com/the9x/anneal/internal/models"
+)
+
+func TestLoadConversationFromReader(t *testing.T) {
+	a := &App{cfg: config.DefaultConfig(), client: &jmap.Client{}, keys: DefaultKeyMap()}
+	a.threads = []Thread{{ID: "t1", Emails: []models.Email{{ID: "e1", ThreadID: "t1"}}}}
+	a.viewState = ViewEmail
+	a.currentEmail = &a.threads[0].Emails[0]
+
+	load := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("L")}
+	if _, cmd := a.Update(load); cmd != nil || a.loading {
+		t.Fatal("loading a conversation with nothing more on the server")
+	}
+
+	a.Update(threadCountMsg{threadID: "t1", count: 3})
+	if _, cmd := a.Update(load); cmd == nil || !a.loading {
+		t.Fatal("L in the reader didn't load the rest of the conversation")
+	}
+	if !a.threads[0].Expanded {
+		t.Error("back from the reader won't show the loaded conversation")
+	}
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/config"
+)
+
+// localDataClearedMsg reports the cached mail and attachments were deleted
+type localDataClearedMsg struct {
+	email string
+	err   error
+}
+
+// confirmLogout asks before signing the current account out of this machine
+func (a *App) confirmLogout() (tea.Model, tea.Cmd) {
+	email := a.client.Email()
+	question := fmt.Sprintf("Log out of %s and delete its cached mail and attachments?", email)
+	return a.askConfirm(question, func() (tea.Model, tea.Cmd) {
+		return a, tea.Batch(a.showToast("Deleting local data…", toastInfo, 0), a.clearLocalData(email))
+	})
+}
+
+// clearLocalData deletes the account's rows from the cache and every file
+// in the attachment cache
+func (a *App) clearLocalData(email string) tea.Cmd {
+	accountID := a.client.AccountID()
+	return func() tea.Msg {
+		if a.store != nil {
+			if err := a.store.DeleteAccount(accountID); err != nil {
+				return localDataClearedMsg{email: email, err: err}
+			}
+		}
+		if a.blobs != nil {
+			if err := a.blobs.Clear(); err != nil {
+				return localDataClearedMsg{email: email, err: err}
+			}
+		}
+		return localDataClearedMsg{email: email}
+	}
+}
+
+// handleLocalDataCleared finishes a logout by removing the token and the
+// account, then quits. The next run starts account setup again.
+func (a *App) handleLocalDataCleared(msg localDataClearedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return a, a.showToast("Logout failed ✗: "+msg.err.Error(), toastError, 5*time.Second)
+	}
+	if err := a.cfg.DeleteToken(msg.email); err != nil && !errors.Is(err, config.ErrTokenNotFound) {
+		return a, a.showToast("Logout failed ✗: "+err.Error(), toastError, 5*time.Second)
+	}
+	a.cfg.RemoveAccount(msg.email)
+	if err := a.cfg.Save(); err != nil {
+		return a, a.showToast("Logout failed ✗: "+err.Error(), toastError, 5*time.Second)
+	}
+	return a, tea.Quit
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/models"
+	"github.com/the9x/anneal/internal/ui/views"
+)
+
+// markReadMsg marks an email read once it has been open for
+// mark_read_delay, if it still is
+type markReadMsg struct {
+	emailID string
+	gen     int
+}
+
+// scheduleMarkRead marks a newly opened email read, right away or after
+// mark_read_delay, unless the folder doesn't allow it. With
+// mark_thread_read_on_open an email already read still marks the rest of
+// its conversation.
+func (a *App) scheduleMarkRead(email *models.Email) tea.Cmd {
+	a.markReadGen++
+	if mb := a.currentMailbox(); mb != nil && !mb.CanSetSeen() {
+		return nil
+	}
+	unread := email.IsUnread
+	if thread := a.selectedThreadWith(email.ID); thread != nil && a.cfg.MarkThreadReadOnOpen {
+		for _, e := range thread.Emails {
+			unread = unread || e.IsUnread
+		}
+	}
+	if !unread {
+		return nil
+	}
+
+	id := email.ID
+	if a.cfg.MarkReadDelay <= 0 {
+		a.markRead(id)
+		return nil
+	}
+	gen := a.markReadGen
+	return tea.Tick(time.Duration(a.cfg.MarkReadDelay)*time.Second, func(time.Time) tea.Msg {
+		return markReadMsg{emailID: id, gen: gen}
+	})
+}
+
+// handleMarkRead marks the email read if it's still the one open in the
+// reader; leaving it sooner cancels the mark
+func (a *App) handleMarkRead(msg markReadMsg) tea.Cmd {
+	if msg.gen != a.markReadGen || a.viewState != ViewEmail ||
+		a.currentEmail == nil || a.currentEmail.ID != msg.emailID {
+		return nil
+	}
+	a.markRead(msg.emailID)
+	return nil
+}
+
+// markRead marks an opened email read, or its whole conversation with
+// mark_thread_read_on_open, and takes them off the unread counts in the
+// list and the sidebar without waiting for the next sync
+func (a *App) markRead(emailID string) {
+	ids := []string{emailID}
+	thread := a.selectedThreadWith(emailID)
+	if thread != nil && a.cfg.MarkThreadReadOnOpen {
+		ids = ids[:0]
+		for _, e := range thread.Emails {
+			if e.IsUnread {
+				ids = append(ids, e.ID)
+			}
+		}
+		if len(ids) == 0 {
+			ids = []string{emailID}
+		}
+	}
+	go a.client.SetEmailsKeywords(ids, map[string]bool{"$seen": true})
+
+	marked := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		marked[id] = true
+	}
+	// Only mail filed in the open folder comes off its count; a thread
+	// also holds replies in Sent and elsewhere
+	mb := a.currentMailbox()
+	read, readHere := 0, 0
+	if thread != nil {
+		for i := range thread.Emails {
+			e := &thread.Emails[i]
+			if !marked[e.ID] || !e.IsUnread {
+				continue
+			}
+			e.IsUnread = false
+			read++
+			if mb != nil && inMailbox(*e, mb.ID) {
+				readHere++
+			}
+			if a.store != nil {
+				a.store.UpdateEmailFlags(e.ID, false, e.IsFlagged)
+			}
+		}
+		thread.UnreadCnt = max(thread.UnreadCnt-read, 0)
+	}
+	for i := range a.emails {
+		if marked[a.emails[i].ID] {
+			a.emails[i].IsUnread = false
+		}
+	}
+
+	if readHere == 0 || mb == nil || mb.IsVirtual() {
+		return
+	}
+	mb.UnreadCount = max(mb.UnreadCount-readHere, 0)
+	if a.store != nil {
+		a.store.UpdateMailboxCounts(a.client.AccountID(), mb.ID, mb.TotalEmails, mb.UnreadCount)
+	}
+	a.mailboxView = views.NewMailboxView(a.mailboxes)
+	a.mailboxView.Select(a.selectedMailbox)
+}
+
+// selectedThreadWith returns the selected thread if it holds the email,
+// or nil
+func (a *App) selectedThreadWith(emailID string) *Thread {
+	if a.selectedThread >= len(a.threads) {
+		return nil
+	}
+	for _, e := range a.threads[a.selectedThread].Emails {
+		if e.ID == emailID {
+			return &a.threads[a.selectedThread]
+		}
+	}
+	return nil
+}
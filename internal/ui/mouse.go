@@ -0,0 +1,84 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// handleMouse maps the mouse onto what the keys already do: the wheel
+// moves like the arrow keys, and a left click selects or opens what's
+// under it. Mouse events only arrive with the mouse option on.
+func (a *App) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	// Text inputs and prompts have the keyboard to themselves
+	if a.err != nil || a.loading || a.confirm != nil || a.filtering || a.finding {
+		return a, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return a.Update(tea.KeyMsg{Type: tea.KeyUp})
+	case tea.MouseButtonWheelDown:
+		return a.Update(tea.KeyMsg{Type: tea.KeyDown})
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return a, nil
+		}
+		x := msg.X - SidebarStyle.GetWidth()
+		y := msg.Y - lipgloss.Height(a.renderHeader())
+		if x < 0 || y < 0 {
+			return a, nil
+		}
+		return a.handleClick(x, y)
+	}
+	return a, nil
+}
+
+// handleClick acts on a click at x, y in the main pane: a conversation in
+// the list is selected, or opened if it already was, and a link or
+// attachment in the reader is opened
+func (a *App) handleClick(x, y int) (tea.Model, tea.Cmd) {
+	switch {
+	case a.move != nil || a.snooze != nil || a.accountPicker || a.blockedList || a.debugThreadID != "":
+		return a, nil
+
+	case a.links != nil && a.viewState == ViewEmail:
+		// Links start below the padding, title and a blank line
+		start, end := a.linkListWindow()
+		if i := start + y - 3; y >= 3 && i < end {
+			a.linkSelected = i
+			return a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		}
+
+	case a.viewState == ViewMessages && a.split == nil && a.threadList != nil:
+		if a.filterQuery != "" {
+			y-- // the filter bar sits above the list
+		}
+		i, ok := a.threadList.RowAt(y)
+		if !ok {
+			return a, nil
+		}
+		if i == a.selectedThread {
+			return a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		}
+		a.clearArrivals()
+		a.selectedThread = i
+		a.threadList.Select(i)
+
+	case a.viewState == ViewEmail && a.emailReader != nil:
+		if a.emailReader.HasFind() {
+			y-- // the find bar sits above the reader
+		}
+		if i, ok := a.emailReader.AttachmentAt(y); ok {
+			a.emailReader.SelectAttachment(i)
+			if att := a.emailReader.SelectedAttachment(); att != nil {
+				return a, a.openAttachment(att)
+			}
+		}
+		if link, ok := a.emailReader.LinkAt(x, y); ok {
+			return a, func() tea.Msg {
+				return linkOpenedMsg{err: openFile(link)}
+			}
+		}
+	}
+	return a, nil
+}
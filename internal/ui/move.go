@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/the9x/anneal/internal/models"
+)
+
+// movePicker lists the folders the selected conversation, or the marked
+// ones, can be moved to, filtered by a fuzzy match on what is typed
+type movePicker struct {
+	input    textinput.Model
+	folders  []*models.Mailbox
+	matches  []fuzzyMatch
+	selected int
+	threadID string // the conversation being moved; empty for marked ones
+	emails   []models.Email
+}
+
+// movedMsg reports the result of moving mail from the picker
+type movedMsg struct {
+	threadID string
+	folder   string
+	action   *bulkAction
+	err      error
+}
+
+// openMovePicker lists every folder other than the open one that can take
+// mail, for the marked conversations or else the selected one
+func (a *App) openMovePicker() tea.Cmd {
+	if a.isInScheduled() || a.isInOutbox() {
+		return nil
+	}
+
+	var threadID string
+	emails := a.markedEmails()
+	if a.viewState != ViewMessages || len(emails) == 0 {
+		if a.selectedThread >= len(a.threads) {
+			return nil
+		}
+		thread := a.threads[a.selectedThread]
+		threadID, emails = thread.ID, thread.Emails
+	}
+	if len(emails) == 0 {
+		return nil
+	}
+
+	var folders []*models.Mailbox
+	for i := range a.mailboxes {
+		mb := &a.mailboxes[i]
+		if i != a.selectedMailbox && !mb.IsVirtual() && mb.CanAdd() {
+			folders = append(folders, mb)
+		}
+	}
+	if len(folders) == 0 {
+		return a.showToast("No other folder to move to", toastInfo, 3*time.Second)
+	}
+
+	input := textinput.New()
+	input.Prompt = "› "
+	input.Placeholder = "folder"
+	input.CharLimit = 100
+	input.PromptStyle = lipgloss.NewStyle().Foreground(ColorAccent)
+	input.TextStyle = lipgloss.NewStyle().Foreground(ColorPrimary)
+	input.PlaceholderStyle = lipgloss.NewStyle().Foreground(ColorDim)
+
+	a.move = &movePicker{input: input, folders: folders, threadID: threadID, emails: emails}
+	a.move.filter()
+	return a.move.input.Focus()
+}
+
+// filter ranks the folders against what has been typed so far
+func (p *movePicker) filter() {
+	names := make([]string, len(p.folders))
+	for i, mb := range p.folders {
+		names[i] = mb.DisplayName()
+	}
+	p.matches = fuzzyFilter(p.input.Value(), names)
+	p.selected = min(p.selected, max(len(p.matches)-1, 0))
+}
+
+// handleMovePickerKeys filters as the user types; enter moves to the
+// selected folder and esc closes the picker
+func (a *App) handleMovePickerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := a.move
+	switch msg.String() {
+	case "up", "ctrl+p", "ctrl+k":
+		if p.selected > 0 {
+			p.selected--
+		}
+		return a, nil
+	case "down", "ctrl+n", "ctrl+j", "tab":
+		if p.selected < len(p.matches)-1 {
+			p.selected++
+		}
+		return a, nil
+	case "enter":
+		if p.selected >= len(p.matches) {
+			return a, nil
+		}
+		a.move = nil
+		return a, a.moveTo(p, p.folders[p.matches[p.selected].index])
+	case "esc":
+		a.move = nil
+		return a, nil
+	}
+
+	var cmd tea.Cmd
+	before := p.input.Value()
+	p.input, cmd = p.input.Update(msg)
+	if p.input.Value() != before {
+		p.selected = 0
+		p.filter()
+	}
+	return a, cmd
+}
+
+// moveTo moves the picker's mail to target in one request, striking the
+// conversation through until the list refreshes. Like an archive, the
+// move can be undone from the status bar for a few seconds.
+func (a *App) moveTo(p *movePicker, target *models.Mailbox) tea.Cmd {
+	action := a.newBulkAction("move", p.emails)
+	ids := make([]string, len(p.emails))
+	for i, e := range p.emails {
+		ids[i] = e.ID
+	}
+	targetID, folder, threadID := target.ID, target.DisplayName(), p.threadID
+	move := func() tea.Msg {
+		if err := a.client.MoveEmails(ids, targetID); err != nil {
+			return movedMsg{threadID: threadID, err: err}
+		}
+		return movedMsg{threadID: threadID, folder: folder, action: action}
+	}
+
+	if threadID == "" {
+		a.marked = make(map[string]bool)
+		return move
+	}
+	a.leaving[threadID] = true
+	if a.viewState == ViewEmail {
+		a.rememberScroll()
+		a.currentEmail = nil
+	}
+	if a.selectedThread < len(a.threads) {
+		a.threads[a.selectedThread].Expanded = false
+	}
+	a.advanceSelection()
+	return move
+}
+
+// handleMoved offers to undo the move and reloads the list
+func (a *App) handleMoved(msg movedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		delete(a.leaving, msg.threadID)
+		return a, a.showToast("Move failed ✗: "+msg.err.Error(), toastError, 5*time.Second)
+	}
+	a.lastBulk = msg.action
+	toastCmd := a.showToast(fmt.Sprintf("Moved to %s — u to undo", msg.folder), toastInfo, archiveUndoWindow)
+	a.toast.undo = true
+	if mb := a.currentMailbox(); mb != nil {
+		return a, tea.Batch(toastCmd, a.loadEmailsFresh(mb.ID))
+	}
+	return a, toastCmd
+}
+
+// renderMovePicker renders the matching folders in place of the main
+// pane, with the typed characters picked out in each name
+func (a *App) renderMovePicker(width int) string {
+	p := a.move
+	label := lipgloss.NewStyle().Foreground(ColorDim)
+
+	var b strings.Builder
+	title := "◇ move conversation to"
+	if p.threadID == "" {
+		title = fmt.Sprintf("◇ move %d messages to", len(p.emails))
+	}
+	b.WriteString(lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render(title))
+	b.WriteString("\n\n  " + p.input.View() + "\n\n")
+
+	if len(p.matches) == 0 {
+		b.WriteString(label.Render("  no folder matches") + "\n")
+	}
+	rows := max(a.height-11, 3)
+	start := 0
+	if p.selected >= rows {
+		start = p.selected - rows + 1
+	}
+	end := min(start+rows, len(p.matches))
+	for i := start; i < end; i++ {
+		m := p.matches[i]
+		style := lipgloss.NewStyle().Foreground(ColorSecondary)
+		marker := "  "
+		if i == p.selected {
+			style = lipgloss.NewStyle().Foreground(ColorPrimary).Background(ColorBgSelect)
+			marker = "▶ "
+		}
+		line := style.Render(marker) + highlightMatches(p.folders[m.index].DisplayName(), m.positions, style)
+		b.WriteString(lipgloss.NewStyle().MaxWidth(width-4).Render(line) + "\n")
+	}
+
+	b.WriteString("\n" + label.Render("type to filter  enter: move  esc: close"))
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Padding(1, 2).
+		Render(b.String())
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/models"
+)
+
+var (
+	// otpCodePattern matches a standalone 4–8 digit number
+	otpCodePattern = regexp.MustCompile(`\b\d{4,8}\b`)
+
+	// otpContextPattern matches words that show up near one-time codes
+	otpContextPattern = regexp.MustCompile(`(?i)\b(code|verification|verify|otp|one-time|passcode|security|login|sign-in|2fa)\b`)
+)
+
+// otpContextWindow is how many characters around a number are searched for
+// a context word
+const otpContextWindow = 60
+
+// extractOTP looks for a one-time code in the subject, then the body. A
+// number only counts when a word like "code" or "verification" is nearby,
+// and four-digit numbers that look like years are skipped.
+func extractOTP(email *models.Email) (string, bool) {
+	for _, text := range []string{email.Subject, email.TextBody, email.Preview} {
+		if code, ok := findOTP(text); ok {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// findOTP returns the first code-like number with context nearby in text
+func findOTP(text string) (string, bool) {
+	for _, loc := range otpCodePattern.FindAllStringIndex(text, -1) {
+		code := text[loc[0]:loc[1]]
+		if len(code) == 4 {
+			if year, _ := strconv.Atoi(code); year >= 1900 && year <= 2099 {
+				continue
+			}
+		}
+
+		start := loc[0] - otpContextWindow
+		if start < 0 {
+			start = 0
+		}
+		end := loc[1] + otpContextWindow
+		if end > len(text) {
+			end = len(text)
+		}
+		if otpContextPattern.MatchString(text[start:end]) {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// copyOTP puts the detected code on the clipboard
+func (a *App) copyOTP(code string) tea.Cmd {
+	if err := clipboard.WriteAll(code); err != nil {
+		return a.showToast("Copy failed ✗: "+strings.TrimSpace(err.Error()), toastError, 5*time.Second)
+	}
+	return a.showToast("Copied "+code+" ✓", toastSuccess, 3*time.Second)
+}
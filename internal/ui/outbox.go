@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/models"
+	"github.com/the9x/anneal/internal/storage"
+	"github.com/the9x/anneal/internal/ui/views"
+)
+
+// outboxLoadedMsg carries the messages that failed to send
+type outboxLoadedMsg struct {
+	items []storage.OutboxItem
+	err   error
+}
+
+// loadOutbox lists failed sends from the local store
+func (a *App) loadOutbox() tea.Cmd {
+	return func() tea.Msg {
+		if a.store == nil {
+			return outboxLoadedMsg{}
+		}
+		items, err := a.store.GetOutbox(a.client.AccountID())
+		return outboxLoadedMsg{items: items, err: err}
+	}
+}
+
+// isInOutbox returns true if the Outbox folder is selected
+func (a *App) isInOutbox() bool {
+	return a.selectedMailbox < len(a.mailboxes) &&
+		a.mailboxes[a.selectedMailbox].ID == models.OutboxMailboxID
+}
+
+// hasOutbox returns true if any message is waiting to be resent. It reads
+// the store, so it's called from the commands that load the folders.
+func (a *App) hasOutbox() bool {
+	if a.store == nil {
+		return false
+	}
+	items, err := a.store.GetOutbox(a.client.AccountID())
+	return err == nil && len(items) > 0
+}
+
+// showOutboxFolder adds the Outbox to the sidebar after a send fails. It
+// goes away on the next mailbox refresh once it's empty.
+func (a *App) showOutboxFolder() {
+	for _, mb := range a.mailboxes {
+		if mb.ID == models.OutboxMailboxID {
+			return
+		}
+	}
+	a.mailboxes = append(a.mailboxes, models.OutboxMailbox())
+	a.mailboxView = views.NewMailboxView(a.mailboxes)
+	a.mailboxView.Select(a.selectedMailbox)
+}
+
+// outboxEmailID is the list ID of an outbox entry. Outbox messages never
+// reached the server, so they have no JMAP ID.
+func outboxEmailID(id int64) string {
+	return fmt.Sprintf("outbox-%d", id)
+}
+
+// outboxEmails presents failed sends as emails for the message list
+func (a *App) outboxEmails(items []storage.OutboxItem) []models.Email {
+	emails := make([]models.Email, len(items))
+	for i, item := range items {
+		var to []models.EmailAddress
+		for _, addr := range item.Message.To {
+			to = append(to, models.EmailAddress{Email: addr})
+		}
+		preview := strings.Join(strings.Fields(item.Message.Body), " ")
+		if len([]rune(preview)) > 200 {
+			preview = string([]rune(preview)[:200])
+		}
+		id := outboxEmailID(item.ID)
+		emails[i] = models.Email{
+			ID:         id,
+			ThreadID:   id,
+			Subject:    item.Message.Subject,
+			From:       []models.EmailAddress{{Email: a.client.Email()}},
+			To:         to,
+			Preview:    preview,
+			TextBody:   item.Message.Body,
+			ReceivedAt: item.UpdatedAt,
+		}
+	}
+	return emails
+}
+
+// selectedOutboxItem returns the outbox entry under the cursor
+func (a *App) selectedOutboxItem() (storage.OutboxItem, bool) {
+	if a.selectedThread >= len(a.threads) || len(a.threads[a.selectedThread].Emails) == 0 {
+		return storage.OutboxItem{}, false
+	}
+	item, ok := a.outbox[a.threads[a.selectedThread].Emails[0].ID]
+	return item, ok
+}
+
+// handleOutboxKeys gives the list keys their Outbox meaning: enter edits
+// the message, r retries it and d discards it. handled is false for keys
+// that behave as in any other folder.
+func (a *App) handleOutboxKeys(msg tea.KeyMsg) (cmd tea.Cmd, handled bool) {
+	switch {
+	case key.Matches(msg, a.keys.Right), key.Matches(msg, a.keys.Enter):
+		if item, ok := a.selectedOutboxItem(); ok {
+			return a.editOutboxItem(item), true
+		}
+		return nil, true
+	case key.Matches(msg, a.keys.Reply):
+		if item, ok := a.selectedOutboxItem(); ok {
+			return tea.Batch(
+				a.showToast("Sending…", toastInfo, 0),
+				a.sendEmail(item.Message, time.Time{}, item.ID, ""),
+			), true
+		}
+		return nil, true
+	case key.Matches(msg, a.keys.Delete):
+		if item, ok := a.selectedOutboxItem(); ok {
+			return a.discardOutboxItem(item), true
+		}
+		return nil, true
+	case key.Matches(msg, a.keys.ReplyAll), key.Matches(msg, a.keys.Forward):
+		// There's nothing on the server to reply to yet
+		return nil, true
+	}
+	return nil, false
+}
+
+// editOutboxItem reopens a failed message in compose. Sending it replaces
+// the outbox entry.
+func (a *App) editOutboxItem(item storage.OutboxItem) tea.Cmd {
+	_, cmd := a.startCompose(nil, views.ModeCompose)
+	if a.composeView == nil {
+		return nil
+	}
+	m := item.Message
+	a.composeView.SetDraft(m.To, m.CC, m.Subject, m.Body, m.IdentityID, m.FromName)
+	a.composeView.SetAttachments(m.Attachments)
+	a.composeOutbox = &item
+	return cmd
+}
+
+// discardOutboxItem drops a failed message without sending it
+func (a *App) discardOutboxItem(item storage.OutboxItem) tea.Cmd {
+	a.advanceSelection()
+	return func() tea.Msg {
+		return emailActionMsg{err: a.store.DeleteOutboxItem(a.client.AccountID(), item.ID)}
+	}
+}
+
+// outboxStatus explains why the selected outbox message hasn't gone out
+func (a *App) outboxStatus() string {
+	item, ok := a.selectedOutboxItem()
+	if !ok {
+		return ""
+	}
+	attempts := "1 attempt"
+	if item.Attempts != 1 {
+		attempts = fmt.Sprintf("%d attempts", item.Attempts)
+	}
+	return fmt.Sprintf("✗ %s, last: %s", attempts, item.LastError)
+}
@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/jmap"
+	"github.com/the9x/anneal/internal/models"
+)
+
+func TestOutboxShownFromLoadedMailboxes(t *testing.T) {
+	inbox := []models.Mailbox{{ID: "inbox", Name: "Inbox", Role: "inbox"}}
+
+	for _, outbox := range []bool{false, true} {
+		a := &App{cfg: config.DefaultConfig(), client: &jmap.Client{}}
+		a.Update(mailboxesLoadedMsg{mailboxes: inbox, outbox: outbox})
+
+		shown := false
+		for _, mb := range a.mailboxes {
+			shown = shown || mb.ID == models.OutboxMailboxID
+		}
+		if shown != outbox {
+			t.Errorf("outbox waiting = %v, but Outbox shown = %v", outbox, shown)
+		}
+	}
+}
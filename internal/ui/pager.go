@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultPager is used when $PAGER is unset
+const defaultPager = "less -R"
+
+// pagerClosedMsg is sent when the external pager exits
+type pagerClosedMsg struct {
+	err error
+}
+
+// openInPager suspends the TUI and pipes text to $PAGER. The reader is left
+// exactly as it was when the pager exits.
+func (a *App) openInPager(text string) tea.Cmd {
+	pager := strings.TrimSpace(os.Getenv("PAGER"))
+	if pager == "" {
+		pager = defaultPager
+	}
+
+	args := strings.Fields(pager)
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return func() tea.Msg {
+			return pagerClosedMsg{err: fmt.Errorf("pager %q not found; set $PAGER", args[0])}
+		}
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return pagerClosedMsg{err: err}
+	})
+}
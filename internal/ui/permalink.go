@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/models"
+)
+
+// fastmailWebURL is where Fastmail's web app shows a message. {mailbox},
+// {thread} and {email} are filled in from the message.
+const fastmailWebURL = "https://app.fastmail.com/mail/{mailbox}/{thread}.{email}"
+
+// webURLOff in an account's web_url turns the link off
+const webURLOff = "off"
+
+// webURLForEmail returns the address of the email in the provider's web
+// app, from the account's web_url or Fastmail's by default. ok is false
+// when the link is turned off or the template needs something the email
+// doesn't have.
+func (a *App) webURLForEmail(email *models.Email) (string, bool) {
+	if email == nil || email.ID == "" {
+		return "", false
+	}
+	template := fastmailWebURL
+	if account := a.cfg.FindAccount(a.client.Email()); account != nil && account.WebURL != "" {
+		template = account.WebURL
+	}
+	if template == webURLOff {
+		return "", false
+	}
+
+	mailbox := a.emailMailboxName(email)
+	if strings.Contains(template, "{mailbox}") && mailbox == "" {
+		return "", false
+	}
+	if strings.Contains(template, "{thread}") && email.ThreadID == "" {
+		return "", false
+	}
+	return strings.NewReplacer(
+		"{mailbox}", url.PathEscape(mailbox),
+		"{thread}", url.PathEscape(email.ThreadID),
+		"{email}", url.PathEscape(email.ID),
+	).Replace(template), true
+}
+
+// emailMailboxName returns the name of a folder the email is in, or of
+// the open folder when the email doesn't say
+func (a *App) emailMailboxName(email *models.Email) string {
+	for _, id := range email.MailboxIDs {
+		for _, mb := range a.mailboxes {
+			if mb.ID == id {
+				return mb.Name
+			}
+		}
+	}
+	if mb := a.currentMailbox(); mb != nil && !mb.IsVirtual() {
+		return mb.Name
+	}
+	return ""
+}
+
+// copyWebURL puts the open email's web address on the clipboard
+func (a *App) copyWebURL() tea.Cmd {
+	link, ok := a.webURLForEmail(a.currentEmail)
+	if !ok {
+		return a.showToast("No web link for this message", toastInfo, 3*time.Second)
+	}
+	if err := clipboard.WriteAll(link); err != nil {
+		return a.showToast("Copy failed ✗: "+strings.TrimSpace(err.Error()), toastError, 5*time.Second)
+	}
+	return a.showToast("Copied web link ✓", toastSuccess, 3*time.Second)
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// pipeTimeout stops commands that hang, like a translator waiting on
+	// the network
+	pipeTimeout = 60 * time.Second
+
+	// pipeMaxOutput caps how much output is kept for the reader
+	pipeMaxOutput = 1 << 20
+)
+
+// pipeFinishedMsg carries the output of a command the body was piped to
+type pipeFinishedMsg struct {
+	emailID string
+	command string
+	output  string
+	err     error
+}
+
+// limitedBuffer keeps the first max bytes written to it and drops the rest
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// pipeBody runs the configured pipe command with the message body on stdin.
+// The command runs through the shell so the template can use pipes and
+// quoting; the subject and sender are passed as $ANNEAL_SUBJECT and
+// $ANNEAL_FROM rather than spliced into the command line.
+func (a *App) pipeBody() tea.Cmd {
+	command := strings.TrimSpace(a.cfg.PipeCommand)
+	if command == "" {
+		return a.showToast("Set pipe_command in config.yaml to pipe messages", toastError, 4*time.Second)
+	}
+	if a.currentEmail == nil || a.emailReader == nil {
+		return nil
+	}
+
+	email := *a.currentEmail
+	body := email.TextBody
+	if body == "" {
+		body = a.emailReader.RenderedBody()
+	}
+
+	run := func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), pipeTimeout)
+		defer cancel()
+
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+		} else {
+			cmd = exec.CommandContext(ctx, "sh", "-c", command)
+		}
+		cmd.Stdin = strings.NewReader(body)
+		cmd.Env = append(os.Environ(),
+			"ANNEAL_SUBJECT="+email.Subject,
+			"ANNEAL_FROM="+email.FromDisplay(),
+		)
+
+		stdout := &limitedBuffer{max: pipeMaxOutput}
+		stderr := &limitedBuffer{max: 4096}
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+
+		err := cmd.Run()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("%s timed out after %s", command, pipeTimeout)
+		} else if err != nil {
+			// The first line of stderr usually says what went wrong
+			reason := strings.TrimSpace(stderr.buf.String())
+			if i := strings.IndexByte(reason, '\n'); i >= 0 {
+				reason = reason[:i]
+			}
+			if reason == "" {
+				reason = err.Error()
+			}
+			err = fmt.Errorf("%s: %s", command, reason)
+		}
+
+		output := stdout.buf.String()
+		if stdout.truncated {
+			output += fmt.Sprintf("\n\n[output truncated at %d KB]", pipeMaxOutput/1024)
+		}
+		return pipeFinishedMsg{emailID: email.ID, command: command, output: output, err: err}
+	}
+
+	return tea.Batch(a.showToast("Running "+command+"…", toastInfo, pipeTimeout), run)
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// bodiesPrefetchedMsg reports the end of a background body prefetch
+type bodiesPrefetchedMsg struct {
+	count int
+	err   error
+}
+
+// prefetchBodies caches the bodies of the newest email in each thread on
+// the first screen, so opening them doesn't wait on the network. Bodies
+// already in the cache are skipped and everything happens in one request.
+func (a *App) prefetchBodies() tea.Cmd {
+	limit := a.cfg.PrefetchCount
+	if a.store == nil || limit <= 0 {
+		return nil
+	}
+	// Outbox messages are local; there's nothing to fetch
+	if a.isInOutbox() {
+		return nil
+	}
+
+	// Only the rows visible in the list
+	if visible := a.height - 9; visible > 0 && visible < limit {
+		limit = visible
+	}
+
+	var candidates []string
+	for _, t := range a.threads {
+		if len(candidates) >= limit {
+			break
+		}
+		if len(t.Emails) > 0 {
+			candidates = append(candidates, t.Emails[0].ID)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		var ids []string
+		for _, id := range candidates {
+			if cached, err := a.store.HasEmailBody(id); err == nil && !cached {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return bodiesPrefetchedMsg{}
+		}
+
+		emails, err := a.client.GetEmailBodies(ids)
+		if err != nil {
+			return bodiesPrefetchedMsg{err: err}
+		}
+		for _, email := range emails {
+			a.store.SaveEmailBody(email)
+		}
+		return bodiesPrefetchedMsg{count: len(emails)}
+	}
+}
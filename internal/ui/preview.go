@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/models"
+)
+
+// attributionRe matches the "On <date>, <name> wrote:" line above a quote
+var attributionRe = regexp.MustCompile(`(?i)^on .+ wrote:$`)
+
+// bodyPreviewsMsg carries previews built from cached bodies
+type bodyPreviewsMsg struct {
+	previews map[string]string
+}
+
+// previewFor returns the preview shown for an email. With preview_source
+// set to body, it's built from the body when one has been loaded, since
+// the server preview is often boilerplate like "View this email in your
+// browser". It runs on every render, so it never reads the cache itself;
+// loadBodyPreviews does that in the background.
+func (a *App) previewFor(email models.Email) string {
+	if a.cfg.PreviewSource != config.PreviewSourceBody {
+		return email.Preview
+	}
+	if preview, ok := a.bodyPreviews[email.ID]; ok {
+		return preview
+	}
+
+	preview := bodyPreview(email)
+	if preview == "" {
+		// Body not loaded yet; try again once it is
+		return email.Preview
+	}
+	a.bodyPreviews[email.ID] = preview
+	return preview
+}
+
+// loadBodyPreviews builds previews for the emails from their cached
+// bodies, off the render path
+func (a *App) loadBodyPreviews(emails []models.Email) tea.Cmd {
+	if a.cfg.PreviewSource != config.PreviewSourceBody || a.store == nil {
+		return nil
+	}
+
+	var ids []string
+	for _, e := range emails {
+		if _, ok := a.bodyPreviews[e.ID]; !ok && e.TextBody == "" {
+			ids = append(ids, e.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		previews := make(map[string]string)
+		for _, id := range ids {
+			cached, err := a.store.GetEmailBody(id)
+			if err != nil || cached == nil {
+				continue
+			}
+			if preview := bodyPreview(*cached); preview != "" {
+				previews[id] = preview
+			}
+		}
+		return bodyPreviewsMsg{previews: previews}
+	}
+}
+
+// bodyPreview builds a one-line preview from the text body, leaving out
+// quoted replies, their attribution line and the signature
+func bodyPreview(email models.Email) string {
+	var words []string
+	for _, line := range strings.Split(email.TextBody, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "--" || trimmed == "-- " || line == "-- " {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") || attributionRe.MatchString(trimmed) {
+			continue
+		}
+		words = append(words, strings.Fields(trimmed)...)
+
+		// Enough for any sensible preview length
+		if len(words) > 100 {
+			break
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// truncatePreview shortens a preview to n characters with an ellipsis. A
+// length of 0 or less keeps the whole preview.
+func truncatePreview(preview string, n int) string {
+	runes := []rune(preview)
+	if n <= 0 || len(runes) <= n {
+		return preview
+	}
+	if n <= 3 {
+		return string(runes[:n])
+	}
+	return string(runes[:n-3]) + "..."
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/models"
+	"github.com/the9x/anneal/internal/storage"
+)
+
+func TestBodyPreviewsLoadOffRenderPath(t *testing.T) {
+	t.Setenv("ANNEAL_CACHE_DIR", "")
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	email := models.Email{
+		ID:         "e1",
+		ThreadID:   "t1",
+		MailboxIDs: []string{"inbox"},
+		Preview:    "View this email in your browser",
+		TextBody:   "Lunch on Friday?\n\nOn Mon, Bob wrote:\n> hi\n-- \nAnn",
+	}
+	if err := store.SaveEmails("acct", []models.Email{email}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveEmailBody(&email); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.PreviewSource = config.PreviewSourceBody
+	a := &App{cfg: cfg, store: store, bodyPreviews: make(map[string]string)}
+
+	listed := email
+	listed.TextBody = ""
+	if got := a.previewFor(listed); got != email.Preview {
+		t.Errorf("preview before loading = %q, want the server preview", got)
+	}
+
+	cmd := a.loadBodyPreviews([]models.Email{listed})
+	if cmd == nil {
+		t.Fatal("no command to load previews")
+	}
+	a.Update(cmd())
+	if got, want := a.previewFor(listed), "Lunch on Friday?"; got != want {
+		t.Errorf("preview after loading = %q, want %q", got, want)
+	}
+	if a.loadBodyPreviews([]models.Email{listed}) != nil {
+		t.Error("previews already loaded are loaded again")
+	}
+}
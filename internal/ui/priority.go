@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// priorityDataLoadedMsg carries the data the Focused/Other classifier needs
+type priorityDataLoadedMsg struct {
+	replied   map[string]bool
+	overrides map[string]bool
+	err       error
+}
+
+// senderTrainedMsg reports whether a Focused/Other override was saved
+type senderTrainedMsg struct {
+	err error
+}
+
+// loadPriorityData reads reply history and sender overrides from the cache
+func (a *App) loadPriorityData() tea.Msg {
+	accountID := a.client.AccountID()
+	replied, err := a.store.GetRepliedAddresses(accountID)
+	if err != nil {
+		return priorityDataLoadedMsg{err: err}
+	}
+	overrides, err := a.store.GetSenderPriorities(accountID)
+	return priorityDataLoadedMsg{replied: replied, overrides: overrides, err: err}
+}
+
+// priorityInboxActive returns true if the current folder is split into
+// Focused and Other
+func (a *App) priorityInboxActive() bool {
+	if !a.cfg.PriorityInbox || a.store == nil {
+		return false
+	}
+	return a.selectedMailbox < len(a.mailboxes) && a.mailboxes[a.selectedMailbox].Role == "inbox"
+}
+
+// isFocusedSender classifies a single sender address. An explicit
+// override wins; otherwise people we've written to, and ourselves, are
+// focused.
+func (a *App) isFocusedSender(addr string) bool {
+	addr = strings.ToLower(addr)
+	if focused, ok := a.senderPriority[addr]; ok {
+		return focused
+	}
+	return a.repliedSenders[addr] || a.isOwnAddress(addr)
+}
+
+// isOwnAddress returns true for the account address or any identity
+func (a *App) isOwnAddress(addr string) bool {
+	if strings.EqualFold(addr, a.client.Email()) {
+		return true
+	}
+	for _, id := range a.identities {
+		if strings.EqualFold(addr, id.Email) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFocusedThread returns true if any message in the thread is from a
+// focused sender. Our own replies don't count, or every conversation we
+// answered would stay focused whatever the other side was trained to; a
+// thread of only our own messages is focused.
+func (a *App) isFocusedThread(t Thread) bool {
+	others := false
+	for _, email := range t.Emails {
+		if len(email.From) == 0 || a.isOwnAddress(email.From[0].Email) {
+			continue
+		}
+		others = true
+		if a.isFocusedSender(email.From[0].Email) {
+			return true
+		}
+	}
+	return !others
+}
+
+// threadSender returns the first sender in the thread who isn't us, the
+// one training a thread applies to
+func (a *App) threadSender(t Thread) string {
+	for _, email := range t.Emails {
+		if len(email.From) > 0 && !a.isOwnAddress(email.From[0].Email) {
+			return strings.ToLower(email.From[0].Email)
+		}
+	}
+	return ""
+}
+
+// filterPriority keeps only the threads belonging to the visible section
+func (a *App) filterPriority(threads []Thread) []Thread {
+	var filtered []Thread
+	for _, t := range threads {
+		if a.isFocusedThread(t) != a.showOther {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// trainSender moves the sender of the selected thread to the other section
+func (a *App) trainSender() tea.Cmd {
+	if a.selectedThread >= len(a.threads) {
+		return nil
+	}
+	thread := a.threads[a.selectedThread]
+	sender := a.threadSender(thread)
+	if sender == "" {
+		return nil
+	}
+
+	focused := !a.isFocusedSender(sender)
+	if a.senderPriority == nil {
+		a.senderPriority = make(map[string]bool)
+	}
+	a.senderPriority[sender] = focused
+	a.applyThreadFilters()
+	a.clampThreadSelection()
+
+	accountID := a.client.AccountID()
+	return func() tea.Msg {
+		err := a.store.SetSenderPriority(accountID, sender, focused)
+		return senderTrainedMsg{err: err}
+	}
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/jmap"
+	"github.com/the9x/anneal/internal/models"
+)
+
+func TestTrainThreadWithOwnReply(t *testing.T) {
+	from := func(addr string) models.Email {
+		return models.Email{From: []models.EmailAddress{{Email: addr}}}
+	}
+	a := &App{
+		cfg:            config.DefaultConfig(),
+		client:         &jmap.Client{},
+		identities:     []jmap.Identity{{Email: "me@example.com"}},
+		repliedSenders: map[string]bool{"news@example.com": true},
+	}
+	thread := Thread{ID: "t1", Emails: []models.Email{from("me@example.com"), from("News@example.com")}}
+	a.threads = []Thread{thread}
+
+	if !a.isFocusedThread(thread) {
+		t.Fatal("thread with a replied-to sender isn't focused")
+	}
+	a.trainSender()
+	if got := a.senderPriority["news@example.com"]; got {
+		t.Errorf("trained news@example.com to focused, want other")
+	}
+	if _, ok := a.senderPriority["me@example.com"]; ok {
+		t.Error("trained our own address")
+	}
+	if a.isFocusedThread(thread) {
+		t.Error("our own reply keeps the thread focused after training it to Other")
+	}
+
+	if !a.isFocusedThread(Thread{Emails: []models.Email{from("me@example.com")}}) {
+		t.Error("thread of only our own messages isn't focused")
+	}
+}
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// readAdjacent opens the next (step 1) or previous (step -1) email from the
+// reader without going back to the list. It walks the folder in list order
+// and each conversation in the order the thread view lists it, so reading
+// on from the end of a thread starts the next one at its top.
+func (a *App) readAdjacent(step int) tea.Cmd {
+	if a.selectedThread >= len(a.threads) {
+		return nil
+	}
+
+	threadIdx, emailIdx := a.selectedThread, a.selectedInThread+step
+	if emailIdx < 0 || emailIdx >= len(a.threads[threadIdx].Emails) {
+		threadIdx += step
+		if threadIdx < 0 || threadIdx >= len(a.threads) {
+			text := "No newer messages in this folder"
+			if step > 0 {
+				text = "No more messages in this folder"
+			}
+			return a.showToast(text, toastInfo, 2*time.Second)
+		}
+		emailIdx = 0
+		if step < 0 {
+			emailIdx = len(a.threads[threadIdx].Emails) - 1
+		}
+	}
+
+	a.rememberScroll()
+	a.selectedThread = threadIdx
+	a.selectedInThread = emailIdx
+	if a.threadList != nil {
+		a.threadList.Select(a.selectedThread)
+	}
+	a.loading = true
+	return a.loadEmail(a.threads[threadIdx].Emails[emailIdx].ID)
+}
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/jmap"
+	"github.com/the9x/anneal/internal/models"
+)
+
+func TestReadAdjacentAfterSingleEmailThread(t *testing.T) {
+	a := &App{cfg: config.DefaultConfig(), client: &jmap.Client{}}
+	a.threads = []Thread{
+		{ID: "t0", Emails: []models.Email{{ID: "a"}, {ID: "b"}, {ID: "c"}}},
+		{ID: "t1", Emails: []models.Email{{ID: "d"}}},
+		{ID: "t2", Emails: []models.Email{{ID: "e"}}},
+	}
+
+	// Reading the middle of a conversation, then opening a one-email
+	// thread from the list
+	a.selectedInThread = 1
+	a.selectedThread = 1
+	a.openSelectedThread()
+
+	a.readAdjacent(-1)
+	if a.selectedThread != 0 || a.selectedInThread != 2 {
+		t.Errorf("previous from the single email went to thread %d email %d, want thread 0 email 2",
+			a.selectedThread, a.selectedInThread)
+	}
+
+	a.selectedThread = 1
+	a.openSelectedThread()
+	a.readAdjacent(1)
+	if a.selectedThread != 2 || a.selectedInThread != 0 {
+		t.Errorf("next from the single email went to thread %d email %d, want thread 2 email 0",
+			a.selectedThread, a.selectedInThread)
+	}
+}
@@ -0,0 +1,25 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fail shows err on the error screen; retry, when not nil, reissues the
+// load that failed and is run when r is pressed
+func (a *App) fail(err error, retry tea.Cmd) {
+	a.err = err
+	a.retry = retry
+}
+
+// handleErrorKeys retries the failed load on r, if there is one to retry;
+// any other key dismisses the error
+func (a *App) handleErrorKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	retry := a.retry
+	a.err = nil
+	a.retry = nil
+	if msg.String() == "r" && retry != nil {
+		a.loading = true
+		return a, retry
+	}
+	return a, nil
+}
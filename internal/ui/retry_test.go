@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/models"
+)
+
+func TestLaterErrorDropsOldRetry(t *testing.T) {
+	a := &App{cfg: config.DefaultConfig()}
+	a.fail(errors.New("load failed"), func() tea.Msg { return nil })
+
+	a.Update(browserOpenedMsg{err: errors.New("no browser")})
+	if a.retry != nil {
+		t.Error("r would retry an earlier failure after a different error")
+	}
+}
+
+func TestFailedLoadRetriesItsOwnFolder(t *testing.T) {
+	a := &App{cfg: config.DefaultConfig()}
+	a.mailboxes = []models.Mailbox{{ID: "inbox", Role: "inbox"}}
+
+	// A load with no folder to name has nothing to retry, whatever is open
+	a.Update(emailsLoadedMsg{err: errors.New("offline")})
+	if a.retry != nil {
+		t.Error("a failed load retries the open folder instead of its own")
+	}
+
+	a.Update(emailsLoadedMsg{mailboxID: "archive", err: errors.New("offline")})
+	if a.retry == nil {
+		t.Error("a failed folder load can't be retried")
+	}
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/models"
+)
+
+// currentMailbox returns the selected mailbox, or nil before mailboxes load
+func (a *App) currentMailbox() *models.Mailbox {
+	if a.selectedMailbox < len(a.mailboxes) {
+		return &a.mailboxes[a.selectedMailbox]
+	}
+	return nil
+}
+
+// isInSent returns true if the Sent folder is selected, where mail is
+// dated by when it was sent rather than received
+func (a *App) isInSent() bool {
+	mb := a.currentMailbox()
+	return mb != nil && mb.Role == "sent"
+}
+
+// mailboxByRole returns the mailbox with the given role, or nil
+func (a *App) mailboxByRole(role string) *models.Mailbox {
+	for i := range a.mailboxes {
+		if a.mailboxes[i].Role == role {
+			return &a.mailboxes[i]
+		}
+	}
+	return nil
+}
+
+// checkRights stops actions the mailbox rights don't allow before they
+// reach the server, returning a status message in their place. ok is
+// false when the key was blocked.
+func (a *App) checkRights(msg tea.KeyMsg) (cmd tea.Cmd, ok bool) {
+	mb := a.currentMailbox()
+	if mb == nil {
+		return nil, true
+	}
+
+	switch {
+	case key.Matches(msg, a.keys.Delete):
+		if a.isInScheduled() || a.isInOutbox() {
+			return nil, true
+		}
+		if !mb.CanRemove() {
+			return a.denied("can't delete from " + mb.DisplayName())
+		}
+		if trash := a.mailboxByRole("trash"); trash != nil && !trash.CanAdd() {
+			return a.denied("can't move messages to Trash")
+		}
+	case key.Matches(msg, a.keys.Archive), key.Matches(msg, a.keys.ArchiveRead):
+		if !mb.CanRemove() {
+			return a.denied("can't archive from " + mb.DisplayName())
+		}
+		if archive := a.mailboxByRole("archive"); archive != nil && !archive.CanAdd() {
+			return a.denied("can't move messages to Archive")
+		}
+	case key.Matches(msg, a.keys.Move), key.Matches(msg, a.keys.Snooze):
+		if !mb.CanRemove() {
+			return a.denied("can't move messages out of " + mb.DisplayName())
+		}
+	case key.Matches(msg, a.keys.MarkUnread):
+		// While an archive can be undone, "u" restores it instead
+		if a.toast != nil && a.toast.undo {
+			return nil, true
+		}
+		if a.isInTrash() {
+			if !mb.CanRemove() {
+				return a.denied("can't restore from " + mb.DisplayName())
+			}
+		} else if !mb.CanSetSeen() {
+			return a.denied("can't change read state in " + mb.DisplayName())
+		}
+	case key.Matches(msg, a.keys.Star), key.Matches(msg, a.keys.Todo):
+		if !mb.CanSetKeywords() {
+			return a.denied("can't flag messages in " + mb.DisplayName())
+		}
+	}
+	return nil, true
+}
+
+// denied shows why an action was blocked
+func (a *App) denied(reason string) (tea.Cmd, bool) {
+	return a.showToast("Read-only: "+reason, toastError, 4*time.Second), false
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/jmap"
+	"github.com/the9x/anneal/internal/models"
+)
+
+// scheduledLoadedMsg carries the held submissions along with their emails
+type scheduledLoadedMsg struct {
+	sends  []jmap.ScheduledSend
+	emails []models.Email
+	err    error
+}
+
+// loadScheduled lists messages the server is holding for a scheduled send.
+// Submissions live only on the server, so there is no cached fallback.
+func (a *App) loadScheduled() tea.Cmd {
+	return func() tea.Msg {
+		sends, err := a.client.GetScheduledSends()
+		if err != nil || len(sends) == 0 {
+			return scheduledLoadedMsg{err: err}
+		}
+
+		ids := make([]string, len(sends))
+		for i, s := range sends {
+			ids[i] = s.EmailID
+		}
+		emails, err := a.client.GetEmailsByIDs(ids)
+		return scheduledLoadedMsg{sends: sends, emails: emails, err: err}
+	}
+}
+
+// isInScheduled returns true if the Scheduled folder is selected
+func (a *App) isInScheduled() bool {
+	return a.selectedMailbox < len(a.mailboxes) &&
+		a.mailboxes[a.selectedMailbox].ID == models.ScheduledMailboxID
+}
+
+// cancelScheduled stops the scheduled send of every email in a thread
+func (a *App) cancelScheduled(emails []models.Email) tea.Cmd {
+	var sends []jmap.ScheduledSend
+	for _, e := range emails {
+		if s, ok := a.scheduled[e.ID]; ok {
+			sends = append(sends, s)
+		}
+	}
+
+	return func() tea.Msg {
+		for _, s := range sends {
+			if err := a.client.CancelScheduledSend(s); err != nil {
+				return emailActionMsg{err: err}
+			}
+		}
+		return emailActionMsg{err: nil}
+	}
+}
+
+// scheduledDate formats a send time for the list's date column
+func scheduledDate(t time.Time) string {
+	if time.Until(t) > 6*24*time.Hour {
+		return t.Format("Jan 2")
+	}
+	return t.Format("Mon 15:04")
+}
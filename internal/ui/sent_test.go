@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/models"
+	"github.com/the9x/anneal/internal/ui/views"
+)
+
+func TestReaderDatesFollowOpenFolder(t *testing.T) {
+	a := &App{cfg: config.DefaultConfig(), width: 100, height: 40}
+	a.mailboxes = []models.Mailbox{{ID: "inbox", Role: "inbox"}, {ID: "sent", Role: "sent"}}
+	a.selectedMailbox = 1
+	email := &models.Email{
+		Subject:    "Draft agenda",
+		TextBody:   "Attached.",
+		SentAt:     time.Date(2026, 1, 5, 9, 0, 0, 0, time.Local),
+		ReceivedAt: time.Date(2026, 1, 5, 9, 2, 0, 0, time.Local),
+	}
+	a.emailReader = views.NewEmailReaderView(email, 80, 34, 0)
+
+	if out := a.renderEmailReader(80); !strings.Contains(out, "Sent") {
+		t.Error("mail read in the Sent folder isn't dated by when it was sent")
+	}
+	a.selectedMailbox = 0
+	if out := a.renderEmailReader(80); strings.Contains(out, "Sent") {
+		t.Error("the sent date stays after the folder changed")
+	}
+}
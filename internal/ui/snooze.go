@@ -0,0 +1,203 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/the9x/anneal/internal/jmap"
+	"github.com/the9x/anneal/internal/models"
+)
+
+// snoozeOption is a time a conversation can be snoozed until
+type snoozeOption struct {
+	label string
+	until time.Time
+}
+
+// snoozePicker offers the times the selected conversation can be snoozed
+// until, in place of the main pane
+type snoozePicker struct {
+	threadID string
+	emails   []models.Email
+	options  []snoozeOption
+	selected int
+}
+
+// snoozedMsg reports the result of snoozing a conversation. local is true
+// when the server had no snooze and this device will wake it.
+type snoozedMsg struct {
+	threadID string
+	until    time.Time
+	local    bool
+	err      error
+}
+
+// snoozeOptions returns the wake times offered from now: in a few hours,
+// tomorrow morning, the weekend (on weekdays) and next week
+func snoozeOptions(now time.Time) []snoozeOption {
+	at := func(d time.Time, hour int) time.Time {
+		return time.Date(d.Year(), d.Month(), d.Day(), hour, 0, 0, 0, d.Location())
+	}
+
+	options := []snoozeOption{
+		{"Later today", now.Add(3 * time.Hour).Truncate(time.Hour)},
+		{"Tomorrow morning", at(now.AddDate(0, 0, 1), 8)},
+	}
+	if wd := now.Weekday(); wd != time.Saturday && wd != time.Sunday {
+		options = append(options, snoozeOption{"This weekend", at(now.AddDate(0, 0, int(time.Saturday-wd)), 9)})
+	}
+	daysToMonday := (int(time.Monday-now.Weekday())+6)%7 + 1
+	options = append(options, snoozeOption{"Next week", at(now.AddDate(0, 0, daysToMonday), 8)})
+	return options
+}
+
+// openSnoozePicker offers wake times for the selected conversation
+func (a *App) openSnoozePicker() tea.Cmd {
+	if a.isInScheduled() || a.isInOutbox() || a.selectedThread >= len(a.threads) {
+		return nil
+	}
+	if mb := a.currentMailbox(); mb != nil && mb.Role == "snoozed" {
+		return a.showToast("Already snoozed", toastInfo, 3*time.Second)
+	}
+	if a.mailboxByRole("snoozed") == nil && (a.store == nil || a.mailboxByRole("archive") == nil) {
+		return a.showToast("Snoozing needs the server's Snoozed folder, or the local cache and an Archive folder", toastError, 4*time.Second)
+	}
+
+	thread := a.threads[a.selectedThread]
+	if len(thread.Emails) == 0 {
+		return nil
+	}
+	a.snooze = &snoozePicker{
+		threadID: thread.ID,
+		emails:   thread.Emails,
+		options:  snoozeOptions(time.Now()),
+	}
+	return nil
+}
+
+// handleSnoozePickerKeys picks a wake time with the arrows and enter, or
+// its number; esc closes the picker
+func (a *App) handleSnoozePickerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := a.snooze
+	switch s := msg.String(); s {
+	case "up", "k":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "j":
+		if p.selected < len(p.options)-1 {
+			p.selected++
+		}
+	case "enter":
+		a.snooze = nil
+		return a, a.snoozeThread(p, p.options[p.selected].until)
+	case "esc", "q", "Z":
+		a.snooze = nil
+	default:
+		if len(s) == 1 && s[0] >= '1' && int(s[0]-'1') < len(p.options) {
+			a.snooze = nil
+			return a, a.snoozeThread(p, p.options[s[0]-'1'].until)
+		}
+	}
+	return a, nil
+}
+
+// snoozeThread snoozes the picker's conversation until the given time.
+// The server's snooze is preferred, so the mail comes back on every
+// device; without one, or if the server refuses, the mail is archived and
+// the snooze kept in the local cache, to be moved back by a later sync.
+func (a *App) snoozeThread(p *snoozePicker, until time.Time) tea.Cmd {
+	ids := make([]string, len(p.emails))
+	for i, e := range p.emails {
+		ids[i] = e.ID
+	}
+	snoozedID := a.mailboxIDByRole("snoozed")
+	archiveID := a.mailboxIDByRole("archive")
+	returnID := a.mailboxIDByRole("inbox")
+	if mb := a.currentMailbox(); mb != nil && !mb.IsVirtual() {
+		returnID = mb.ID
+	}
+	accountID := a.client.AccountID()
+	threadID := p.threadID
+
+	snooze := func() tea.Msg {
+		if snoozedID != "" {
+			err := a.client.SnoozeEmails(ids, snoozedID, until)
+			if err == nil {
+				return snoozedMsg{threadID: threadID, until: until}
+			}
+			jmap.Logf("snooze: %v; snoozing locally", err)
+		}
+		if a.store == nil || archiveID == "" || returnID == "" {
+			return snoozedMsg{threadID: threadID, err: errors.New("the server didn't take the snooze")}
+		}
+		if err := a.client.MoveEmails(ids, archiveID); err != nil {
+			return snoozedMsg{threadID: threadID, err: err}
+		}
+		if err := a.store.SaveSnoozes(accountID, ids, until, returnID); err != nil {
+			// Without the record nothing would bring the mail back
+			a.client.MoveEmails(ids, returnID)
+			return snoozedMsg{threadID: threadID, err: err}
+		}
+		return snoozedMsg{threadID: threadID, until: until, local: true}
+	}
+
+	a.leaving[threadID] = true
+	if a.viewState == ViewEmail {
+		a.rememberScroll()
+		a.currentEmail = nil
+	}
+	if a.selectedThread < len(a.threads) {
+		a.threads[a.selectedThread].Expanded = false
+	}
+	a.advanceSelection()
+	return snooze
+}
+
+// handleSnoozed confirms the snooze and reloads the list
+func (a *App) handleSnoozed(msg snoozedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		delete(a.leaving, msg.threadID)
+		return a, a.showToast("Snooze failed ✗: "+msg.err.Error(), toastError, 5*time.Second)
+	}
+	text := "Snoozed until " + msg.until.Format("Mon Jan 2, 15:04")
+	if msg.local {
+		text += " (wakes while anneal runs)"
+	}
+	toastCmd := a.showToast(text, toastSuccess, 4*time.Second)
+	if mb := a.currentMailbox(); mb != nil {
+		return a, tea.Batch(toastCmd, a.loadEmailsFresh(mb.ID))
+	}
+	return a, toastCmd
+}
+
+// renderSnoozePicker renders the wake times in place of the main pane
+func (a *App) renderSnoozePicker(width int) string {
+	p := a.snooze
+	label := lipgloss.NewStyle().Foreground(ColorDim)
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render("◇ snooze until"))
+	b.WriteString("\n\n")
+
+	for i, opt := range p.options {
+		line := fmt.Sprintf("  %d  %-18s %s", i+1, opt.label, opt.until.Format("Mon Jan 2, 15:04"))
+		style := lipgloss.NewStyle().Foreground(ColorSecondary)
+		if i == p.selected {
+			line = "▶" + line[1:]
+			style = lipgloss.NewStyle().Foreground(ColorPrimary).Background(ColorBgSelect)
+		}
+		b.WriteString(style.MaxWidth(width-4).Render(line) + "\n")
+	}
+
+	b.WriteString("\n" + label.Render(fmt.Sprintf("1-%d or enter: snooze  esc: close", len(p.options))))
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Padding(1, 2).
+		Render(b.String())
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnoozeOptions(t *testing.T) {
+	// Wednesday afternoon
+	now := time.Date(2026, 10, 14, 14, 20, 0, 0, time.UTC)
+	want := []time.Time{
+		time.Date(2026, 10, 14, 17, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC),
+	}
+	got := snoozeOptions(now)
+	if len(got) != len(want) {
+		t.Fatalf("got %d options, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].until.Equal(want[i]) {
+			t.Errorf("%s = %v, want %v", got[i].label, got[i].until, want[i])
+		}
+	}
+
+	// On a Sunday there's no weekend left, and next week is tomorrow
+	sunday := snoozeOptions(time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC))
+	if len(sunday) != 3 {
+		t.Fatalf("got %d options on a Sunday, want 3", len(sunday))
+	}
+	if next := sunday[2].until; !next.Equal(time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("next week from Sunday = %v", next)
+	}
+
+	// From a Monday, next week is the Monday after
+	monday := snoozeOptions(time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC))
+	if next := monday[len(monday)-1].until; !next.Equal(time.Date(2026, 10, 26, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("next week from Monday = %v", next)
+	}
+}
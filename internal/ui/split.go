@@ -0,0 +1,298 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/the9x/anneal/internal/models"
+	"github.com/the9x/anneal/internal/ui/views"
+)
+
+// splitPane is the second folder shown beside the message list in the
+// side-by-side view, for moving mail between the two
+type splitPane struct {
+	mailbox  models.Mailbox
+	threads  []Thread
+	selected int
+	list     *views.ThreadListView
+	right    bool // focus is on this pane rather than the message list
+	repaired bool // the folder pairing changed and is saved on close
+}
+
+// splitLoadedMsg carries the emails of the folder in the right pane
+type splitLoadedMsg struct {
+	mailboxID string
+	emails    []models.Email
+	err       error
+}
+
+// splitMovedMsg reports a thread moved from one pane to the other
+type splitMovedMsg struct {
+	count  int
+	folder string
+	err    error
+}
+
+// openSplit shows the open folder beside the one last paired with it, or
+// Archive the first time
+func (a *App) openSplit() tea.Cmd {
+	left := a.currentMailbox()
+	if left == nil || left.IsVirtual() {
+		return a.showToast("Side by side needs a real folder on the left", toastError, 3*time.Second)
+	}
+
+	var right *models.Mailbox
+	if account := a.cfg.FindAccount(a.client.Email()); account != nil {
+		if name, ok := account.SplitFolders[left.Name]; ok {
+			right = a.splitCandidate(func(mb *models.Mailbox) bool { return mb.Name == name })
+		}
+	}
+	if right == nil {
+		right = a.splitCandidate(func(mb *models.Mailbox) bool { return mb.Role == "archive" })
+	}
+	if right == nil {
+		right = a.splitCandidate(func(*models.Mailbox) bool { return true })
+	}
+	if right == nil {
+		return a.showToast("No other folder to show beside this one", toastInfo, 3*time.Second)
+	}
+
+	a.split = &splitPane{
+		mailbox: *right,
+		list:    views.NewThreadListView(a.width/2, a.height-7, a.cfg.MaxContentWidth),
+	}
+	a.split.list.SetColumns(a.columnLayout())
+	return a.loadSplit(false)
+}
+
+// splitCandidate returns the first folder other than the open one that
+// can go in the right pane and matches
+func (a *App) splitCandidate(match func(*models.Mailbox) bool) *models.Mailbox {
+	for i := range a.mailboxes {
+		mb := &a.mailboxes[i]
+		if i != a.selectedMailbox && !mb.IsVirtual() && match(mb) {
+			return mb
+		}
+	}
+	return nil
+}
+
+// loadSplit loads the right pane's folder, from the cache unless fresh
+func (a *App) loadSplit(fresh bool) tea.Cmd {
+	mailboxID := a.split.mailbox.ID
+	return func() tea.Msg {
+		if a.syncer != nil && !fresh {
+			emails, err := a.syncer.GetCachedEmails(mailboxID, a.cfg.PageSize)
+			if err == nil && len(emails) > 0 {
+				return splitLoadedMsg{mailboxID: mailboxID, emails: emails}
+			}
+		}
+		emails, err := a.client.GetEmails(mailboxID, a.cfg.PageSize)
+		if err == nil && a.store != nil && len(emails) > 0 {
+			a.store.SaveEmails(a.client.AccountID(), emails)
+		}
+		return splitLoadedMsg{mailboxID: mailboxID, emails: emails, err: err}
+	}
+}
+
+// handleSplitLoaded fills the right pane, unless its folder was changed
+// while loading
+func (a *App) handleSplitLoaded(msg splitLoadedMsg) (tea.Model, tea.Cmd) {
+	if a.split == nil || a.split.mailbox.ID != msg.mailboxID {
+		return a, nil
+	}
+	if msg.err != nil {
+		return a, a.showToast("Couldn't load "+a.split.mailbox.DisplayName()+": "+msg.err.Error(), toastError, 4*time.Second)
+	}
+	a.split.threads = a.groupEmailsIntoThreads(msg.emails)
+	a.split.selected = min(a.split.selected, max(len(a.split.threads)-1, 0))
+	a.split.list.Select(a.split.selected)
+	return a, nil
+}
+
+// handleSplitKeys moves through the focused pane; tab switches panes, m
+// moves the selected conversation to the other folder and [ or ] picks
+// the folder on the right
+func (a *App) handleSplitKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := a.split
+	switch msg.String() {
+	case "up", "k":
+		if s.right && s.selected > 0 {
+			s.selected--
+			s.list.Select(s.selected)
+		} else if !s.right && a.selectedThread > 0 {
+			a.selectedThread--
+			if a.threadList != nil {
+				a.threadList.Select(a.selectedThread)
+			}
+		}
+	case "down", "j":
+		if s.right && s.selected < len(s.threads)-1 {
+			s.selected++
+			s.list.Select(s.selected)
+		} else if !s.right && a.selectedThread < len(a.threads)-1 {
+			a.selectedThread++
+			if a.threadList != nil {
+				a.threadList.Select(a.selectedThread)
+			}
+		}
+	case "tab", "shift+tab":
+		s.right = !s.right
+	case "m":
+		return a, a.moveAcrossSplit()
+	case "[", "]":
+		return a, a.cycleSplitFolder(msg.String() == "]")
+	case "esc", "q", "V":
+		return a, a.closeSplit()
+	}
+	return a, nil
+}
+
+// closeSplit goes back to the message list, saving the folder pairing if
+// it was changed while the split was open
+func (a *App) closeSplit() tea.Cmd {
+	repaired := a.split.repaired
+	a.split = nil
+	if !repaired {
+		return nil
+	}
+	if err := a.cfg.Save(); err != nil {
+		return a.showToast("Couldn't remember the folder pair: "+err.Error(), toastError, 4*time.Second)
+	}
+	return nil
+}
+
+// cycleSplitFolder shows the next or previous folder on the right and
+// remembers the pairing for the folder on the left, to save on close
+func (a *App) cycleSplitFolder(forward bool) tea.Cmd {
+	var candidates []*models.Mailbox
+	current := 0
+	for i := range a.mailboxes {
+		mb := &a.mailboxes[i]
+		if i == a.selectedMailbox || mb.IsVirtual() {
+			continue
+		}
+		if mb.ID == a.split.mailbox.ID {
+			current = len(candidates)
+		}
+		candidates = append(candidates, mb)
+	}
+	if len(candidates) < 2 {
+		return nil
+	}
+	step := len(candidates) - 1
+	if forward {
+		step = 1
+	}
+	a.split.mailbox = *candidates[(current+step)%len(candidates)]
+	a.split.threads = nil
+	a.split.selected = 0
+
+	if account := a.cfg.FindAccount(a.client.Email()); account != nil {
+		if account.SplitFolders == nil {
+			account.SplitFolders = make(map[string]string)
+		}
+		account.SplitFolders[a.currentMailbox().Name] = a.split.mailbox.Name
+		a.split.repaired = true
+	}
+	return a.loadSplit(false)
+}
+
+// moveAcrossSplit moves the selected conversation in the focused pane to
+// the folder in the other one, in a single request
+func (a *App) moveAcrossSplit() tea.Cmd {
+	s := a.split
+	from, to := a.currentMailbox(), &s.mailbox
+	threads, selected := a.threads, a.selectedThread
+	if s.right {
+		from, to = to, from
+		threads, selected = s.threads, s.selected
+	}
+	if from == nil || to == nil || selected >= len(threads) {
+		return nil
+	}
+	if !from.CanRemove() {
+		cmd, _ := a.denied("can't move messages out of " + from.DisplayName())
+		return cmd
+	}
+	if !to.CanAdd() {
+		cmd, _ := a.denied("can't move messages to " + to.DisplayName())
+		return cmd
+	}
+
+	ids := make([]string, 0, len(threads[selected].Emails))
+	for _, e := range threads[selected].Emails {
+		ids = append(ids, e.ID)
+	}
+	targetID, folder := to.ID, to.DisplayName()
+	return func() tea.Msg {
+		return splitMovedMsg{count: len(ids), folder: folder, err: a.client.MoveEmails(ids, targetID)}
+	}
+}
+
+// handleSplitMoved reloads both panes after a move
+func (a *App) handleSplitMoved(msg splitMovedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return a, a.showToast("Move failed ✗: "+msg.err.Error(), toastError, 5*time.Second)
+	}
+	text := fmt.Sprintf("Moved %d messages to %s", msg.count, msg.folder)
+	if msg.count == 1 {
+		text = "Moved to " + msg.folder
+	}
+	cmds := []tea.Cmd{a.showToast(text, toastSuccess, 3*time.Second)}
+	if mb := a.currentMailbox(); mb != nil {
+		cmds = append(cmds, a.loadEmailsFresh(mb.ID))
+	}
+	if a.split != nil {
+		cmds = append(cmds, a.loadSplit(true))
+	}
+	return a, tea.Batch(cmds...)
+}
+
+// renderSplit renders the message list and the paired folder side by side
+func (a *App) renderSplit(width int) string {
+	leftWidth := (width - 1) / 2
+	rightWidth := width - 1 - leftWidth
+	height := max(a.height-7, 1)
+
+	left := a.splitHeader(a.currentMailbox(), !a.split.right, leftWidth)
+	if a.threadList != nil {
+		a.threadList.UpdateThreads(a.convertToViewThreads())
+		a.threadList.SetHighlight("")
+		a.threadList.SetShowSize(false)
+		a.threadList.SetSize(leftWidth, height)
+		left += "\n" + a.threadList.View()
+	}
+
+	a.split.list.UpdateThreads(a.toViewThreads(a.split.threads))
+	a.split.list.SetSize(rightWidth, height)
+	right := a.splitHeader(&a.split.mailbox, a.split.right, rightWidth) + "\n" + a.split.list.View()
+
+	divider := lipgloss.NewStyle().Foreground(ColorDim).
+		Render(strings.TrimSuffix(strings.Repeat("│\n", a.height-6), "\n"))
+
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().Width(leftWidth).MaxHeight(a.height-6).Render(left),
+		divider,
+		lipgloss.NewStyle().Width(rightWidth).MaxHeight(a.height-6).Render(right),
+	)
+}
+
+// splitHeader names a pane's folder, highlighted when the pane has focus
+func (a *App) splitHeader(mb *models.Mailbox, focused bool, width int) string {
+	name := "?"
+	if mb != nil {
+		name = mb.DisplayName()
+	}
+	style := lipgloss.NewStyle().Foreground(ColorDim)
+	if focused {
+		style = lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true)
+		name = "◆ " + name + "  (tab: switch  m: move across  [/]: folder  esc: close)"
+	} else {
+		name = "◇ " + name
+	}
+	return style.MaxWidth(width).Render(" " + name)
+}
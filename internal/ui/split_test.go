@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"os"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/jmap"
+	"github.com/the9x/anneal/internal/models"
+	"github.com/the9x/anneal/internal/ui/views"
+)
+
+func TestSplitPairSavedOnClose(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.Accounts = []models.Account{{Email: ""}}
+	a := &App{cfg: cfg, client: &jmap.Client{}, width: 80, height: 24}
+	a.mailboxes = []models.Mailbox{
+		{ID: "inbox", Name: "Inbox", Role: "inbox"},
+		{ID: "archive", Name: "Archive", Role: "archive"},
+		{ID: "lists", Name: "Lists"},
+		{ID: "receipts", Name: "Receipts"},
+	}
+	a.split = &splitPane{mailbox: a.mailboxes[1], list: views.NewThreadListView(40, 17, 0)}
+
+	path, err := config.ConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		a.handleSplitKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("config saved while picking the folder on the right")
+	}
+	if got := cfg.Accounts[0].SplitFolders["Inbox"]; got != "Archive" {
+		t.Fatalf("paired with %q, want Archive after a full cycle", got)
+	}
+
+	a.handleSplitKeys(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.split != nil {
+		t.Fatal("esc left the split open")
+	}
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("pairing not saved on close: %v", err)
+	}
+	if len(saved) == 0 {
+		t.Error("empty config saved")
+	}
+}
@@ -1,19 +1,26 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/the9x/anneal/internal/ui/theme"
+)
 
-// anneal color palette — the9x.ac brand
+// anneal color palette, see the theme package for the ANSI fallbacks
 var (
 	// Core colors
-	ColorBg        = lipgloss.Color("#1d1d40") // background
-	ColorPrimary   = lipgloss.Color("#d4d2e3") // primary text
-	ColorSecondary = lipgloss.Color("#9795b5") // secondary text
-	ColorAccent    = lipgloss.Color("#e61e25") // accent (used sparingly)
+	ColorBg        = theme.Bg        // background
+	ColorPrimary   = theme.Primary   // primary text
+	ColorSecondary = theme.Secondary // secondary text
+	ColorAccent    = theme.Accent    // accent (used sparingly)
 
 	// Derived shades
-	ColorBgLight  = lipgloss.Color("#252550") // slightly lighter bg
-	ColorBgSelect = lipgloss.Color("#2d2d5a") // selection bg
-	ColorDim      = lipgloss.Color("#5a5880") // dim text
+	ColorBgLight  = theme.BgLight  // slightly lighter bg
+	ColorBgSelect = theme.BgSelect // selection bg
+	ColorDim      = theme.Dim      // dim text
+
+	// Connection indicator
+	ColorOnline  = theme.Online  // last request succeeded
+	ColorPending = theme.Pending // request in flight
 )
 
 // Minimal borders
@@ -0,0 +1,15 @@
+package ui
+
+import "testing"
+
+func TestSyncProgressTicksWhileSyncing(t *testing.T) {
+	a := &App{syncing: true}
+	if _, cmd := a.Update(syncProgressMsg{}); cmd == nil {
+		t.Error("progress stopped redrawing during a sync")
+	}
+
+	a.Update(syncCompleteMsg{})
+	if _, cmd := a.Update(syncProgressMsg{}); cmd != nil {
+		t.Error("progress kept redrawing after the sync")
+	}
+}
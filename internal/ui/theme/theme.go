@@ -0,0 +1,58 @@
+// Package theme holds the anneal color palette shared by the UI and its
+// views. Every color carries a truecolor brand value and a high-contrast
+// ANSI-16 fallback, so the palette degrades on terminals without
+// truecolor instead of being approximated.
+package theme
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Theme names accepted by Setup
+const (
+	Dark         = "dark"
+	HighContrast = "high-contrast"
+)
+
+// color pairs a truecolor value with the ANSI-16 color used in its place
+func color(trueColor, ansi string) lipgloss.CompleteColor {
+	return lipgloss.CompleteColor{TrueColor: trueColor, ANSI256: ansi, ANSI: ansi}
+}
+
+// anneal color palette — the9x.ac brand
+var (
+	// Core colors
+	Bg        = color("#1d1d40", "0")  // background
+	Primary   = color("#d4d2e3", "15") // primary text
+	Secondary = color("#9795b5", "7")  // secondary text
+	Accent    = color("#e61e25", "9")  // accent (used sparingly)
+
+	// Derived shades
+	BgLight  = color("#252550", "5") // slightly lighter bg
+	BgSelect = color("#2d2d5a", "4") // selection bg
+	Dim      = color("#5a5880", "6") // dim text
+
+	// Connection indicator
+	Online  = color("#5fb878", "10") // last request succeeded
+	Pending = color("#e0b341", "11") // request in flight
+)
+
+// Setup picks the color profile before the UI starts. NO_COLOR or
+// noColor disable styling entirely; the high-contrast theme, or a terminal
+// without truecolor, use the ANSI-16 palette.
+func Setup(name string, noColor bool) {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		return
+	}
+
+	switch profile := lipgloss.ColorProfile(); {
+	case profile == termenv.Ascii:
+		// Not a color terminal; leave styling off
+	case name == HighContrast || profile != termenv.TrueColor:
+		lipgloss.SetColorProfile(termenv.ANSI)
+	}
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// toggleThreadDebug opens or closes the grouping overlay for the selected
+// thread
+func (a *App) toggleThreadDebug() {
+	if a.debugThreadID != "" {
+		a.debugThreadID = ""
+		return
+	}
+	if a.selectedThread < len(a.threads) {
+		a.debugThreadID = a.threads[a.selectedThread].ID
+	}
+}
+
+// renderThreadDebug shows how a thread was grouped: its ID, whether that
+// ID came from the server or the email ID fallback, and every member
+func (a *App) renderThreadDebug(width int) string {
+	t := a.findThread(a.debugThreadID)
+	if t == nil {
+		return a.renderEmptyMain(width, "Thread no longer loaded")
+	}
+
+	label := lipgloss.NewStyle().Foreground(ColorDim)
+	value := lipgloss.NewStyle().Foreground(ColorPrimary)
+	warn := lipgloss.NewStyle().Foreground(ColorAccent)
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render("◇ thread grouping"))
+	b.WriteString("\n\n")
+
+	b.WriteString(label.Render("thread id  ") + value.Render(t.ID) + "\n")
+	if len(t.Emails) == 1 && t.Emails[0].ThreadID == "" {
+		b.WriteString(label.Render("source     ") + warn.Render("fallback: email has no threadId") + "\n")
+	} else {
+		b.WriteString(label.Render("source     ") + value.Render("server threadId") + "\n")
+	}
+
+	server := "not checked"
+	if t.ServerCount > 0 {
+		server = fmt.Sprintf("%d", t.ServerCount)
+	}
+	b.WriteString(label.Render("loaded     ") + value.Render(fmt.Sprintf("%d", len(t.Emails))) +
+		label.Render("  on server ") + value.Render(server) + "\n\n")
+
+	b.WriteString(label.Render("members") + "\n")
+	for _, email := range t.Emails {
+		line := fmt.Sprintf("  %s  %s  %s", email.ID, email.ReceivedAt.Format("2006-01-02 15:04"), email.FromDisplay())
+		b.WriteString(value.MaxWidth(width-4).Render(line) + "\n")
+
+		// Members grouped under a different ID point at a grouping bug
+		if email.ThreadID != "" && email.ThreadID != t.ID {
+			b.WriteString(warn.Render("    threadId "+email.ThreadID) + "\n")
+		}
+		b.WriteString(label.MaxWidth(width-4).Render("    "+email.Subject) + "\n")
+	}
+
+	b.WriteString("\n" + label.Render("press any key to close"))
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Padding(1, 2).
+		Render(b.String())
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// toastKind picks the colour of a toast
+type toastKind int
+
+const (
+	toastInfo toastKind = iota
+	toastSuccess
+	toastError
+)
+
+// toast is a short status message shown over the status bar
+type toast struct {
+	id   int
+	text string
+	kind toastKind
+	undo bool // "u" undoes the last archive while this toast is shown
+}
+
+// archiveUndoWindow is how long "u" can undo an archive
+const archiveUndoWindow = 5 * time.Second
+
+// toastExpiredMsg dismisses the toast with the given id, unless a newer
+// one has replaced it
+type toastExpiredMsg struct {
+	id int
+}
+
+// showToast displays a toast, dismissing it after ttl. A ttl of 0 keeps it
+// until another toast replaces it.
+func (a *App) showToast(text string, kind toastKind, ttl time.Duration) tea.Cmd {
+	a.toastSeq++
+	a.toast = &toast{id: a.toastSeq, text: text, kind: kind}
+	if ttl <= 0 {
+		return nil
+	}
+	id := a.toastSeq
+	return tea.Tick(ttl, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: id}
+	})
+}
+
+// renderToast renders the current toast for the status bar
+func (a *App) renderToast() string {
+	color := ColorPrimary
+	switch a.toast.kind {
+	case toastSuccess:
+		color = ColorOnline
+	case toastError:
+		color = ColorAccent
+	}
+	return lipgloss.NewStyle().Foreground(color).Bold(true).Render(a.toast.text)
+}
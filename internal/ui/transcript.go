@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/the9x/anneal/internal/models"
+	"github.com/the9x/anneal/internal/ui/views"
+)
+
+// transcript is a conversation shown as one document to scroll through,
+// in place of the thread view
+type transcript struct {
+	threadID string
+	doc      string // from renderThreadTranscript, wrapped when shown
+	scroll   int
+}
+
+// transcriptLoadedMsg carries a conversation with every body filled in
+type transcriptLoadedMsg struct {
+	thread Thread
+	err    error
+}
+
+// openTranscript loads the bodies the selected conversation is missing,
+// then shows it as a transcript
+func (a *App) openTranscript() tea.Cmd {
+	if a.selectedThread >= len(a.threads) {
+		return nil
+	}
+	thread := a.threads[a.selectedThread]
+	thread.Emails = append([]models.Email(nil), thread.Emails...)
+
+	a.loading = true
+	return func() tea.Msg {
+		return transcriptLoadedMsg{thread: thread, err: a.loadThreadBodies(thread.Emails)}
+	}
+}
+
+// handleTranscriptLoaded shows the transcript, unless the conversation was
+// left while its bodies loaded
+func (a *App) handleTranscriptLoaded(msg transcriptLoadedMsg) (tea.Model, tea.Cmd) {
+	a.loading = false
+	if msg.err != nil {
+		return a, a.showToast("Couldn't load the conversation: "+msg.err.Error(), toastError, 4*time.Second)
+	}
+	if a.viewState != ViewThread || a.selectedThread >= len(a.threads) || a.threads[a.selectedThread].ID != msg.thread.ID {
+		return a, nil
+	}
+	a.transcript = &transcript{
+		threadID: msg.thread.ID,
+		doc:      a.renderThreadTranscript(msg.thread),
+	}
+	return a, nil
+}
+
+// showingTranscript returns true while the open conversation is shown as
+// a transcript
+func (a *App) showingTranscript() bool {
+	return a.transcript != nil && a.viewState == ViewThread &&
+		a.selectedThread < len(a.threads) && a.threads[a.selectedThread].ID == a.transcript.threadID
+}
+
+// handleTranscriptKeys scrolls the transcript; esc goes back to the
+// thread view
+func (a *App) handleTranscriptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	page := max((a.height-8)/2, 1)
+	switch msg.String() {
+	case "up", "k":
+		a.transcript.scroll = max(a.transcript.scroll-1, 0)
+	case "down", "j":
+		a.transcript.scroll++
+	case "pgup", "ctrl+u":
+		a.transcript.scroll = max(a.transcript.scroll-page, 0)
+	case "pgdown", "ctrl+d", " ":
+		a.transcript.scroll += page
+	case "g", "home":
+		a.transcript.scroll = 0
+	case "G", "end":
+		// Clamped to the last page when rendered
+		a.transcript.scroll = 1 << 30
+	case "esc", "q", "left", "h", "T":
+		a.transcript = nil
+	}
+	return a, nil
+}
+
+// renderThreadTranscript renders a conversation as one flowing document,
+// oldest message first, each under a one-line header. Quoted text already
+// shown earlier in the transcript is collapsed, so a reply quoting the
+// whole conversation doesn't repeat it.
+func (a *App) renderThreadTranscript(thread Thread) string {
+	emails := append([]models.Email(nil), thread.Emails...)
+	sort.SliceStable(emails, func(i, j int) bool {
+		return emails[i].ReceivedAt.Before(emails[j].ReceivedAt)
+	})
+
+	subject := thread.Subject
+	if subject == "" {
+		subject = "(no subject)"
+	}
+	title := lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true)
+	from := lipgloss.NewStyle().Foreground(ColorPrimary)
+	dim := lipgloss.NewStyle().Foreground(ColorDim)
+	body := lipgloss.NewStyle().Foreground(ColorSecondary)
+
+	var b strings.Builder
+	b.WriteString(title.Render("◈ "+subject) + dim.Render(fmt.Sprintf("  %d messages", len(emails))))
+	b.WriteString("\n")
+
+	var seen strings.Builder
+	for _, email := range emails {
+		text := email.TextBody
+		if text == "" && email.HTMLBody != "" {
+			text = views.HTMLToText(email.HTMLBody)
+		}
+		text = strings.TrimSpace(collapseSeenQuotes(text, seen.String()))
+		seen.WriteString(quoteKey(text) + " ")
+
+		sender := "unknown"
+		if len(email.From) > 0 {
+			sender = email.From[0].Name
+			if sender == "" {
+				sender = email.From[0].Email
+			}
+		}
+		b.WriteString("\n" + from.Render("▸ "+sender) + dim.Render("  "+email.ReceivedAt.Local().Format("Mon Jan 2, 15:04")))
+		b.WriteString("\n\n")
+		for _, line := range strings.Split(text, "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), ">") || line == quoteHiddenMarker {
+				b.WriteString(dim.Render(line) + "\n")
+			} else {
+				b.WriteString(body.Render(line) + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// quoteHiddenMarker stands in for quoted text collapsed in a transcript
+const quoteHiddenMarker = "[quoted text hidden]"
+
+// collapseSeenQuotes replaces each run of quoted lines that mostly repeats
+// text in seen, along with the attribution line above it, with a marker.
+// Quotes of anything not already shown are kept.
+func collapseSeenQuotes(body, seen string) string {
+	lines := strings.Split(body, "\n")
+	var out []string
+	for i := 0; i < len(lines); {
+		if !strings.HasPrefix(strings.TrimSpace(lines[i]), ">") {
+			out = append(out, lines[i])
+			i++
+			continue
+		}
+		end := i
+		for end < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[end]), ">") {
+			end++
+		}
+		if !quoteSeen(lines[i:end], seen) {
+			out = append(out, lines[i:end]...)
+			i = end
+			continue
+		}
+		// Drop the "On ... wrote:" line introducing the quote
+		if n := len(out); n > 0 && attributionRe.MatchString(strings.TrimSpace(out[n-1])) {
+			out = out[:n-1]
+		}
+		out = append(out, quoteHiddenMarker)
+		i = end
+	}
+	return strings.Join(out, "\n")
+}
+
+// quoteSeen returns true if at least half the quoted lines with any text
+// appear in seen. Lines are compared by their words, so a quote wrapped
+// differently from the original still matches.
+func quoteSeen(quoted []string, seen string) bool {
+	total, found := 0, 0
+	for _, line := range quoted {
+		key := quoteKey(line)
+		if key == "" {
+			continue
+		}
+		total++
+		if strings.Contains(seen, key) {
+			found++
+		}
+	}
+	return total > 0 && found*2 >= total
+}
+
+// quoteKey reduces text to its lowercased words, without quote markers,
+// for comparing quoted text with the original
+func quoteKey(text string) string {
+	var words []string
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		if word = strings.TrimLeft(word, ">"); word != "" {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// renderTranscript renders the transcript in place of the thread view,
+// wrapped to the width and scrolled
+func (a *App) renderTranscript(width int) string {
+	wrap := width - 4
+	if a.cfg.MaxContentWidth > 0 {
+		wrap = min(wrap, a.cfg.MaxContentWidth)
+	}
+	lines := strings.Split(strings.TrimRight(lipgloss.NewStyle().Width(max(wrap, 20)).Render(a.transcript.doc), "\n"), "\n")
+
+	rows := max(a.height-9, 3)
+	maxScroll := max(len(lines)-rows, 0)
+	a.transcript.scroll = min(a.transcript.scroll, maxScroll)
+	end := min(a.transcript.scroll+rows, len(lines))
+
+	label := lipgloss.NewStyle().Foreground(ColorDim)
+	footer := "↑/↓: scroll  space: page  g/G: top/bottom  esc: close"
+	if maxScroll > 0 {
+		footer = fmt.Sprintf("▾ %d%%  ", a.transcript.scroll*100/maxScroll) + footer
+	}
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Padding(1, 2).
+		Render(strings.Join(lines[a.transcript.scroll:end], "\n") + "\n\n" + label.Render(footer))
+}
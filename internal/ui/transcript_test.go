@@ -0,0 +1,72 @@
+package ui
+
+import "testing"
+
+func TestCollapseSeenQuotes(t *testing.T) {
+	seen := quoteKey("Can we move the meeting to Thursday? The room is booked on Wednesday afternoon.") + " "
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "whole conversation quoted",
+			body: "Thursday works.\n\nOn Mon, Jan 5, 2026 at 10:00, Ann wrote:\n> Can we move the meeting to Thursday? The room\n> is booked on Wednesday afternoon.",
+			want: "Thursday works.\n\n" + quoteHiddenMarker,
+		},
+		{
+			name: "quote of unseen text",
+			body: "Agreed.\n\nOn Mon, Jan 5, 2026 at 10:00, Bob wrote:\n> Let's order lunch from the usual place.",
+			want: "Agreed.\n\nOn Mon, Jan 5, 2026 at 10:00, Bob wrote:\n> Let's order lunch from the usual place.",
+		},
+		{
+			name: "rewrapped quote",
+			body: "Fine by me.\n> Can we move the\n> meeting to Thursday? The room is\n> booked on Wednesday\n> afternoon.",
+			want: "Fine by me.\n" + quoteHiddenMarker,
+		},
+	}
+	for _, tt := range tests {
+		if got := collapseSeenQuotes(tt.body, seen); got != tt.want {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteSeen(t *testing.T) {
+	seen := quoteKey("Can we move the meeting to Thursday? The room is booked on Wednesday afternoon.")
+
+	tests := []struct {
+		name   string
+		quoted []string
+		want   bool
+	}{
+		{"same lines", []string{"> Can we move the meeting to Thursday?"}, true},
+		{"rewrapped", []string{"> Can we move", "> the meeting to Thursday? The", ">> room is booked"}, true},
+		{"unseen", []string{"> Lunch is on me.", "> See you there."}, false},
+		{"half seen", []string{"> the room is booked", "> on a different day"}, true},
+		{"mostly unseen", []string{"> the room is booked", "> on a different day", "> by someone else"}, false},
+		{"empty quote", []string{">", "> "}, false},
+	}
+	for _, tt := range tests {
+		if got := quoteSeen(tt.quoted, seen); got != tt.want {
+			t.Errorf("%s: quoteSeen = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteKey(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"> Hello  World", "hello world"},
+		{">> nested > quote", "nested quote"},
+		{"  plain\ttext\n", "plain text"},
+		{">", ""},
+	}
+	for _, tt := range tests {
+		if got := quoteKey(tt.text); got != tt.want {
+			t.Errorf("quoteKey(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package views
+
+import "github.com/charmbracelet/lipgloss"
+
+// ColumnLayout sets how the message list shares its width between the
+// sender and subject, and which optional columns it shows
+type ColumnLayout struct {
+	FromPercent int  // share of the flexible width given to the sender
+	MinFrom     int  // narrowest the sender column gets
+	MaxFrom     int  // widest the sender column gets
+	Size        bool // show a size column before the date
+	Flags       bool // show the priority, follow-up and attachment markers
+}
+
+// DefaultColumnLayout is a quarter of the width for the sender, within
+// 12 to 24 columns, with flags and without sizes
+func DefaultColumnLayout() ColumnLayout {
+	return ColumnLayout{
+		FromPercent: 25,
+		MinFrom:     minFromWidth,
+		MaxFrom:     maxFromWidth,
+		Flags:       true,
+	}
+}
+
+// valid returns the layout with any setting that doesn't make sense
+// replaced by its default
+func (l ColumnLayout) valid() ColumnLayout {
+	def := DefaultColumnLayout()
+	if l.FromPercent < 10 || l.FromPercent > 90 {
+		l.FromPercent = def.FromPercent
+	}
+	if l.MinFrom < 4 {
+		l.MinFrom = def.MinFrom
+	}
+	if l.MaxFrom < l.MinFrom {
+		l.MaxFrom = max(def.MaxFrom, l.MinFrom)
+	}
+	return l
+}
+
+// sizeWidth is the width of the optional size column, as "999.9 KB"
+const sizeWidth = 8
+
+// SetColumns sets the column layout, falling back to the default for
+// settings out of range
+func (v *ThreadListView) SetColumns(layout ColumnLayout) {
+	v.columns = layout.valid()
+}
+
+// dateColumnWidth returns the width of the date column: wide enough for
+// the longest date shown, and no narrower than the default
+func (v *ThreadListView) dateColumnWidth() int {
+	width := dateWidth
+	for _, t := range v.threads {
+		width = max(width, lipgloss.Width(t.Date))
+	}
+	return width
+}
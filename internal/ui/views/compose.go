@@ -2,13 +2,17 @@ package views
 
 import (
 	"fmt"
+	"net/mail"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/the9x/anneal/internal/i18n"
 	"github.com/the9x/anneal/internal/models"
+	"github.com/the9x/anneal/internal/ui/theme"
 )
 
 // ComposeMode indicates the type of composition
@@ -23,11 +27,12 @@ const (
 
 // anneal brand colors for compose
 var (
-	composeColorPrimary   = lipgloss.Color("#d4d2e3")
-	composeColorSecondary = lipgloss.Color("#9795b5")
-	composeColorDim       = lipgloss.Color("#5a5880")
-	composeColorBg        = lipgloss.Color("#1d1d40")
-	composeColorBgSelect  = lipgloss.Color("#2d2d5a")
+	composeColorPrimary   = theme.Primary
+	composeColorSecondary = theme.Secondary
+	composeColorDim       = theme.Dim
+	composeColorBg        = theme.Bg
+	composeColorBgSelect  = theme.BgSelect
+	composeColorAccent    = theme.Accent
 
 	composeLabelStyle = lipgloss.NewStyle().
 				Foreground(composeColorDim).
@@ -61,6 +66,11 @@ type Identity struct {
 	ID    string
 	Name  string
 	Email string
+
+	// Signature and ReplyTo are the ones configured for this identity,
+	// falling back to the account's
+	Signature string
+	ReplyTo   string
 }
 
 // ComposeField indicates which field is focused
@@ -68,9 +78,11 @@ type ComposeField int
 
 const (
 	FieldFrom ComposeField = iota
+	FieldName
 	FieldTo
 	FieldCc
 	FieldSubject
+	FieldSendAt
 	FieldBody
 )
 
@@ -82,18 +94,52 @@ type ComposeView struct {
 	identities       []Identity
 	selectedIdentity int
 
+	name    textinput.Model // optional From display name override
 	to      textinput.Model
 	cc      textinput.Model
 	subject textinput.Model
+	sendAt  textinput.Model // optional scheduled send time
 	body    textarea.Model
 
+	// scheduling shows the send-at field when the server can hold messages
+	scheduling bool
+
+	// bottomPost puts replies below the quote, with the signature last
+	bottomPost bool
+
+	// signature is the one added to the body, swapped out when the From
+	// identity changes
+	signature string
+
+	// Long Cc lists are edited one address per line in ccArea while the
+	// field has focus, and summarized otherwise
+	ccArea     textarea.Model
+	ccExpanded bool
+
+	// draftID is the autosaved copy in Drafts, replaced on each save
+	draftID string
+
+	// attachments are the paths of the files to send with the message
+	attachments []string
+	// attachmentSizes are the sizes of the attached files when attached,
+	// by path, or missing if the file couldn't be read
+	attachmentSizes map[string]int64
+
 	focused ComposeField
+	err     string // validation error shown above the help line
 	width   int
 	height  int
 }
 
 // NewComposeView creates a new compose view
 func NewComposeView(width, height int, identities []Identity) *ComposeView {
+	// From name override field
+	name := textinput.New()
+	name.CharLimit = 100
+	name.Width = width - 14
+	name.PromptStyle = lipgloss.NewStyle().Foreground(composeColorDim)
+	name.TextStyle = lipgloss.NewStyle().Foreground(composeColorPrimary)
+
 	// To field
 	to := textinput.New()
 	to.Placeholder = "recipient@example.com"
@@ -106,7 +152,7 @@ func NewComposeView(width, height int, identities []Identity) *ComposeView {
 	// Cc field
 	cc := textinput.New()
 	cc.Placeholder = ""
-	cc.CharLimit = 500
+	cc.CharLimit = 0 // reply-all can bring in dozens of addresses
 	cc.Width = width - 14
 	cc.PromptStyle = lipgloss.NewStyle().Foreground(composeColorDim)
 	cc.TextStyle = lipgloss.NewStyle().Foreground(composeColorPrimary)
@@ -119,46 +165,161 @@ func NewComposeView(width, height int, identities []Identity) *ComposeView {
 	subject.PromptStyle = lipgloss.NewStyle().Foreground(composeColorDim)
 	subject.TextStyle = lipgloss.NewStyle().Foreground(composeColorPrimary)
 
+	// Send-at field, shown only when scheduling is enabled
+	sendAt := textinput.New()
+	sendAt.Placeholder = "now (or 17:30, tomorrow 9:00, 2h, 2006-01-02 15:04)"
+	sendAt.CharLimit = 40
+	sendAt.Width = width - 14
+	sendAt.PromptStyle = lipgloss.NewStyle().Foreground(composeColorDim)
+	sendAt.TextStyle = lipgloss.NewStyle().Foreground(composeColorPrimary)
+
 	// Body textarea
 	body := textarea.New()
 	body.Placeholder = "compose your message..."
 	body.CharLimit = 0 // No limit
 	body.SetWidth(width - 4)
-	body.SetHeight(height - 12)
+	body.SetHeight(height - 13) // SetScheduling makes room for send-at
 	body.FocusedStyle.Base = lipgloss.NewStyle().Foreground(composeColorPrimary)
 	body.BlurredStyle.Base = lipgloss.NewStyle().Foreground(composeColorSecondary)
 	body.FocusedStyle.CursorLine = lipgloss.NewStyle().Background(composeColorBgSelect)
 	body.ShowLineNumbers = false
 
+	// Cc editor for long lists, one address per line
+	ccArea := textarea.New()
+	ccArea.CharLimit = 0
+	ccArea.SetWidth(width - 4)
+	ccArea.Prompt = "  "
+	ccArea.ShowLineNumbers = false
+	ccArea.FocusedStyle.Base = lipgloss.NewStyle().Foreground(composeColorPrimary)
+	ccArea.FocusedStyle.CursorLine = lipgloss.NewStyle().Background(composeColorBgSelect)
+
 	// Start on From field if multiple identities, otherwise To field
 	startField := FieldTo
 	if len(identities) > 1 {
 		startField = FieldFrom
 	}
 
-	return &ComposeView{
+	v := &ComposeView{
 		Mode:             ModeCompose,
 		identities:       identities,
 		selectedIdentity: 0,
+		name:             name,
 		to:               to,
 		cc:               cc,
+		ccArea:           ccArea,
 		subject:          subject,
+		sendAt:           sendAt,
 		body:             body,
 		focused:          startField,
 		width:            width,
 		height:           height,
 	}
+	v.updateNamePlaceholder()
+	return v
+}
+
+// updateNamePlaceholder shows the selected identity's name as the default
+func (v *ComposeView) updateNamePlaceholder() {
+	v.name.Placeholder = "(identity name)"
+	if id := v.GetIdentity(); id != nil && id.Name != "" {
+		v.name.Placeholder = id.Name
+	}
 }
 
 // SetSize updates the view dimensions
 func (v *ComposeView) SetSize(width, height int) {
 	v.width = width
 	v.height = height
-	v.to.Width = width - 14
-	v.cc.Width = width - 14
-	v.subject.Width = width - 14
-	v.body.SetWidth(width - 4)
-	v.body.SetHeight(height - 12)
+	field := max(width-14, 1)
+	v.name.Width = field
+	v.to.Width = field
+	v.cc.Width = field
+	v.ccArea.SetWidth(max(width-4, 1))
+	v.subject.Width = field
+	v.sendAt.Width = field
+	v.body.SetWidth(max(width-4, 1))
+	v.body.SetHeight(v.bodyHeight())
+}
+
+// SetScheduling shows or hides the send-at field
+func (v *ComposeView) SetScheduling(enabled bool) {
+	v.scheduling = enabled
+	v.body.SetHeight(v.bodyHeight())
+}
+
+// SetBottomPosting writes replies below the quoted message rather than
+// above it. Call before SetReply.
+func (v *ComposeView) SetBottomPosting(enabled bool) {
+	v.bottomPost = enabled
+}
+
+// bodyHeight returns the textarea height left after the header fields
+func (v *ComposeView) bodyHeight() int {
+	h := v.height - 13
+	if v.scheduling {
+		h--
+	}
+	if v.ccExpanded {
+		h -= v.ccArea.Height()
+	}
+	if len(v.attachments) > 0 {
+		h--
+	}
+	return max(h, 1)
+}
+
+// ccSummaryAt is the most Cc recipients shown inline; longer lists are
+// summarized and open in a one-per-line editor when focused
+const ccSummaryAt = 5
+
+// ccAddresses returns the entries of the Cc list
+func (v *ComposeView) ccAddresses() []string {
+	return splitAddresses(v.cc.Value())
+}
+
+// splitAddresses splits a comma or newline separated list, dropping
+// empty entries
+func splitAddresses(s string) []string {
+	var addrs []string
+	for _, entry := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			addrs = append(addrs, entry)
+		}
+	}
+	return addrs
+}
+
+// expandCc opens the one-per-line editor for a long Cc list
+func (v *ComposeView) expandCc() {
+	addrs := v.ccAddresses()
+	v.ccArea.SetValue(strings.Join(addrs, "\n"))
+	v.ccArea.SetHeight(min(len(addrs), 8))
+	v.ccArea.Focus()
+	v.ccExpanded = true
+	v.body.SetHeight(v.bodyHeight())
+}
+
+// ccValue returns the Cc list, including edits in the expanded editor
+func (v *ComposeView) ccValue() string {
+	if v.ccExpanded {
+		return strings.Join(splitAddresses(v.ccArea.Value()), ", ")
+	}
+	return v.cc.Value()
+}
+
+// collapseCc folds the editor's lines back into the comma list
+func (v *ComposeView) collapseCc() {
+	v.cc.SetValue(v.ccValue())
+	v.ccArea.Blur()
+	v.ccExpanded = false
+	v.body.SetHeight(v.bodyHeight())
+}
+
+// ccSummary describes a long Cc list on one line
+func (v *ComposeView) ccSummary() string {
+	addrs := v.ccAddresses()
+	return fmt.Sprintf("%d recipients — %s, %s and %d more (tab here to edit)",
+		len(addrs), addrs[0], addrs[1], len(addrs)-2)
 }
 
 // SetReply configures the view for replying
@@ -171,29 +332,12 @@ func (v *ComposeView) SetReply(email *models.Email, replyAll bool) {
 		v.Mode = ModeReply
 	}
 
-	// Set To address
-	replyTo := ""
-	if len(email.ReplyTo) > 0 {
-		replyTo = email.ReplyTo[0].Email
-	} else if len(email.From) > 0 {
-		replyTo = email.From[0].Email
-	}
-	v.to.SetValue(replyTo)
+	to, cc := replyRecipients(email, replyAll)
+	v.to.SetValue(strings.Join(to, ", "))
 
 	// Set CC for reply-all (excluding self - caller should pass myEmail)
 	if replyAll {
-		var ccAddrs []string
-		for _, addr := range email.To {
-			if addr.Email != replyTo {
-				ccAddrs = append(ccAddrs, addr.Email)
-			}
-		}
-		for _, addr := range email.CC {
-			if addr.Email != replyTo {
-				ccAddrs = append(ccAddrs, addr.Email)
-			}
-		}
-		v.cc.SetValue(strings.Join(ccAddrs, ", "))
+		v.cc.SetValue(strings.Join(cc, ", "))
 	}
 
 	// Set subject with Re: prefix
@@ -203,8 +347,14 @@ func (v *ComposeView) SetReply(email *models.Email, replyAll bool) {
 	}
 	v.subject.SetValue(subject)
 
-	// Quote original message
-	v.body.SetValue(v.quoteText(email.TextBody, email.From))
+	// Quote original message, with room to write below it when
+	// bottom-posting
+	quote := v.quoteText(email.TextBody, email.From)
+	if v.bottomPost && quote != "" {
+		quote = strings.TrimLeft(quote, "\n") + "\n"
+	}
+	v.body.SetValue(quote)
+	v.placeReplyCursor()
 
 	// Focus From if multiple identities, otherwise body for typing
 	if len(v.identities) > 1 {
@@ -256,6 +406,128 @@ func (v *ComposeView) SetForward(email *models.Email) {
 	}
 }
 
+// SetDraft fills the view with a message written earlier, such as one
+// that failed to send
+func (v *ComposeView) SetDraft(to, cc []string, subject, body, identityID, fromName string) {
+	v.to.SetValue(strings.Join(to, ", "))
+	v.cc.SetValue(strings.Join(cc, ", "))
+	v.subject.SetValue(subject)
+	v.body.SetValue(body)
+	v.name.SetValue(fromName)
+	for i, id := range v.identities {
+		if id.ID == identityID {
+			v.selectedIdentity = i
+			v.updateNamePlaceholder()
+			break
+		}
+	}
+	v.focusField(FieldBody)
+}
+
+// FocusField puts the cursor in a field, such as one that needs
+// correcting before the message can be sent
+func (v *ComposeView) FocusField(field ComposeField) {
+	v.focusField(field)
+}
+
+// SetSignature adds the account signature below where the message is
+// typed: above any quoted or forwarded text, or at the very end of a
+// bottom-posted reply
+func (v *ComposeView) SetSignature(signature string) {
+	signature = strings.TrimRight(signature, "\n")
+	if strings.TrimSpace(signature) == "" {
+		return
+	}
+	v.signature = signature
+	if v.bottomPosted() {
+		v.body.SetValue(v.body.Value() + "\n\n-- \n" + signature)
+	} else {
+		v.body.SetValue("\n\n-- \n" + signature + v.body.Value())
+	}
+	v.placeReplyCursor()
+}
+
+// swapSignature replaces the signature in the body with the selected
+// identity's. The body is left alone if the signature was edited by hand,
+// or if it came with one already, as a restored draft does.
+func (v *ComposeView) swapSignature() {
+	id := v.GetIdentity()
+	if id == nil {
+		return
+	}
+	signature := strings.TrimRight(id.Signature, "\n")
+	if signature == v.signature {
+		return
+	}
+
+	body := v.body.Value()
+	if v.signature != "" {
+		old := "\n\n-- \n" + v.signature
+		i := strings.Index(body, old)
+		if v.bottomPosted() {
+			i = strings.LastIndex(body, old)
+		}
+		if i < 0 {
+			return
+		}
+		body = body[:i] + body[i+len(old):]
+	} else if strings.Contains(body, "\n-- \n") {
+		return
+	}
+
+	v.body.SetValue(body)
+	v.signature = ""
+	v.SetSignature(signature)
+	if v.signature == "" {
+		v.placeReplyCursor()
+	}
+}
+
+// replyRecipients works out who a reply goes to. A plain reply goes to the
+// Reply-To address if set, otherwise the sender. Reply-all also keeps the
+// original sender in To when Reply-To points elsewhere (e.g. a mailing
+// list), and copies everyone else from To and Cc. Addresses are deduped
+// case-insensitively across both fields.
+func replyRecipients(email *models.Email, replyAll bool) (to, cc []string) {
+	seen := make(map[string]bool)
+	add := func(list *[]string, addr string) {
+		key := strings.ToLower(strings.TrimSpace(addr))
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		*list = append(*list, addr)
+	}
+
+	if len(email.ReplyTo) > 0 {
+		if replyAll {
+			for _, addr := range email.ReplyTo {
+				add(&to, addr.Email)
+			}
+		} else {
+			add(&to, email.ReplyTo[0].Email)
+		}
+	} else if len(email.From) > 0 {
+		add(&to, email.From[0].Email)
+	}
+
+	if !replyAll {
+		return to, nil
+	}
+
+	// Reply-To may differ from From; don't drop the original sender
+	for _, addr := range email.From {
+		add(&to, addr.Email)
+	}
+	for _, addr := range email.To {
+		add(&cc, addr.Email)
+	}
+	for _, addr := range email.CC {
+		add(&cc, addr.Email)
+	}
+	return to, cc
+}
+
 func (v *ComposeView) quoteText(body string, from []models.EmailAddress) string {
 	if body == "" {
 		return ""
@@ -283,21 +555,68 @@ func (v *ComposeView) quoteText(body string, from []models.EmailAddress) string
 	return quoted.String()
 }
 
-// RemoveSelfFromCC removes the user's own email from CC
-func (v *ComposeView) RemoveSelfFromCC(myEmail string) {
-	ccVal := v.cc.Value()
-	if ccVal == "" {
+// RemoveSelfFromRecipients removes the user's own email from CC, and from
+// To as long as someone else is left to receive the reply
+func (v *ComposeView) RemoveSelfFromRecipients(myEmail string) {
+	if filtered := removeAddress(v.to.Value(), myEmail); len(filtered) > 0 {
+		v.to.SetValue(strings.Join(filtered, ", "))
+	}
+
+	if v.cc.Value() == "" {
 		return
 	}
+	v.cc.SetValue(strings.Join(removeAddress(v.cc.Value(), myEmail), ", "))
+}
 
+// removeAddress splits a comma-separated address list, dropping addr
+func removeAddress(list, addr string) []string {
 	var filtered []string
-	for _, addr := range strings.Split(ccVal, ",") {
-		addr = strings.TrimSpace(addr)
-		if addr != "" && addr != myEmail {
-			filtered = append(filtered, addr)
+	for _, a := range strings.Split(list, ",") {
+		a = strings.TrimSpace(a)
+		if a != "" && !strings.EqualFold(a, addr) {
+			filtered = append(filtered, a)
 		}
 	}
-	v.cc.SetValue(strings.Join(filtered, ", "))
+	return filtered
+}
+
+// AddRecipients appends addresses to the Cc list when it has focus and to
+// To otherwise, skipping any already there. It returns the name of the
+// field they went to.
+func (v *ComposeView) AddRecipients(addrs []string) string {
+	if v.focused != FieldCc {
+		v.to.SetValue(strings.Join(appendAddresses(splitAddresses(v.to.Value()), addrs), ", "))
+		v.to.CursorEnd()
+		return "To"
+	}
+
+	if v.ccExpanded {
+		list := appendAddresses(splitAddresses(v.ccArea.Value()), addrs)
+		v.ccArea.SetValue(strings.Join(list, "\n"))
+		v.ccArea.SetHeight(min(len(list), 8))
+		v.body.SetHeight(v.bodyHeight())
+		return "Cc"
+	}
+	v.cc.SetValue(strings.Join(appendAddresses(v.ccAddresses(), addrs), ", "))
+	v.cc.CursorEnd()
+	return "Cc"
+}
+
+// appendAddresses adds the addresses not already in list
+func appendAddresses(list, addrs []string) []string {
+	for _, addr := range addrs {
+		present := false
+		for _, existing := range list {
+			if strings.EqualFold(existing, addr) {
+				present = true
+				break
+			}
+		}
+		if !present {
+			list = append(list, addr)
+		}
+	}
+	return list
 }
 
 func (v *ComposeView) focusField(field ComposeField) {
@@ -305,22 +624,40 @@ func (v *ComposeView) focusField(field ComposeField) {
 	if field == FieldFrom && len(v.identities) <= 1 {
 		field = FieldTo
 	}
+	// Skip send-at if the server can't schedule
+	if field == FieldSendAt && !v.scheduling {
+		field = FieldBody
+	}
+
+	if v.ccExpanded && field != FieldCc {
+		v.collapseCc()
+	}
 
 	v.focused = field
+	v.name.Blur()
 	v.to.Blur()
 	v.cc.Blur()
 	v.subject.Blur()
+	v.sendAt.Blur()
 	v.body.Blur()
 
 	switch field {
 	case FieldFrom:
 		// No input widget for From, just visual focus
+	case FieldName:
+		v.name.Focus()
 	case FieldTo:
 		v.to.Focus()
 	case FieldCc:
-		v.cc.Focus()
+		if len(v.ccAddresses()) > ccSummaryAt {
+			v.expandCc()
+		} else {
+			v.cc.Focus()
+		}
 	case FieldSubject:
 		v.subject.Focus()
+	case FieldSendAt:
+		v.sendAt.Focus()
 	case FieldBody:
 		v.body.Focus()
 	}
@@ -340,18 +677,53 @@ func (v *ComposeView) Update(msg tea.Msg) (*ComposeView, tea.Cmd) {
 				if v.selectedIdentity < 0 {
 					v.selectedIdentity = len(v.identities) - 1
 				}
+				v.updateNamePlaceholder()
+				v.swapSignature()
 				return v, nil
 			case "right", "l", "tab", "enter":
 				v.selectedIdentity++
 				if v.selectedIdentity >= len(v.identities) {
 					v.selectedIdentity = 0
 				}
+				v.updateNamePlaceholder()
+				v.swapSignature()
 				return v, nil
 			case "down":
 				// Move to next field
+				v.focusField(FieldName)
+				return v, nil
+			}
+		}
+
+		// The expanded Cc editor keeps up and down for moving between lines
+		if v.ccExpanded {
+			switch msg.String() {
+			case "tab":
+				v.focusField(FieldSubject)
+				return v, nil
+			case "shift+tab":
 				v.focusField(FieldTo)
 				return v, nil
 			}
+			var cmd tea.Cmd
+			v.ccArea, cmd = v.ccArea.Update(msg)
+			return v, cmd
+		}
+
+		// Jump over the reply: to the top, or to just above the quote
+		if v.focused == FieldBody {
+			switch msg.String() {
+			case "alt+up":
+				v.jumpBodyStart()
+				return v, nil
+			case "alt+down":
+				if v.bottomPosted() {
+					v.jumpBelowQuote()
+				} else {
+					v.jumpAboveQuote()
+				}
+				return v, nil
+			}
 		}
 
 		switch msg.String() {
@@ -363,7 +735,7 @@ func (v *ComposeView) Update(msg tea.Msg) (*ComposeView, tea.Cmd) {
 			}
 		case "shift+tab", "up":
 			// Move to previous field (only from header fields)
-			minField := FieldTo
+			minField := FieldName
 			if len(v.identities) > 1 {
 				minField = FieldFrom
 			}
@@ -382,12 +754,16 @@ func (v *ComposeView) Update(msg tea.Msg) (*ComposeView, tea.Cmd) {
 	switch v.focused {
 	case FieldFrom:
 		// From field doesn't have an input widget
+	case FieldName:
+		v.name, cmd = v.name.Update(msg)
 	case FieldTo:
 		v.to, cmd = v.to.Update(msg)
 	case FieldCc:
 		v.cc, cmd = v.cc.Update(msg)
 	case FieldSubject:
 		v.subject, cmd = v.subject.Update(msg)
+	case FieldSendAt:
+		v.sendAt, cmd = v.sendAt.Update(msg)
 	case FieldBody:
 		v.body, cmd = v.body.Update(msg)
 	}
@@ -410,7 +786,7 @@ func (v *ComposeView) View() string {
 	case ModeForward:
 		modeStr = "forward"
 	}
-	header := composeHeaderStyle.Render("◈ " + modeStr)
+	header := composeHeaderStyle.Render("◈ " + i18n.T(modeStr))
 	b.WriteString(header)
 	b.WriteString("\n\n")
 
@@ -441,9 +817,18 @@ func (v *ComposeView) View() string {
 			fromStyle := lipgloss.NewStyle().Foreground(composeColorSecondary)
 			b.WriteString(fromStyle.Render(identityStr))
 		}
+		if id := v.GetIdentity(); id != nil && id.ReplyTo != "" {
+			b.WriteString(composeLabelStyle.Render("  reply-to: " + id.ReplyTo))
+		}
 		b.WriteString("\n")
 	}
 
+	// From name override
+	nameLabel := composeLabelStyle.Render("name: ")
+	b.WriteString(nameLabel)
+	b.WriteString(v.name.View())
+	b.WriteString("\n")
+
 	// To field
 	toLabel := composeLabelStyle.Render("to: ")
 	b.WriteString(toLabel)
@@ -453,21 +838,50 @@ func (v *ComposeView) View() string {
 	// Cc field
 	ccLabel := composeLabelStyle.Render("cc: ")
 	b.WriteString(ccLabel)
-	b.WriteString(v.cc.View())
+	switch {
+	case v.ccExpanded:
+		b.WriteString("\n")
+		b.WriteString(v.ccArea.View())
+	case len(v.ccAddresses()) > ccSummaryAt:
+		b.WriteString(lipgloss.NewStyle().Foreground(composeColorSecondary).Render(v.ccSummary()))
+	default:
+		b.WriteString(v.cc.View())
+	}
 	b.WriteString("\n")
 
 	// Subject field
 	subjectLabel := composeLabelStyle.Render("subject: ")
 	b.WriteString(subjectLabel)
 	b.WriteString(v.subject.View())
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+
+	// Send-at field
+	if v.scheduling {
+		sendAtLabel := composeLabelStyle.Render("send at: ")
+		b.WriteString(sendAtLabel)
+		b.WriteString(v.sendAt.View())
+		b.WriteString("\n")
+	}
+
+	// Attached files
+	if len(v.attachments) > 0 {
+		b.WriteString(composeLabelStyle.Render("attach: "))
+		b.WriteString(lipgloss.NewStyle().Foreground(composeColorSecondary).MaxWidth(v.width - 12).Render(v.attachmentSummary()))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
 
 	// Body
 	b.WriteString(v.body.View())
 	b.WriteString("\n")
 
+	if v.err != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(composeColorAccent).Render("✗ " + v.err))
+		b.WriteString("\n")
+	}
+
 	// Help - add tab hint if on From field
-	helpText := "tab: next field │ ctrl+s: send │ esc: cancel"
+	helpText := "tab: next field │ ctrl+r: contacts │ ctrl+o: attach │ ctrl+s: send │ esc: cancel"
 	if v.focused == FieldFrom {
 		helpText = "tab/←/→: cycle identity │ ↓: next field │ ctrl+s: send │ esc: cancel"
 	}
@@ -480,36 +894,189 @@ func (v *ComposeView) View() string {
 // GetValues returns the composed email values
 func (v *ComposeView) GetValues() (to, cc []string, subject, body string) {
 	// Parse To addresses
-	toVal := v.to.Value()
-	if toVal != "" {
-		for _, addr := range strings.Split(toVal, ",") {
-			addr = strings.TrimSpace(addr)
-			if addr != "" {
-				to = append(to, addr)
-			}
+	to, _ = parseAddressList(v.to.Value())
+
+	// Parse CC addresses
+	cc, _ = parseAddressList(v.ccValue())
+
+	subject = v.subject.Value()
+	body = v.body.Value()
+
+	return
+}
+
+// Validate checks there's someone to send to, the recipient lists and the
+// send time. On failure it returns the field with the problem, and an
+// error naming it and every malformed address in it.
+func (v *ComposeView) Validate() (ComposeField, error) {
+	if !v.HasRecipients() {
+		return FieldTo, fmt.Errorf("add a recipient before sending")
+	}
+	for _, f := range []struct {
+		field ComposeField
+		name  string
+		value string
+	}{
+		{FieldTo, "to", v.to.Value()},
+		{FieldCc, "cc", v.ccValue()},
+	} {
+		errs := validateAddresses(splitAddresses(f.value))
+		if len(errs) == 0 {
+			continue
+		}
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			msgs[i] = err.Error()
+		}
+		return f.field, fmt.Errorf("%s: %s", f.name, strings.Join(msgs, "; "))
+	}
+	if _, err := parseSendAt(v.sendAt.Value(), time.Now()); err != nil {
+		return FieldSendAt, fmt.Errorf("send at: %w", err)
+	}
+	return 0, nil
+}
+
+// GetSendAt returns when the message should be sent, or the zero time to
+// send it now
+func (v *ComposeView) GetSendAt() time.Time {
+	if !v.scheduling {
+		return time.Time{}
+	}
+	sendAt, _ := parseSendAt(v.sendAt.Value(), time.Now())
+	return sendAt
+}
+
+// parseSendAt reads a send time: a clock time ("17:30", today or else
+// tomorrow), "tomorrow" with an optional time (9:00 by default), a delay
+// ("2h", "in 30m") or a full "2006-01-02 15:04". Empty means now.
+func parseSendAt(s string, now time.Time) (time.Time, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" || s == "now" {
+		return time.Time{}, nil
+	}
+
+	var at time.Time
+	if d, err := time.ParseDuration(strings.TrimPrefix(s, "in ")); err == nil {
+		at = now.Add(d)
+	} else if t, err := time.ParseInLocation("2006-01-02 15:04", s, now.Location()); err == nil {
+		at = t
+	} else if rest, ok := strings.CutPrefix(s, "tomorrow"); ok {
+		clock := strings.TrimSpace(rest)
+		if clock == "" {
+			clock = "9:00"
+		}
+		t, err := time.ParseInLocation("15:04", clock, now.Location())
+		if err != nil {
+			return time.Time{}, fmt.Errorf("can't read %q, try \"tomorrow 9:00\"", s)
 		}
+		day := now.AddDate(0, 0, 1)
+		at = time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+	} else if t, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		at = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+	} else {
+		return time.Time{}, fmt.Errorf("can't read %q, try 17:30, tomorrow 9:00 or 2h", s)
 	}
 
-	// Parse CC addresses
-	ccVal := v.cc.Value()
-	if ccVal != "" {
-		for _, addr := range strings.Split(ccVal, ",") {
-			addr = strings.TrimSpace(addr)
-			if addr != "" {
-				cc = append(cc, addr)
+	if !at.After(now) {
+		return time.Time{}, fmt.Errorf("%s is in the past", at.Format("Jan 2 15:04"))
+	}
+	return at, nil
+}
+
+// SetError shows a validation error in the compose view; pass "" to clear
+func (v *ComposeView) SetError(msg string) {
+	v.err = msg
+}
+
+// validateAddresses returns an error for each entry that isn't a usable
+// address, saying what looks wrong with it
+func validateAddresses(addrs []string) []error {
+	var errs []error
+	for _, entry := range addrs {
+		parsed, err := mail.ParseAddress(entry)
+		if err == nil && !strings.ContainsAny(parsed.Address, "\r\n") {
+			continue
+		}
+
+		bare := entry
+		if i := strings.LastIndex(bare, "<"); i >= 0 {
+			bare = strings.TrimSuffix(bare[i+1:], ">")
+		}
+		local, domain, hasAt := strings.Cut(bare, "@")
+		reason := "not an email address"
+		switch {
+		case !hasAt:
+			reason = "missing @"
+		case local == "":
+			reason = "nothing before the @"
+		case domain == "":
+			reason = "missing domain after the @"
+		case strings.ContainsAny(bare, " \t"):
+			reason = "contains a space"
+		case strings.Count(bare, "@") > 1:
+			reason = "more than one @"
+		}
+		errs = append(errs, fmt.Errorf("%q %s", entry, reason))
+	}
+	return errs
+}
+
+// parseAddressList splits a comma-separated recipient list into bare
+// addresses. Entries like "Name <addr>" are reduced to addr; anything
+// that doesn't parse is reported and skipped.
+func parseAddressList(list string) ([]string, error) {
+	var addrs []string
+	var firstErr error
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parsed, err := mail.ParseAddress(entry)
+		if err != nil || strings.ContainsAny(parsed.Address, "\r\n") {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("invalid address %q", entry)
 			}
+			continue
 		}
+		addrs = append(addrs, parsed.Address)
 	}
+	return addrs, firstErr
+}
 
-	subject = v.subject.Value()
-	body = v.body.Value()
+// GetFromName returns the display name override for the From header, or
+// an empty string to use the identity's own name
+func (v *ComposeView) GetFromName() string {
+	return strings.TrimSpace(v.name.Value())
+}
 
-	return
+// DraftID returns the email ID of the autosaved draft, or "" if the
+// message hasn't been saved yet
+func (v *ComposeView) DraftID() string {
+	return v.draftID
 }
 
-// IsEmpty returns true if the body is empty (cancel condition)
+// SetDraftID records the email ID of the latest autosaved draft
+func (v *ComposeView) SetDraftID(id string) {
+	v.draftID = id
+}
+
+// IsEmpty returns true if the body is empty (cancel condition). The
+// signature added to the body doesn't count as text.
 func (v *ComposeView) IsEmpty() bool {
-	return strings.TrimSpace(v.body.Value()) == ""
+	body := v.body.Value()
+	if v.signature != "" {
+		body = strings.Replace(body, "\n\n-- \n"+v.signature, "", 1)
+	}
+	return strings.TrimSpace(body) == ""
+}
+
+// HasSubject returns true if the subject isn't blank
+func (v *ComposeView) HasSubject() bool {
+	return strings.TrimSpace(v.subject.Value()) != ""
 }
 
 // HasRecipients returns true if there's at least one recipient
@@ -530,6 +1097,7 @@ func (v *ComposeView) SelectIdentityByEmail(email string) {
 	for i, id := range v.identities {
 		if strings.EqualFold(id.Email, email) {
 			v.selectedIdentity = i
+			v.updateNamePlaceholder()
 			return
 		}
 	}
@@ -0,0 +1,100 @@
+package views
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/the9x/anneal/internal/models"
+)
+
+func TestReplyRecipients(t *testing.T) {
+	addrs := func(emails ...string) []models.EmailAddress {
+		var out []models.EmailAddress
+		for _, e := range emails {
+			out = append(out, models.EmailAddress{Email: e})
+		}
+		return out
+	}
+	list := &models.Email{
+		From:    addrs("alice@example.com"),
+		ReplyTo: addrs("list@example.org"),
+		To:      addrs("me@example.com", "List@Example.org"),
+		CC:      addrs("bob@example.com", "ALICE@example.com"),
+	}
+
+	tests := []struct {
+		name     string
+		email    *models.Email
+		replyAll bool
+		wantTo   []string
+		wantCC   []string
+	}{
+		{"reply goes to Reply-To", list, false, []string{"list@example.org"}, nil},
+		{"reply all keeps the sender", list, true,
+			[]string{"list@example.org", "alice@example.com"},
+			[]string{"me@example.com", "bob@example.com"}},
+		{"reply without Reply-To", &models.Email{From: addrs("alice@example.com")}, false,
+			[]string{"alice@example.com"}, nil},
+		{"Reply-To same as From", &models.Email{From: addrs("alice@example.com"), ReplyTo: addrs("Alice@example.com")}, true,
+			[]string{"Alice@example.com"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to, cc := replyRecipients(tt.email, tt.replyAll)
+			if strings.Join(to, ",") != strings.Join(tt.wantTo, ",") {
+				t.Errorf("to = %q, want %q", to, tt.wantTo)
+			}
+			if strings.Join(cc, ",") != strings.Join(tt.wantCC, ",") {
+				t.Errorf("cc = %q, want %q", cc, tt.wantCC)
+			}
+		})
+	}
+}
+
+func TestIsEmptyIgnoresSignature(t *testing.T) {
+	v := NewComposeView(80, 24, nil)
+	v.SetSignature("Jane Doe\nExample Corp")
+	if !v.IsEmpty() {
+		t.Error("a body with only the signature counts as written")
+	}
+
+	v.body.SetValue("Hi Bob," + v.body.Value())
+	if v.IsEmpty() {
+		t.Error("a body with text above the signature counts as empty")
+	}
+}
+
+func TestValidateLeavesFocus(t *testing.T) {
+	tests := []struct {
+		name      string
+		to, cc    []string
+		wantField ComposeField
+	}{
+		{"no recipient", nil, nil, FieldTo},
+		{"bad to", []string{"bob.example.com"}, nil, FieldTo},
+		{"bad cc", []string{"bob@example.com"}, []string{"carol@"}, FieldCc},
+	}
+	for _, tt := range tests {
+		v := NewComposeView(80, 24, nil)
+		v.SetDraft(tt.to, tt.cc, "Hi", "Body", "", "")
+		v.focusField(FieldSubject)
+
+		field, err := v.Validate()
+		if err == nil {
+			t.Errorf("%s: Validate passed", tt.name)
+			continue
+		}
+		if field != tt.wantField {
+			t.Errorf("%s: Validate blamed field %d, want %d", tt.name, field, tt.wantField)
+		}
+		if v.focused != FieldSubject {
+			t.Errorf("%s: Validate moved focus to %d", tt.name, v.focused)
+		}
+	}
+
+	v := NewComposeView(80, 24, nil)
+	v.SetDraft([]string{"bob@example.com"}, nil, "Hi", "Body", "", "")
+	if _, err := v.Validate(); err != nil {
+		t.Errorf("valid message: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ToggleAttachment attaches the file at path, or takes it off again if
+// it's already attached. It returns true if the file is now attached.
+func (v *ComposeView) ToggleAttachment(path string) bool {
+	for i, p := range v.attachments {
+		if p == path {
+			v.attachments = append(v.attachments[:i], v.attachments[i+1:]...)
+			delete(v.attachmentSizes, path)
+			v.body.SetHeight(v.bodyHeight())
+			return false
+		}
+	}
+	v.attachments = append(v.attachments, path)
+	v.recordSize(path)
+	v.body.SetHeight(v.bodyHeight())
+	return true
+}
+
+// SetAttachments replaces the attached files, as when reopening a message
+// that failed to send
+func (v *ComposeView) SetAttachments(paths []string) {
+	v.attachments = append([]string(nil), paths...)
+	v.attachmentSizes = nil
+	for _, p := range paths {
+		v.recordSize(p)
+	}
+	v.body.SetHeight(v.bodyHeight())
+}
+
+// recordSize notes the size of an attached file, so the summary doesn't
+// have to read the disk on every render
+func (v *ComposeView) recordSize(path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	if v.attachmentSizes == nil {
+		v.attachmentSizes = make(map[string]int64)
+	}
+	v.attachmentSizes[path] = info.Size()
+}
+
+// Attachments returns the paths of the attached files
+func (v *ComposeView) Attachments() []string {
+	return v.attachments
+}
+
+// attachmentSummary lists the attached files by name, with their total
+// size as of when they were attached
+func (v *ComposeView) attachmentSummary() string {
+	names := make([]string, len(v.attachments))
+	var total int64
+	for i, p := range v.attachments {
+		names[i] = filepath.Base(p)
+		total += v.attachmentSizes[p]
+	}
+	if total == 0 {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s (%s)", strings.Join(names, ", "), FormatSize(int(total)))
+}
@@ -0,0 +1,43 @@
+package views
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAttachmentSummaryUsesSizeWhenAttached(t *testing.T) {
+	dir := t.TempDir()
+	report := filepath.Join(dir, "report.pdf")
+	notes := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(report, []byte(strings.Repeat("x", 2048)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(notes, []byte(strings.Repeat("x", 1024)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewComposeView(80, 24, nil)
+	v.ToggleAttachment(report)
+	v.ToggleAttachment(notes)
+	if got, want := v.attachmentSummary(), "report.pdf, notes.txt (3.0 KB)"; got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+
+	// The size is from when the file was attached, not read on each render
+	os.WriteFile(report, nil, 0600)
+	if got, want := v.attachmentSummary(), "report.pdf, notes.txt (3.0 KB)"; got != want {
+		t.Errorf("summary after the file changed = %q, want %q", got, want)
+	}
+
+	v.ToggleAttachment(report)
+	if got, want := v.attachmentSummary(), "notes.txt (1.0 KB)"; got != want {
+		t.Errorf("summary after removing one = %q, want %q", got, want)
+	}
+
+	v.SetAttachments([]string{filepath.Join(dir, "missing.zip")})
+	if got, want := v.attachmentSummary(), "missing.zip"; got != want {
+		t.Errorf("summary of an unreadable file = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,109 @@
+package views
+
+import "strings"
+
+// forwardMarker starts the forwarded message built by SetForward
+const forwardMarker = "---------- Forwarded message ----------"
+
+// jumpBodyStart moves the body cursor to the first line
+func (v *ComposeView) jumpBodyStart() {
+	v.moveBodyCursor(0, 0)
+}
+
+// jumpAboveQuote moves the body cursor to the end of your own text: the
+// last non-empty line above the signature and the quoted or forwarded
+// message. With nothing typed yet that's the top of the body.
+func (v *ComposeView) jumpAboveQuote() {
+	lines := strings.Split(v.body.Value(), "\n")
+
+	// Where the quote begins, including its "On ... wrote:" line
+	end := len(lines)
+	for i, line := range lines {
+		if strings.HasPrefix(line, ">") || line == forwardMarker {
+			end = i
+			if line != forwardMarker && i > 0 && strings.HasSuffix(strings.TrimSpace(lines[i-1]), "wrote:") {
+				end = i - 1
+			}
+			break
+		}
+	}
+	// The signature sits between your text and the quote
+	for i := 0; i < end; i++ {
+		if lines[i] == "-- " {
+			end = i
+			break
+		}
+	}
+
+	row := 0
+	for i := end - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			row = i
+			break
+		}
+	}
+	v.moveBodyCursor(row, len([]rune(lines[row])))
+}
+
+// jumpBelowQuote moves the body cursor to the end of your own text in a
+// bottom-posted reply: the last non-empty line between the quote and the
+// signature, or the line after the quote with nothing typed yet
+func (v *ComposeView) jumpBelowQuote() {
+	lines := strings.Split(v.body.Value(), "\n")
+
+	// The last quoted line, if there's a quote
+	quoteEnd := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, ">") {
+			quoteEnd = i
+		}
+	}
+	end := len(lines)
+	for i := quoteEnd + 1; i < len(lines); i++ {
+		if lines[i] == "-- " {
+			end = i
+			break
+		}
+	}
+
+	// Leave a blank line after the quote
+	row := 0
+	if quoteEnd >= 0 {
+		row = min(quoteEnd+2, len(lines)-1)
+	}
+	for i := end - 1; i > quoteEnd; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			row = i
+			break
+		}
+	}
+	v.moveBodyCursor(row, len([]rune(lines[row])))
+}
+
+// bottomPosted returns true if the body is a reply written below the quote
+func (v *ComposeView) bottomPosted() bool {
+	return v.bottomPost && (v.Mode == ModeReply || v.Mode == ModeReplyAll)
+}
+
+// placeReplyCursor puts the cursor where a new reply is written: the top
+// of the body, or below the quote when bottom-posting
+func (v *ComposeView) placeReplyCursor() {
+	if v.bottomPosted() {
+		v.jumpBelowQuote()
+	} else {
+		v.jumpBodyStart()
+	}
+}
+
+// moveBodyCursor puts the body cursor on a line and column. The textarea
+// only moves a line at a time, and over soft-wrapped lines too, so it
+// steps until it reaches the line.
+func (v *ComposeView) moveBodyCursor(row, col int) {
+	for guard := 0; v.body.Line() > row && guard < 10000; guard++ {
+		v.body.CursorUp()
+	}
+	for guard := 0; v.body.Line() < row && guard < 10000; guard++ {
+		v.body.CursorDown()
+	}
+	v.body.SetCursor(col)
+}
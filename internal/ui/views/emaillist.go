@@ -6,16 +6,17 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/the9x/anneal/internal/models"
+	"github.com/the9x/anneal/internal/ui/theme"
 )
 
 // anneal brand colors
 var (
-	listColorPrimary   = lipgloss.Color("#d4d2e3")
-	listColorSecondary = lipgloss.Color("#9795b5")
-	listColorDim       = lipgloss.Color("#5a5880")
-	listColorBg        = lipgloss.Color("#1d1d40")
-	listColorBgSelect  = lipgloss.Color("#2d2d5a")
-	listColorAccent    = lipgloss.Color("#e61e25") // used sparingly
+	listColorPrimary   = theme.Primary
+	listColorSecondary = theme.Secondary
+	listColorDim       = theme.Dim
+	listColorBg        = theme.Bg
+	listColorBgSelect  = theme.BgSelect
+	listColorAccent    = theme.Accent // used sparingly
 
 	emailListHeaderStyle = lipgloss.NewStyle().
 				Foreground(listColorDim).
@@ -196,10 +197,7 @@ func (v *EmailListView) renderEmailRow(email models.Email, selected bool, fromWi
 	}
 
 	// From
-	from := email.FromDisplay()
-	if len(from) > fromWidth {
-		from = from[:fromWidth-1] + "…"
-	}
+	from := truncate(email.FromDisplay(), fromWidth)
 	fromStyle := emailFromStyle
 	if email.IsUnread {
 		fromStyle = emailFromUnreadStyle
@@ -211,9 +209,7 @@ func (v *EmailListView) renderEmailRow(email models.Email, selected bool, fromWi
 	if subject == "" {
 		subject = "(no subject)"
 	}
-	if len(subject) > subjectWidth {
-		subject = subject[:subjectWidth-1] + "…"
-	}
+	subject = truncate(subject, subjectWidth)
 	subjectStyle := emailSubjectStyle
 	if email.IsUnread {
 		subjectStyle = emailSubjectUnreadStyle
@@ -221,7 +217,7 @@ func (v *EmailListView) renderEmailRow(email models.Email, selected bool, fromWi
 	subjectStr := subjectStyle.Width(subjectWidth).Render(subject)
 
 	// Date
-	dateStr := emailDateStyle.Width(dateWidth).Render(email.DateDisplay())
+	dateStr := emailDateStyle.Width(dateWidth).Render(email.DateDisplay(false))
 
 	// Combine row
 	row := fmt.Sprintf("%s%s %s  %s  %s", unreadDot, flags, fromStr, subjectStr, dateStr)
@@ -7,17 +7,18 @@ import (
 
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/the9x/anneal/internal/models"
+	"github.com/the9x/anneal/internal/ui/theme"
 )
 
-const maxEmailWidth = 100
-
 // anneal brand colors
 var (
-	readerColorPrimary   = lipgloss.Color("#d4d2e3")
-	readerColorSecondary = lipgloss.Color("#9795b5")
-	readerColorDim       = lipgloss.Color("#5a5880")
-	readerColorBg        = lipgloss.Color("#1d1d40")
+	readerColorPrimary   = theme.Primary
+	readerColorSecondary = theme.Secondary
+	readerColorDim       = theme.Dim
+	readerColorBg        = theme.Bg
+	readerColorAccent    = theme.Accent
 
 	readerHeaderStyle = lipgloss.NewStyle().
 				Background(readerColorBg).
@@ -67,17 +68,38 @@ type EmailReaderView struct {
 	contentWidth       int
 	scrollY            int
 	lines              []string
+	maxWidth           int // content width cap, 0 for none
 	renderer           *glamour.TermRenderer
 	attachmentMode     bool // true when navigating attachments
 	selectedAttachment int  // index of selected attachment
+	showAllRecipients  bool   // true to list every To/Cc address
+	otp                string // detected one-time code, shown in the header
+	showSize           bool   // true to show the message size in the header
+	shortAddresses     bool   // true to show names without their addresses
+	compact            bool   // true to squeeze the header into a line or two
+	sent               bool   // true to date the message by when it was sent
+	pipeCommand        string // command whose output replaces the body
+	pipeOutput         string
+
+	// When the text and HTML parts differ, showHTML picks the HTML one
+	divergentParts bool
+	showHTML       bool
+
+	// Find in the body: the term, the matching line indices and which
+	// match is current
+	findTerm string
+	matches  []int
+	matchIdx int
 }
 
-// NewEmailReaderView creates a new email reader view
-func NewEmailReaderView(email *models.Email, width, height int) *EmailReaderView {
-	contentWidth := width
-	if contentWidth > maxEmailWidth {
-		contentWidth = maxEmailWidth
-	}
+// maxHeaderRecipients is how many addresses a To or Cc line shows before
+// collapsing the rest into "and N others"
+const maxHeaderRecipients = 3
+
+// NewEmailReaderView creates a new email reader view. maxWidth caps the
+// body width for readability; 0 uses the full width.
+func NewEmailReaderView(email *models.Email, width, height, maxWidth int) *EmailReaderView {
+	contentWidth := capWidth(width, maxWidth)
 
 	// Create glamour renderer for markdown
 	renderer, _ := glamour.NewTermRenderer(
@@ -90,23 +112,25 @@ func NewEmailReaderView(email *models.Email, width, height int) *EmailReaderView
 		width:        width,
 		height:       height,
 		contentWidth: contentWidth,
+		maxWidth:     maxWidth,
 		renderer:     renderer,
 	}
+	if email != nil {
+		v.divergentParts = partsDiffer(email.TextBody, email.HTMLBody)
+	}
 	v.prepareContent()
 	return v
 }
 
 // SetSize updates the view dimensions
 func (v *EmailReaderView) SetSize(width, height int) {
-	contentWidth := width
-	if contentWidth > maxEmailWidth {
-		contentWidth = maxEmailWidth
-	}
+	contentWidth := capWidth(width, v.maxWidth)
 
 	if v.contentWidth != contentWidth {
 		v.contentWidth = contentWidth
 		v.width = width
 		v.prepareContent()
+		v.findMatches()
 	}
 	v.height = height
 }
@@ -129,6 +153,110 @@ func (v *EmailReaderView) ScrollDown() {
 	}
 }
 
+// ScrollOffset returns the current scroll position in lines
+func (v *EmailReaderView) ScrollOffset() int {
+	return v.scrollY
+}
+
+// SetScrollOffset restores a scroll position, clamped to the content
+func (v *EmailReaderView) SetScrollOffset(y int) {
+	maxScroll := len(v.lines) - v.height + 10
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if y > maxScroll {
+		y = maxScroll
+	}
+	if y < 0 {
+		y = 0
+	}
+	v.scrollY = y
+}
+
+// bodyHeight returns how many body lines fit below the header
+func (v *EmailReaderView) bodyHeight() int {
+	if h := v.height - 7 - lipgloss.Height(v.renderHeader()); h > 0 {
+		return h
+	}
+	return 1
+}
+
+// SetOTP shows a detected one-time code prominently in the header
+func (v *EmailReaderView) SetOTP(code string) {
+	v.otp = code
+}
+
+// SetCompactHeader squeezes the header into sender and date, with a
+// second line for anything flagged, leaving more rows for the body.
+// ToggleRecipients shows the full header.
+func (v *EmailReaderView) SetCompactHeader(compact bool) {
+	v.compact = compact
+}
+
+// CompactHeader returns true if the header is currently squeezed
+func (v *EmailReaderView) CompactHeader() bool {
+	return v.compact && !v.showAllRecipients
+}
+
+// SetShowSize shows the message size next to the date in the header
+func (v *EmailReaderView) SetShowSize(show bool) {
+	v.showSize = show
+}
+
+// SetShortAddresses shows names alone in the header, without their
+// addresses, for mail with many recipients
+func (v *EmailReaderView) SetShortAddresses(short bool) {
+	v.shortAddresses = short
+}
+
+// SetSent shows when the message was sent instead of received, for mail
+// in the Sent folder
+func (v *EmailReaderView) SetSent(sent bool) {
+	v.sent = sent
+}
+
+// SetPipeOutput shows a command's output in place of the body until
+// ClearPipeOutput is called
+func (v *EmailReaderView) SetPipeOutput(command, output string) {
+	v.pipeCommand = command
+	v.pipeOutput = output
+	v.scrollY = 0
+	v.prepareContent()
+	v.findMatches()
+}
+
+// ClearPipeOutput brings back the email body
+func (v *EmailReaderView) ClearPipeOutput() {
+	v.pipeCommand = ""
+	v.pipeOutput = ""
+	v.scrollY = 0
+	v.prepareContent()
+	v.findMatches()
+}
+
+// HasPipeOutput returns true while command output replaces the body
+func (v *EmailReaderView) HasPipeOutput() bool {
+	return v.pipeCommand != ""
+}
+
+// RenderedBody returns the rendered body lines joined for an external pager
+func (v *EmailReaderView) RenderedBody() string {
+	return strings.Join(v.lines, "\n") + "\n"
+}
+
+// ToggleRecipients expands or collapses long To/Cc lists in the header
+func (v *EmailReaderView) ToggleRecipients() {
+	v.showAllRecipients = !v.showAllRecipients
+}
+
+// HasHiddenRecipients returns true if the header collapses any addresses
+func (v *EmailReaderView) HasHiddenRecipients() bool {
+	if v.email == nil || v.showAllRecipients {
+		return false
+	}
+	return len(v.email.To) > maxHeaderRecipients || len(v.email.CC) > maxHeaderRecipients
+}
+
 // HasAttachments returns true if the email has non-inline attachments
 func (v *EmailReaderView) HasAttachments() bool {
 	if v.email == nil {
@@ -209,19 +337,36 @@ func (v *EmailReaderView) nonInlineAttachmentCount() int {
 }
 
 func (v *EmailReaderView) prepareContent() {
+	// Command output is shown as-is, only wrapped to fit
+	if v.pipeCommand != "" {
+		v.lines = v.trimEmptyLines(v.wrapText(v.pipeOutput, v.contentWidth-4))
+		return
+	}
+
+	// Encrypted mail would only show armor or nothing at all
+	if v.email.Security == models.SecurityEncrypted {
+		v.lines = v.wrapText(encryptedNotice, v.contentWidth-4)
+		return
+	}
+
 	// Get body content
 	body := v.email.TextBody
-	if body == "" && v.email.HTMLBody != "" {
-		body = v.htmlToText(v.email.HTMLBody)
+	if (body == "" || v.showHTML) && v.email.HTMLBody != "" {
+		body = HTMLToText(v.email.HTMLBody)
 	}
 	if body == "" {
 		body = v.email.Preview
 	}
+	body = cleanupEncoding(body)
+	if v.email.Security == models.SecuritySigned {
+		body = stripClearsign(body)
+	}
 
-	// Try to render as markdown if it looks like markdown
-	if v.looksLikeMarkdown(body) && v.renderer != nil {
+	// Try to render as markdown if it looks like markdown, keeping the
+	// plain text when glamour fails or renders nothing
+	if v.renderer != nil && v.looksLikeMarkdown(body) {
 		rendered, err := v.renderer.Render(body)
-		if err == nil {
+		if err == nil && strings.TrimSpace(rendered) != "" {
 			body = rendered
 		}
 	}
@@ -297,21 +442,30 @@ func (v *EmailReaderView) trimEmptyLines(lines []string) []string {
 	return lines[start : end+1]
 }
 
-// looksLikeMarkdown checks if text appears to be markdown
+// markdownSignals are the constructs that suggest a body was written in
+// markdown. Italics need the asterisks to hug a word, so "5 * 3" or a
+// footnote star doesn't count.
+var markdownSignals = []*regexp.Regexp{
+	regexp.MustCompile(`(?m)^#{1,6} \S`),                          // Headers
+	regexp.MustCompile(`\*\*[^*\s][^*\n]*[^*\s]\*\*`),             // Bold
+	regexp.MustCompile(`(?m)(^|\s)\*[^*\s][^*\n]*[^*\s]\*(\s|$)`), // Italic
+	regexp.MustCompile(`\[[^\]]+\]\([^)]+\)`),                     // Links
+	regexp.MustCompile("(?m)^```"),                                // Code blocks
+	regexp.MustCompile(`(?m)^[-*] \S`),                            // Lists
+	regexp.MustCompile(`(?m)^\d+\. \S`),                           // Numbered lists
+}
+
+// looksLikeMarkdown checks if text appears to be markdown. Plain mail
+// often has one of the signals by accident, a list or a starred word, so
+// at least two different ones are needed.
 func (v *EmailReaderView) looksLikeMarkdown(text string) bool {
-	mdPatterns := []string{
-		`^#+ `,           // Headers
-		`\*\*[^*]+\*\*`,  // Bold
-		`\*[^*]+\*`,      // Italic
-		`\[[^\]]+\]\([^)]+\)`, // Links
-		"^```",           // Code blocks
-		`^- `,            // Lists
-		`^\d+\. `,        // Numbered lists
-	}
-
-	for _, pattern := range mdPatterns {
-		if matched, _ := regexp.MatchString(pattern, text); matched {
-			return true
+	found := 0
+	for _, re := range markdownSignals {
+		if re.MatchString(text) {
+			found++
+			if found >= 2 {
+				return true
+			}
 		}
 	}
 	return false
@@ -340,12 +494,16 @@ func (v *EmailReaderView) View() string {
 	b.WriteString(readerSubjectStyle.Render("◈ " + subject))
 	b.WriteString("\n\n")
 
-	// Body with scrolling
-	bodyHeight := v.height - 12
-	if bodyHeight < 1 {
-		bodyHeight = 1
+	// Label command output so it isn't mistaken for the message
+	if v.pipeCommand != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(readerColorDim).
+			Render("▸ output of " + v.pipeCommand + "  (esc for the message)"))
+		b.WriteString("\n\n")
 	}
 
+	// Body with scrolling
+	bodyHeight := v.bodyHeight()
+
 	endIdx := v.scrollY + bodyHeight
 	if endIdx > len(v.lines) {
 		endIdx = len(v.lines)
@@ -359,11 +517,17 @@ func (v *EmailReaderView) View() string {
 	if startIdx < endIdx {
 		visibleLines := v.lines[startIdx:endIdx]
 
-		// Style quoted lines differently
+		// Highlight search matches, and style quoted lines differently
+		matching := make(map[int]bool, len(v.matches))
+		for _, m := range v.matches {
+			matching[m] = true
+		}
 		styledLines := make([]string, len(visibleLines))
 		for i, line := range visibleLines {
 			trimmed := strings.TrimSpace(line)
-			if strings.HasPrefix(trimmed, ">") {
+			if lineIdx := startIdx + i; matching[lineIdx] {
+				styledLines[i] = v.highlightLine(line, lineIdx == v.matches[v.matchIdx])
+			} else if strings.HasPrefix(trimmed, ">") {
 				styledLines[i] = readerQuoteStyle.Render(line)
 			} else {
 				styledLines[i] = readerBodyStyle.Render(line)
@@ -392,7 +556,7 @@ func (v *EmailReaderView) View() string {
 		b.WriteString(v.renderAttachments())
 	}
 
-	// Center the content if wider than maxEmailWidth
+	// Center the content if wider than the width cap
 	content := b.String()
 	if v.width > v.contentWidth {
 		return lipgloss.Place(v.width, 0, lipgloss.Center, lipgloss.Top, content)
@@ -401,38 +565,93 @@ func (v *EmailReaderView) View() string {
 }
 
 func (v *EmailReaderView) renderHeader() string {
+	if v.CompactHeader() {
+		return v.renderCompactHeader()
+	}
+
 	var lines []string
 
-	// From
+	// From - shown even when missing, as automated mail sometimes is
+	from := v.email.FromDisplay()
 	if len(v.email.From) > 0 {
-		from := v.formatAddresses(v.email.From)
-		lines = append(lines,
-			readerLabelStyle.Render("▸ From")+
-				readerValueStyle.Render(from))
+		from = v.formatAddresses(v.email.From)
 	}
+	lines = append(lines,
+		readerLabelStyle.Render("▸ From")+
+			readerValueStyle.Render(from)+v.renderSentBy())
 
-	// To
-	if len(v.email.To) > 0 {
-		to := v.formatAddresses(v.email.To)
-		lines = append(lines,
-			readerLabelStyle.Render("▸ To")+
-				readerValueStyle.Render(to))
+	// To - always shown so BCC-only mail doesn't look addressed to nobody
+	to := v.formatRecipients(v.email.To)
+	if to == "" {
+		to = "(undisclosed recipients)"
 	}
+	lines = append(lines,
+		readerLabelStyle.Render("▸ To")+
+			readerValueStyle.Render(to))
 
 	// CC
 	if len(v.email.CC) > 0 {
-		cc := v.formatAddresses(v.email.CC)
+		cc := v.formatRecipients(v.email.CC)
 		lines = append(lines,
 			readerLabelStyle.Render("▸ cc")+
 				readerValueStyle.Render(cc))
 	}
 
-	// Date
-	date := v.email.ReceivedAt.Format("Mon, Jan 2, 2006 at 3:04 PM")
+	// Date, or the sent time for mail in the Sent folder
+	label := "▸ Date"
+	if v.sent && !v.email.SentAt.IsZero() {
+		label = "▸ Sent"
+	}
+	date := v.email.Time(v.sent).Format("Mon, Jan 2, 2006 at 3:04 PM")
+	if v.showSize && v.email.Size > 0 {
+		date += " · " + FormatSize(v.email.Size)
+	}
 	lines = append(lines,
-		readerLabelStyle.Render("▸ Date")+
+		readerLabelStyle.Render(label)+
 			readerValueStyle.Render(date))
 
+	// Which part is shown when they differ
+	if v.divergentParts {
+		part := "text"
+		other := "html"
+		if v.showHTML {
+			part, other = "html", "text"
+		}
+		lines = append(lines,
+			readerLabelStyle.Render("▸ Part")+
+				readerValueStyle.Render(part)+
+				lipgloss.NewStyle().Foreground(readerColorDim).Render("  (parts differ, H for "+other+")"))
+	}
+
+	// Priority set by the sender; normal and low aren't worth a line
+	if v.email.Priority == models.PriorityHigh {
+		lines = append(lines,
+			readerLabelStyle.Render("▸ !")+
+				lipgloss.NewStyle().Foreground(readerColorAccent).Bold(true).Render("High priority"))
+	}
+
+	// Signatures aren't verified, only reported
+	switch v.email.Security {
+	case models.SecuritySigned:
+		lines = append(lines,
+			readerLabelStyle.Render("▸ 🔒")+
+				readerValueStyle.Render("signed")+
+				lipgloss.NewStyle().Foreground(readerColorDim).Render("  (not verified)"))
+	case models.SecurityEncrypted:
+		lines = append(lines,
+			readerLabelStyle.Render("▸ 🔒")+
+				readerValueStyle.Render("encrypted")+
+				lipgloss.NewStyle().Foreground(readerColorDim).Render("  (cannot display)"))
+	}
+
+	// One-time code
+	if v.otp != "" {
+		code := lipgloss.NewStyle().Foreground(readerColorAccent).Bold(true).Render(v.otp)
+		lines = append(lines,
+			readerLabelStyle.Render("▸ Code")+code+
+				readerValueStyle.Render("  (y to copy)"))
+	}
+
 	headerWidth := v.contentWidth - 4
 	if headerWidth < 40 {
 		headerWidth = 40
@@ -440,14 +659,87 @@ func (v *EmailReaderView) renderHeader() string {
 	return readerHeaderStyle.Width(headerWidth).Render(strings.Join(lines, "\n"))
 }
 
+// renderSentBy names who sent the email when it was sent on the author's
+// behalf, and is empty otherwise
+func (v *EmailReaderView) renderSentBy() string {
+	sender, ok := v.email.SentOnBehalf()
+	if !ok {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(readerColorDim).Render(" (sent by " + sender.String() + ")")
+}
+
+// renderCompactHeader renders the sender and date on one line, and what
+// the full header would flag (priority, security, a code, differing
+// parts) on a second only when there is any
+func (v *EmailReaderView) renderCompactHeader() string {
+	dim := lipgloss.NewStyle().Foreground(readerColorDim)
+	accent := lipgloss.NewStyle().Foreground(readerColorAccent).Bold(true)
+
+	date := v.email.Time(v.sent).Format("Jan 2, 2006 3:04 PM")
+	lines := []string{
+		readerValueStyle.Render("▸ "+v.email.FromDisplay()) + v.renderSentBy() + dim.Render("  "+date),
+	}
+
+	var flags []string
+	if v.email.Priority == models.PriorityHigh {
+		flags = append(flags, accent.Render("! high priority"))
+	}
+	if v.email.Security != "" {
+		flags = append(flags, readerValueStyle.Render("🔒 "+v.email.Security))
+	}
+	if v.otp != "" {
+		flags = append(flags, readerValueStyle.Render("code ")+accent.Render(v.otp))
+	}
+	if v.divergentParts {
+		flags = append(flags, dim.Render("parts differ"))
+	}
+	if len(flags) > 0 {
+		lines = append(lines, "  "+strings.Join(flags, dim.Render(" · ")))
+	}
+
+	headerWidth := v.contentWidth - 4
+	if headerWidth < 40 {
+		headerWidth = 40
+	}
+	return readerHeaderStyle.Padding(0).Width(headerWidth).MaxWidth(headerWidth).Render(strings.Join(lines, "\n"))
+}
+
 func (v *EmailReaderView) formatAddresses(addrs []models.EmailAddress) string {
 	var parts []string
 	for _, addr := range addrs {
-		parts = append(parts, addr.String())
+		if v.shortAddresses {
+			parts = append(parts, addr.ShortName())
+		} else {
+			parts = append(parts, addr.String())
+		}
 	}
 	return strings.Join(parts, ", ")
 }
 
+// formatRecipients formats a To/Cc list, collapsing long lists to
+// "X, Y and N others" unless expanded. Group syntax such as
+// "undisclosed-recipients:;" arrives as addresses without an email and
+// is dropped.
+func (v *EmailReaderView) formatRecipients(addrs []models.EmailAddress) string {
+	var valid []models.EmailAddress
+	for _, addr := range addrs {
+		if addr.Email != "" {
+			valid = append(valid, addr)
+		}
+	}
+	if v.showAllRecipients || len(valid) <= maxHeaderRecipients {
+		return v.formatAddresses(valid)
+	}
+
+	shown := maxHeaderRecipients - 1
+	var parts []string
+	for _, addr := range valid[:shown] {
+		parts = append(parts, addr.ShortName())
+	}
+	return fmt.Sprintf("%s and %d others", strings.Join(parts, ", "), len(valid)-shown)
+}
+
 func (v *EmailReaderView) renderAttachments() string {
 	titleText := "◈ attachments"
 	if v.attachmentMode {
@@ -464,7 +756,7 @@ func (v *EmailReaderView) renderAttachments() string {
 		if att.IsInline {
 			continue
 		}
-		size := v.formatSize(att.Size)
+		size := FormatSize(att.Size)
 		text := fmt.Sprintf("  ◇ %s (%s)", att.Name, size)
 
 		if v.attachmentMode && idx == v.selectedAttachment {
@@ -484,7 +776,8 @@ func (v *EmailReaderView) renderAttachments() string {
 	return readerAttachmentStyle.Render(content)
 }
 
-func (v *EmailReaderView) formatSize(bytes int) string {
+// FormatSize formats a byte count as B, KB or MB
+func FormatSize(bytes int) string {
 	const (
 		KB = 1024
 		MB = KB * 1024
@@ -570,25 +863,33 @@ func (v *EmailReaderView) wrapText(text string, width int) []string {
 		}
 
 		// Handle pre-wrapped lines (from glamour)
-		if len(para) <= width {
+		if lipgloss.Width(para) <= width {
 			lines = append(lines, para)
 			continue
 		}
 
-		// Wrap long lines
-		words := strings.Fields(para)
+		// Wrap long lines, measuring display width rather than bytes so
+		// CJK and emoji wrap at the right column
+		var words []string
+		for _, word := range strings.Fields(para) {
+			words = append(words, v.breakWord(word, width)...)
+		}
 		if len(words) == 0 {
 			lines = append(lines, "")
 			continue
 		}
 
 		currentLine := words[0]
+		currentWidth := lipgloss.Width(currentLine)
 		for _, word := range words[1:] {
-			if len(currentLine)+1+len(word) <= width {
+			wordWidth := lipgloss.Width(word)
+			if currentWidth+1+wordWidth <= width {
 				currentLine += " " + word
+				currentWidth += 1 + wordWidth
 			} else {
 				lines = append(lines, currentLine)
 				currentLine = word
+				currentWidth = wordWidth
 			}
 		}
 		lines = append(lines, currentLine)
@@ -597,7 +898,21 @@ func (v *EmailReaderView) wrapText(text string, width int) []string {
 	return lines
 }
 
-func (v *EmailReaderView) htmlToText(html string) string {
+// breakWord splits a word wider than width into width-sized chunks.
+// Scripts like Chinese and Japanese have no spaces, so a whole sentence
+// arrives here as one "word". Chunks end on grapheme boundaries, so an
+// emoji built from several code points stays whole. Words carrying ANSI
+// escapes are left alone to avoid splitting a sequence.
+func (v *EmailReaderView) breakWord(word string, width int) []string {
+	if width <= 0 || lipgloss.Width(word) <= width || strings.Contains(word, "\x1b") {
+		return []string{word}
+	}
+	return strings.Split(ansi.Hardwrap(word, width, true), "\n")
+}
+
+// HTMLToText converts an HTML body to readable text with markdown-style
+// formatting
+func HTMLToText(html string) string {
 	text := html
 
 	// Remove style and script tags with content
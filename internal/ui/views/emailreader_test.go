@@ -0,0 +1,78 @@
+package views
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/the9x/anneal/internal/models"
+)
+
+func TestLooksLikeMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{"starred word", "I *really* mean it.", false},
+		{"several starred words", "This is *very* and *truly* urgent.", false},
+		{"arithmetic", "Total: 5 * 3 * 2 = 30", false},
+		{"footnote", "Terms apply*\n\n* see website", false},
+		{"plain list", "Shopping:\n- eggs\n- milk", false},
+		{"list and starred word", "Please *read* this\n- one\n- two", true},
+		{"header and bold", "# Release notes\n\n**Breaking** changes below", true},
+		{"link and code", "See [docs](https://example.com)\n\n```\ngo test\n```", true},
+	}
+	v := &EmailReaderView{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := v.looksLikeMarkdown(tt.text); got != tt.want {
+				t.Errorf("looksLikeMarkdown(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainAsterisksNotItalicized(t *testing.T) {
+	email := &models.Email{
+		Subject:  "Hello",
+		From:     []models.EmailAddress{{Name: "Ann", Email: "ann@example.com"}},
+		TextBody: "I *really* mean it, see you *tomorrow*.",
+	}
+	view := ansiRe.ReplaceAllString(NewEmailReaderView(email, 100, 40, 0).View(), "")
+	if !strings.Contains(view, "*really*") || !strings.Contains(view, "*tomorrow*") {
+		t.Errorf("asterisks in plain text were rendered as markdown:\n%s", view)
+	}
+}
+
+func TestWrapTextMixedWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  []string
+	}{
+		{"cjk without spaces", "日本語のメールです", 8, []string{"日本語の", "メールで", "す"}},
+		{"cjk odd width", "日本語のメール", 5, []string{"日本", "語の", "メー", "ル"}},
+		{"latin and cjk words", "hello 世界 again", 10, []string{"hello 世界", "again"}},
+		{"emoji", "ok 🎉🎉🎉 done", 6, []string{"ok", "🎉🎉🎉", "done"}},
+		{"emoji run", "🎉🎉🎉🎉", 5, []string{"🎉🎉", "🎉🎉"}},
+		{"skin tones", "👍🏽👍🏽👍🏽", 4, []string{"👍🏽👍🏽", "👍🏽"}},
+		{"zwj sequences", "👨‍👩‍👧👨‍👩‍👧", 3, []string{"👨‍👩‍👧", "👨‍👩‍👧"}},
+		{"ascii", "the quick brown fox", 10, []string{"the quick", "brown fox"}},
+	}
+	v := &EmailReaderView{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := v.wrapText(tt.text, tt.width)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("wrapText(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+			}
+			for _, line := range got {
+				if w := lipgloss.Width(line); w > tt.width {
+					t.Errorf("line %q is %d cells wide, over %d", line, w, tt.width)
+				}
+			}
+		})
+	}
+}
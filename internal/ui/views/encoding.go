@@ -0,0 +1,153 @@
+package views
+
+import (
+	"io"
+	"mime/quotedprintable"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// qpHighEscapeRe matches a quoted-printable escape of a byte above
+	// ASCII, such as =E2, which ordinary text has no reason to contain
+	qpHighEscapeRe = regexp.MustCompile(`=[89A-F][0-9A-F]`)
+
+	// qpSoftBreakRe matches a quoted-printable soft line break: a line
+	// ending in a single =, not a run of them like a Markdown underline
+	qpSoftBreakRe = regexp.MustCompile(`(?m)(?:^|[^=])=\r?$`)
+)
+
+// cp1252High holds the Windows-1252 characters for bytes 0x80-0x9F, where
+// it differs from Latin-1. The five bytes it leaves undefined keep their
+// Latin-1 code point.
+var cp1252High = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// cp1252Specials maps the characters in cp1252High back to their byte
+var cp1252Specials = func() map[rune]byte {
+	m := make(map[rune]byte, len(cp1252High))
+	for i, r := range cp1252High {
+		if r >= 0x100 {
+			m[r] = byte(0x80 + i)
+		}
+	}
+	return m
+}()
+
+// cleanupEncoding repairs bodies the server passed on less decoded than
+// it should: quoted-printable left in place (=20, =3D, soft line breaks)
+// and UTF-8 that was read as Windows-1252 somewhere along the way, which
+// turns ’ into â€™. Text without those artifacts comes back unchanged.
+func cleanupEncoding(text string) string {
+	if looksQuotedPrintable(text) {
+		text = decodeQuotedPrintable(text)
+	}
+	return fixMojibake(text)
+}
+
+// looksQuotedPrintable returns true if text carries both soft line breaks
+// and escapes of non-ASCII bytes. Either alone turns up in ordinary text,
+// as in x=20, a URL's ?id=12345 or a line of ===, which mustn't be decoded.
+func looksQuotedPrintable(text string) bool {
+	return qpSoftBreakRe.MatchString(text) && qpHighEscapeRe.MatchString(text)
+}
+
+// decodeQuotedPrintable decodes text, reading the bytes as Windows-1252
+// when they aren't UTF-8. The text is kept as it was if it doesn't decode.
+func decodeQuotedPrintable(text string) string {
+	decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(text)))
+	if err != nil {
+		return text
+	}
+	if utf8.Valid(decoded) {
+		return string(decoded)
+	}
+	return decodeCP1252(decoded)
+}
+
+// decodeCP1252 converts Windows-1252 bytes to a string
+func decodeCP1252(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		if c >= 0x80 && c <= 0x9F {
+			sb.WriteRune(cp1252High[c-0x80])
+		} else {
+			sb.WriteRune(rune(c))
+		}
+	}
+	return sb.String()
+}
+
+// cp1252Byte returns the Windows-1252 (or Latin-1) byte for r, if it has
+// one above ASCII
+func cp1252Byte(r rune) (byte, bool) {
+	if b, ok := cp1252Specials[r]; ok {
+		return b, true
+	}
+	if r >= 0x80 && r <= 0xFF {
+		return byte(r), true
+	}
+	return 0, false
+}
+
+// fixMojibake finds runs of characters that, turned back into Windows-1252
+// bytes, form valid UTF-8, and replaces them with what they spelled. Only
+// runs carrying a mojibake signature are touched, and the result is kept
+// only if it has fewer signatures than the text: a Latin-1 capital next to
+// curly punctuation, as in “CAFÉ”, also forms valid UTF-8 but was never
+// garbled.
+func fixMojibake(text string) string {
+	before := mojibakeSignatures(text)
+	if before == 0 {
+		return text
+	}
+
+	var out strings.Builder
+	var run []byte
+	var runText strings.Builder
+	flush := func() {
+		if len(run) >= 2 && utf8.Valid(run) && mojibakeSignatures(runText.String()) > 0 {
+			out.Write(run)
+		} else {
+			out.WriteString(runText.String())
+		}
+		run = run[:0]
+		runText.Reset()
+	}
+
+	for _, r := range text {
+		if b, ok := cp1252Byte(r); ok {
+			run = append(run, b)
+			runText.WriteRune(r)
+			continue
+		}
+		flush()
+		out.WriteRune(r)
+	}
+	flush()
+
+	if fixed := out.String(); mojibakeSignatures(fixed) < before {
+		return fixed
+	}
+	return text
+}
+
+// mojibakeSignatures counts the telltale pairs UTF-8 leaves when read as
+// Windows-1252: "â€", which starts the curly quotes and dashes, and Ã or Â
+// followed by a character standing for a continuation byte
+func mojibakeSignatures(text string) int {
+	n := strings.Count(text, "â€")
+	var prev rune
+	for _, r := range text {
+		if prev == 'Ã' || prev == 'Â' {
+			if b, ok := cp1252Byte(r); ok && b <= 0xBF {
+				n++
+			}
+		}
+		prev = r
+	}
+	return n
+}
@@ -0,0 +1,46 @@
+package views
+
+import "testing"
+
+func TestCleanupEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"apostrophe", "itâ€™s here", "it’s here"},
+		{"curly quotes", "â€œHiâ€\u009d she said", "“Hi” she said"},
+		{"dash", "2019â€“2020", "2019–2020"},
+		{"accented", "cafÃ© and crÃ¨me brÃ»lÃ©e", "café and crème brûlée"},
+		{"capital", "Ã‰tÃ©", "Été"},
+		{"pound", "Â£5", "£5"},
+		{"mixed with clean text", "CAFÉ” but itâ€™s", "CAFÉ” but it’s"},
+		{"quoted-printable", "caf=C3=A9 =3D ok=\r\nmore", "café = okmore"},
+		{"quoted-printable cp1252", "=93quoted=94 text=\r\nhere", "“quoted” texthere"},
+
+		// Valid text that must come back unchanged
+		{"capital in quotes", "“CAFÉ”", "“CAFÉ”"},
+		{"capital before ellipsis", "CAFÉ…", "CAFÉ…"},
+		{"accents", "naïve résumé, déjà vu", "naïve résumé, déjà vu"},
+		{"nordic", "Ångström", "Ångström"},
+		{"ascii", "plain text = fine", "plain text = fine"},
+		{"emoji", "done ✅ 🎉", "done ✅ 🎉"},
+		{"assignments", "Set x=10, y=20 and z=30", "Set x=10, y=20 and z=30"},
+		{"url", "https://example.com/?id=12345&ts=16000&v=20", "https://example.com/?id=12345&ts=16000&v=20"},
+		{"markdown underlines", "Title\n=====\n\nPart\n===\nx=20", "Title\n=====\n\nPart\n===\nx=20"},
+		{"soft breaks without escapes", "long line=\nwrapped=\nhere", "long line=\nwrapped=\nhere"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cleanupEncoding(tt.in); got != tt.want {
+				t.Errorf("cleanupEncoding(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCP1252(t *testing.T) {
+	if got, want := decodeCP1252([]byte{0x93, 'a', 0x94, 0x80, 0xE9, 0x81}), "“a”€é\u0081"; got != want {
+		t.Errorf("decodeCP1252 = %q, want %q", got, want)
+	}
+}
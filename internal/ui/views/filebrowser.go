@@ -0,0 +1,257 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/the9x/anneal/internal/ui/theme"
+)
+
+var (
+	fbTitleStyle    = lipgloss.NewStyle().Foreground(theme.Primary).Bold(true)
+	fbDirStyle      = lipgloss.NewStyle().Foreground(theme.Primary)
+	fbFileStyle     = lipgloss.NewStyle().Foreground(theme.Secondary)
+	fbSelectedStyle = lipgloss.NewStyle().Foreground(theme.Primary).Background(theme.BgSelect)
+	fbDimStyle      = lipgloss.NewStyle().Foreground(theme.Dim)
+)
+
+// fileEntry is a file or directory listed in the browser
+type fileEntry struct {
+	name string
+	dir  bool
+	size int64
+}
+
+// FileBrowserView lets the user walk the filesystem and pick a file, for
+// attaching to a message
+type FileBrowserView struct {
+	dir        string
+	entries    []fileEntry
+	selected   int
+	showHidden bool
+	marked     map[string]bool // files already picked, shown checked
+	err        error
+
+	chosen string
+	closed bool
+
+	width  int
+	height int
+}
+
+// NewFileBrowserView opens the browser in dir, or the home directory if
+// dir can't be read. marked lists files to show as already picked.
+func NewFileBrowserView(dir string, marked []string, width, height int) *FileBrowserView {
+	v := &FileBrowserView{
+		marked: make(map[string]bool, len(marked)),
+		width:  width,
+		height: height,
+	}
+	for _, p := range marked {
+		v.marked[p] = true
+	}
+	if dir == "" || v.open(dir) != nil {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = "/"
+		}
+		v.err = v.open(home)
+	}
+	return v
+}
+
+// open lists dir, directories first and then files, each by name
+func (v *FileBrowserView) open(dir string) error {
+	items, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var entries []fileEntry
+	for _, item := range items {
+		if !v.showHidden && strings.HasPrefix(item.Name(), ".") {
+			continue
+		}
+		e := fileEntry{name: item.Name(), dir: item.IsDir()}
+		// Follow symlinks so linked directories can be entered
+		if info, err := os.Stat(filepath.Join(dir, item.Name())); err == nil {
+			e.dir = info.IsDir()
+			e.size = info.Size()
+		}
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].dir != entries[j].dir {
+			return entries[i].dir
+		}
+		return strings.ToLower(entries[i].name) < strings.ToLower(entries[j].name)
+	})
+
+	v.dir = dir
+	v.entries = entries
+	v.selected = 0
+	v.err = nil
+	return nil
+}
+
+// Dir returns the directory being shown
+func (v *FileBrowserView) Dir() string {
+	return v.dir
+}
+
+// Chosen returns the file picked with enter, if any
+func (v *FileBrowserView) Chosen() (string, bool) {
+	return v.chosen, v.chosen != ""
+}
+
+// Closed returns true once the browser was closed without a pick
+func (v *FileBrowserView) Closed() bool {
+	return v.closed
+}
+
+// SetSize updates the view dimensions
+func (v *FileBrowserView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
+
+// Update moves through the listing: enter opens a directory or picks a
+// file, backspace goes up a level, ~ goes home and . shows hidden files
+func (v *FileBrowserView) Update(msg tea.Msg) (*FileBrowserView, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return v, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		if v.selected > 0 {
+			v.selected--
+		}
+	case "down", "j":
+		if v.selected < len(v.entries)-1 {
+			v.selected++
+		}
+	case "g", "home":
+		v.selected = 0
+	case "G", "end":
+		v.selected = max(len(v.entries)-1, 0)
+	case "enter", "right", "l":
+		if v.selected >= len(v.entries) {
+			break
+		}
+		e := v.entries[v.selected]
+		path := filepath.Join(v.dir, e.name)
+		if !e.dir {
+			v.chosen = path
+			break
+		}
+		if err := v.open(path); err != nil {
+			v.err = err
+		}
+	case "backspace", "left", "h":
+		v.up()
+	case "~":
+		if home, err := os.UserHomeDir(); err == nil {
+			if err := v.open(home); err != nil {
+				v.err = err
+			}
+		}
+	case ".":
+		v.showHidden = !v.showHidden
+		name := ""
+		if v.selected < len(v.entries) {
+			name = v.entries[v.selected].name
+		}
+		if err := v.open(v.dir); err != nil {
+			v.err = err
+		}
+		v.selectName(name)
+	case "esc", "q", "ctrl+o":
+		v.closed = true
+	}
+	return v, nil
+}
+
+// up shows the parent directory with the one just left selected
+func (v *FileBrowserView) up() {
+	parent := filepath.Dir(v.dir)
+	if parent == v.dir {
+		return
+	}
+	left := filepath.Base(v.dir)
+	if err := v.open(parent); err != nil {
+		v.err = err
+		return
+	}
+	v.selectName(left)
+}
+
+// selectName selects the entry with the given name, if it's listed
+func (v *FileBrowserView) selectName(name string) {
+	for i, e := range v.entries {
+		if e.name == name {
+			v.selected = i
+			return
+		}
+	}
+}
+
+// View renders the listing, scrolled to keep the selection in view
+func (v *FileBrowserView) View() string {
+	var b strings.Builder
+	b.WriteString(fbTitleStyle.Render("◇ attach a file"))
+	b.WriteString("\n")
+	b.WriteString(fbDimStyle.MaxWidth(v.width - 4).Render(v.dir))
+	b.WriteString("\n\n")
+
+	if v.err != nil {
+		b.WriteString(lipgloss.NewStyle().Foreground(theme.Accent).Render("✗ "+v.err.Error()) + "\n")
+	}
+	if len(v.entries) == 0 {
+		b.WriteString(fbDimStyle.Render("  (empty)") + "\n")
+	}
+
+	rows := max(v.height-7, 3)
+	start := 0
+	if v.selected >= rows {
+		start = v.selected - rows + 1
+	}
+	end := min(start+rows, len(v.entries))
+	for i := start; i < end; i++ {
+		e := v.entries[i]
+		style := fbFileStyle
+		line := "    " + e.name
+		switch {
+		case e.dir:
+			style = fbDirStyle
+			line = "  ▸ " + e.name + "/"
+		case v.marked[filepath.Join(v.dir, e.name)]:
+			line = "  ✓ " + e.name
+		}
+		if !e.dir {
+			line += "  " + FormatSize(int(e.size))
+		}
+		if i == v.selected {
+			line = "▶" + line[1:]
+			style = fbSelectedStyle
+		}
+		b.WriteString(style.MaxWidth(v.width-4).Render(line) + "\n")
+	}
+
+	hidden := "show"
+	if v.showHidden {
+		hidden = "hide"
+	}
+	b.WriteString("\n" + fbDimStyle.Render(fmt.Sprintf("enter: open/attach  ←: up  ~: home  .: %s hidden  esc: close", hidden)))
+
+	return lipgloss.NewStyle().
+		Width(v.width).
+		Padding(1, 2).
+		Render(b.String())
+}
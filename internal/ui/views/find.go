@@ -0,0 +1,143 @@
+package views
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ansiRe matches the escape codes glamour leaves in rendered lines
+var ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+var (
+	readerMatchStyle = lipgloss.NewStyle().
+				Foreground(readerColorBg).
+				Background(readerColorSecondary)
+
+	readerCurrentMatchStyle = lipgloss.NewStyle().
+				Foreground(readerColorBg).
+				Background(readerColorAccent).
+				Bold(true)
+)
+
+// Find searches the body for term, case-insensitively, and scrolls to the
+// first match at or below the current position. It returns the number of
+// matching lines.
+func (v *EmailReaderView) Find(term string) int {
+	v.findTerm = term
+	v.findMatches()
+	v.matchIdx = 0
+	for i, line := range v.matches {
+		if line >= v.scrollY {
+			v.matchIdx = i
+			break
+		}
+	}
+	v.scrollToMatch()
+	return len(v.matches)
+}
+
+// NextMatch scrolls to the next match, wrapping around at the end
+func (v *EmailReaderView) NextMatch() {
+	if len(v.matches) == 0 {
+		return
+	}
+	v.matchIdx = (v.matchIdx + 1) % len(v.matches)
+	v.scrollToMatch()
+}
+
+// PrevMatch scrolls to the previous match, wrapping around at the start
+func (v *EmailReaderView) PrevMatch() {
+	if len(v.matches) == 0 {
+		return
+	}
+	v.matchIdx = (v.matchIdx - 1 + len(v.matches)) % len(v.matches)
+	v.scrollToMatch()
+}
+
+// ClearFind removes the search and its highlights
+func (v *EmailReaderView) ClearFind() {
+	v.findTerm = ""
+	v.matches = nil
+	v.matchIdx = 0
+}
+
+// HasFind returns true while a search is active
+func (v *EmailReaderView) HasFind() bool {
+	return v.findTerm != ""
+}
+
+// FindStatus describes the search position, like "3/12"
+func (v *EmailReaderView) FindStatus() string {
+	if len(v.matches) == 0 {
+		return "no matches"
+	}
+	return fmt.Sprintf("%d/%d", v.matchIdx+1, len(v.matches))
+}
+
+// findMatches records the lines containing the search term. Called again
+// when the body is rewrapped.
+func (v *EmailReaderView) findMatches() {
+	v.matches = nil
+	if v.findTerm == "" {
+		return
+	}
+	term := strings.ToLower(v.findTerm)
+	for i, line := range v.lines {
+		if strings.Contains(strings.ToLower(ansiRe.ReplaceAllString(line, "")), term) {
+			v.matches = append(v.matches, i)
+		}
+	}
+	if v.matchIdx >= len(v.matches) {
+		v.matchIdx = 0
+	}
+}
+
+// scrollToMatch brings the current match into view, leaving a little
+// context above it
+func (v *EmailReaderView) scrollToMatch() {
+	if len(v.matches) == 0 {
+		return
+	}
+	line := v.matches[v.matchIdx]
+	if line < v.scrollY || line >= v.scrollY+v.bodyHeight() {
+		v.SetScrollOffset(line - 2)
+	}
+}
+
+// highlightLine renders a body line with each occurrence of the search
+// term highlighted. Matching lines lose their markdown styling so the
+// match offsets line up with what's shown.
+func (v *EmailReaderView) highlightLine(line string, current bool) string {
+	plain := ansiRe.ReplaceAllString(line, "")
+	lower := strings.ToLower(plain)
+	term := strings.ToLower(v.findTerm)
+
+	// Offsets only line up when lowercasing kept the length
+	if len(lower) != len(plain) {
+		return readerBodyStyle.Render(plain)
+	}
+
+	style := readerMatchStyle
+	if current {
+		style = readerCurrentMatchStyle
+	}
+
+	var b strings.Builder
+	rest := 0
+	for {
+		idx := strings.Index(lower[rest:], term)
+		if idx < 0 {
+			break
+		}
+		start := rest + idx
+		end := start + len(term)
+		b.WriteString(readerBodyStyle.Render(plain[rest:start]))
+		b.WriteString(style.Render(plain[start:end]))
+		rest = end
+	}
+	b.WriteString(readerBodyStyle.Render(plain[rest:]))
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package views
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// hrefRe matches the target of a link in HTML
+	hrefRe = regexp.MustCompile(`(?i)\bhref\s*=\s*["']\s*(https?://[^"'\s]+)`)
+
+	// urlRe matches a web address in plain text
+	urlRe = regexp.MustCompile(`https?://[^\s<>"'\x60]+`)
+)
+
+// extractLinks returns the web links in an email, each once, in the order
+// they first appear. Links in the HTML part come before any only found in
+// the text part.
+func extractLinks(textBody, htmlBody string) []string {
+	seen := make(map[string]bool)
+	var links []string
+	add := func(link string) {
+		// Sentence punctuation after a bare URL isn't part of it
+		link = strings.TrimRight(link, ".,;:!?)]}")
+		if link != "" && !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+
+	for _, m := range hrefRe.FindAllStringSubmatch(htmlBody, -1) {
+		add(html.UnescapeString(m[1]))
+	}
+	for _, link := range urlRe.FindAllString(textBody, -1) {
+		add(link)
+	}
+	return links
+}
+
+// Links returns the web links in the open email
+func (v *EmailReaderView) Links() []string {
+	if v.email == nil {
+		return nil
+	}
+	return extractLinks(v.email.TextBody, v.email.HTMLBody)
+}
+
+// HasLinks returns true if the open email links anywhere on the web
+func (v *EmailReaderView) HasLinks() bool {
+	return len(v.Links()) > 0
+}
@@ -7,15 +7,16 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/the9x/anneal/internal/models"
+	"github.com/the9x/anneal/internal/ui/theme"
 )
 
 // anneal brand colors
 var (
-	mbColorPrimary   = lipgloss.Color("#d4d2e3")
-	mbColorSecondary = lipgloss.Color("#9795b5")
-	mbColorDim       = lipgloss.Color("#5a5880")
-	mbColorBg        = lipgloss.Color("#1d1d40")
-	mbColorBgSelect  = lipgloss.Color("#2d2d5a")
+	mbColorPrimary   = theme.Primary
+	mbColorSecondary = theme.Secondary
+	mbColorDim       = theme.Dim
+	mbColorBg        = theme.Bg
+	mbColorBgSelect  = theme.BgSelect
 
 	mailboxTitleStyle = lipgloss.NewStyle().
 				Foreground(mbColorSecondary).
@@ -58,14 +59,20 @@ func NewMailboxView(mailboxes []models.Mailbox) *MailboxView {
 
 	roleOrder := map[string]int{
 		"inbox":   0,
-		"drafts":  1,
-		"sent":    2,
-		"archive": 3,
-		"trash":   4,
-		"junk":    5,
+		"snoozed": 1,
+		"drafts":  2,
+		"sent":    3,
+		"archive": 4,
+		"trash":   5,
+		"junk":    6,
 	}
 
 	sort.Slice(sorted, func(i, j int) bool {
+		// Virtual folders go after everything on the server
+		if sorted[i].IsVirtual() != sorted[j].IsVirtual() {
+			return sorted[j].IsVirtual()
+		}
+
 		ri, oki := roleOrder[sorted[i].Role]
 		rj, okj := roleOrder[sorted[j].Role]
 
@@ -157,10 +164,7 @@ func (v *MailboxView) renderMailbox(mb models.Mailbox, selected bool) string {
 	icon := v.getIcon(mb.Role, selected)
 
 	// Truncate name if too long
-	maxNameLen := 12
-	if len(name) > maxNameLen {
-		name = name[:maxNameLen-1] + "…"
-	}
+	name = truncate(name, 12)
 
 	// Format unread count
 	var countStr string
@@ -201,6 +205,14 @@ func (v *MailboxView) getIcon(role string, selected bool) string {
 		icon = "▽"
 	case "junk":
 		icon = "⊘"
+	case "snoozed":
+		icon = "◐"
+	case "followup":
+		icon = "☐"
+	case "scheduled":
+		icon = "◷"
+	case "outbox":
+		icon = "✗"
 	default:
 		icon = "◆"
 	}
@@ -0,0 +1,94 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RowAt returns the thread on line y of the list, counting from the
+// column header, if one is shown there
+func (v *ThreadListView) RowAt(y int) (int, bool) {
+	visibleRows := max(v.height-3, 1)
+	if y < 1 || y > visibleRows {
+		return 0, false
+	}
+	i := v.offset + y - 1
+	if i >= len(v.threads) {
+		return 0, false
+	}
+	return i, true
+}
+
+// bodyTop returns the line of the view the body starts on, below the
+// header, subject and any command output label
+func (v *EmailReaderView) bodyTop() int {
+	top := lipgloss.Height(v.renderHeader()) + 2
+	if v.pipeCommand != "" {
+		top += 2
+	}
+	return top
+}
+
+// LinkAt returns the web link under column x of line y of the view
+func (v *EmailReaderView) LinkAt(x, y int) (string, bool) {
+	if v.email == nil {
+		return "", false
+	}
+	row := y - v.bodyTop()
+	line := v.scrollY + row
+	if row < 0 || row >= v.bodyHeight() || line >= len(v.lines) {
+		return "", false
+	}
+
+	if v.width > v.contentWidth {
+		x -= (v.width - v.contentWidth) / 2
+	}
+	text := v.lines[line]
+	for _, span := range urlRe.FindAllStringIndex(text, -1) {
+		link := trimLink(text[span[0]:span[1]])
+		start := lipgloss.Width(text[:span[0]])
+		if x >= start && x < start+lipgloss.Width(link) {
+			return link, true
+		}
+	}
+	return "", false
+}
+
+// trimLink cuts styling codes and sentence punctuation off the end of a
+// link found in a rendered line
+func trimLink(link string) string {
+	if i := strings.IndexByte(link, 0x1b); i >= 0 {
+		link = link[:i]
+	}
+	return strings.TrimRight(link, ".,;:!?)]}")
+}
+
+// AttachmentAt returns the attachment listed on line y of the view, by its
+// position among the attachments shown
+func (v *EmailReaderView) AttachmentAt(y int) (int, bool) {
+	if !v.HasAttachments() {
+		return 0, false
+	}
+	lines := strings.Split(v.View(), "\n")
+	for i, line := range lines {
+		if strings.Contains(line, "◈ attachments") {
+			n := y - i - 1
+			if n >= 0 && n < v.nonInlineAttachmentCount() {
+				return n, true
+			}
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// SelectAttachment switches to attachment mode with the i-th attachment
+// selected
+func (v *EmailReaderView) SelectAttachment(i int) {
+	if !v.HasAttachments() || i < 0 || i >= v.nonInlineAttachmentCount() {
+		return
+	}
+	v.attachmentMode = true
+	v.selectedAttachment = i
+}
@@ -0,0 +1,49 @@
+package views
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/the9x/anneal/internal/models"
+)
+
+func TestLinkAtHitsOnlyTheLink(t *testing.T) {
+	email := &models.Email{
+		Subject:  "Links",
+		TextBody: "The agenda is at https://example.com/agenda, see you there",
+	}
+	v := NewEmailReaderView(email, 80, 40, 0)
+
+	y, col := -1, 0
+	for i, line := range v.lines {
+		plain := ansiRe.ReplaceAllString(line, "")
+		if j := strings.Index(plain, "https://"); j >= 0 {
+			y, col = v.bodyTop()+i, len(plain[:j])
+			break
+		}
+	}
+	if y < 0 {
+		t.Fatal("link not rendered")
+	}
+
+	tests := []struct {
+		name string
+		x    int
+		want bool
+	}{
+		{"first character", col, true},
+		{"last character", col + len("https://example.com/agenda") - 1, true},
+		{"trailing comma", col + len("https://example.com/agenda"), false},
+		{"text before", col - 3, false},
+		{"text after", col + 40, false},
+	}
+	for _, tt := range tests {
+		link, ok := v.LinkAt(tt.x, y)
+		if ok != tt.want {
+			t.Errorf("%s: LinkAt = %q, %v, want hit %v", tt.name, link, ok, tt.want)
+		}
+		if ok && link != "https://example.com/agenda" {
+			t.Errorf("%s: LinkAt = %q", tt.name, link)
+		}
+	}
+}
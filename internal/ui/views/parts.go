@@ -0,0 +1,68 @@
+package views
+
+import (
+	"strings"
+	"unicode"
+)
+
+// partsDifferAt is the share of words the text and HTML parts must have in
+// common to count as the same message. Below it the reader offers both.
+const partsDifferAt = 0.7
+
+// HasDivergentParts returns true if the email has text and HTML parts
+// whose content differs, so the reader lets you pick between them
+func (v *EmailReaderView) HasDivergentParts() bool {
+	return v.divergentParts
+}
+
+// ToggleBodyPart switches the body between the text part and the HTML part
+// converted to text. It does nothing unless the parts differ.
+func (v *EmailReaderView) ToggleBodyPart() {
+	if !v.divergentParts {
+		return
+	}
+	v.showHTML = !v.showHTML
+	v.scrollY = 0
+	v.prepareContent()
+	v.findMatches()
+}
+
+// partsDiffer compares the words of the text part with those of the HTML
+// part. Converting HTML changes layout and link syntax but keeps the
+// words, so only a real difference in content pushes the overlap down.
+func partsDiffer(text, html string) bool {
+	if strings.TrimSpace(text) == "" || strings.TrimSpace(html) == "" {
+		return false
+	}
+	a := wordSet(text)
+	b := wordSet(HTMLToText(html))
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) != len(b)
+	}
+
+	common := 0
+	for w := range a {
+		if b[w] {
+			common++
+		}
+	}
+	union := len(a) + len(b) - common
+	return float64(common)/float64(union) < partsDifferAt
+}
+
+// wordSet returns the distinct lowercased words of s, ignoring URLs
+func wordSet(s string) map[string]bool {
+	words := make(map[string]bool)
+	for _, field := range strings.Fields(s) {
+		if strings.Contains(field, "://") {
+			continue
+		}
+		w := strings.ToLower(strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}))
+		if w != "" {
+			words[w] = true
+		}
+	}
+	return words
+}
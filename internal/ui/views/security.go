@@ -0,0 +1,44 @@
+package views
+
+import (
+	"strings"
+)
+
+// encryptedNotice stands in for the body of encrypted mail, which anneal
+// can't decrypt
+const encryptedNotice = "This message is encrypted and can't be displayed here. " +
+	"Open it in a mail client that has your key to read it."
+
+// stripClearsign returns the signed text of an inline PGP signed message,
+// without the armor lines, hash header and signature block around it.
+// Bodies that aren't clearsigned come back unchanged.
+func stripClearsign(body string) string {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+
+	start := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "-----BEGIN PGP SIGNED MESSAGE-----" {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return body
+	}
+
+	// Armor headers such as "Hash: SHA256" run until the first blank line
+	i := start + 1
+	for i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+		i++
+	}
+
+	var out []string
+	for i++; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "-----BEGIN PGP SIGNATURE-----" {
+			break
+		}
+		// Lines starting with a dash are escaped with "- " when signing
+		out = append(out, strings.TrimPrefix(lines[i], "- "))
+	}
+	return strings.Join(out, "\n")
+}
@@ -3,8 +3,10 @@ package views
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/the9x/anneal/internal/ui/theme"
 )
 
 // Thread represents a group of emails in a conversation
@@ -17,15 +19,27 @@ type Thread struct {
 	EmailCnt  int
 	UnreadCnt int
 	Expanded  bool
+	Pinned    bool
+	Todo      bool // marked for follow-up
+	Marked    bool // part of the multi-selection
+	Size      int  // total size of the loaded emails in bytes
+	Leaving   bool // archived, shown struck through until removed
+	Failed    bool // in the outbox after failing to send
+	New       bool // arrived with the last refresh
+	Priority  bool // sent as high priority
+
+	HasAttachment bool // any email in the thread has attachments
 }
 
 // anneal brand colors
 var (
-	thColorPrimary   = lipgloss.Color("#d4d2e3")
-	thColorSecondary = lipgloss.Color("#9795b5")
-	thColorDim       = lipgloss.Color("#5a5880")
-	thColorBg        = lipgloss.Color("#1d1d40")
-	thColorBgSelect  = lipgloss.Color("#2d2d5a")
+	thColorPrimary   = theme.Primary
+	thColorSecondary = theme.Secondary
+	thColorDim       = theme.Dim
+	thColorBg        = theme.Bg
+	thColorBgSelect  = theme.BgSelect
+	thColorAccent    = theme.Accent
+	thColorBgMarked  = theme.BgLight
 
 	threadHeaderStyle = lipgloss.NewStyle().
 				Foreground(thColorDim).
@@ -42,9 +56,27 @@ var (
 				Bold(true).
 				Padding(0, 1)
 
+	threadRowMarkedStyle = lipgloss.NewStyle().
+				Foreground(thColorPrimary).
+				Background(thColorBgMarked).
+				Padding(0, 1)
+
+	threadRowLeavingStyle = lipgloss.NewStyle().
+				Foreground(thColorDim).
+				Strikethrough(true).
+				Padding(0, 1)
+
 	threadUnreadDotStyle = lipgloss.NewStyle().
 				Foreground(thColorPrimary)
 
+	threadFailedStyle = lipgloss.NewStyle().
+				Foreground(thColorAccent).
+				Bold(true)
+
+	threadNewStyle = lipgloss.NewStyle().
+			Foreground(thColorSecondary).
+			Bold(true)
+
 	threadFromStyle = lipgloss.NewStyle().
 			Foreground(thColorPrimary)
 
@@ -68,13 +100,23 @@ var (
 	threadExpandedStyle = lipgloss.NewStyle().
 				Foreground(thColorPrimary)
 
+	threadMatchStyle = lipgloss.NewStyle().
+				Foreground(thColorAccent).
+				Bold(true)
+
 	threadEmptyStyle = lipgloss.NewStyle().
 				Foreground(thColorDim).
 				Padding(2).
 				Align(lipgloss.Center)
 )
 
-const maxListWidth = 100
+// capWidth limits width to max, where a max of 0 means no cap
+func capWidth(width, max int) int {
+	if max > 0 && width > max {
+		return max
+	}
+	return width
+}
 
 // Column width constraints
 const (
@@ -92,22 +134,26 @@ type ThreadListView struct {
 	offset       int
 	width        int
 	contentWidth int
+	maxWidth     int // content width cap, 0 for none
 	height       int
+	highlight    string // filter text to highlight in rows
+	showSize     bool   // show the size column in place of the date
+	columns      ColumnLayout
 }
 
-// NewThreadListView creates a new thread list view
-func NewThreadListView(width, height int) *ThreadListView {
-	contentWidth := width
-	if contentWidth > maxListWidth {
-		contentWidth = maxListWidth
-	}
+// NewThreadListView creates a new thread list view. maxWidth caps the
+// row width; 0 uses the full width.
+func NewThreadListView(width, height, maxWidth int) *ThreadListView {
+	contentWidth := capWidth(width, maxWidth)
 	return &ThreadListView{
 		threads:      []Thread{},
 		selected:     0,
 		offset:       0,
 		width:        width,
 		contentWidth: contentWidth,
+		maxWidth:     maxWidth,
 		height:       height,
+		columns:      DefaultColumnLayout(),
 	}
 }
 
@@ -116,6 +162,17 @@ func (v *ThreadListView) UpdateThreads(threads []Thread) {
 	v.threads = threads
 }
 
+// SetHighlight sets the filter text highlighted in sender and subject
+func (v *ThreadListView) SetHighlight(query string) {
+	v.highlight = strings.TrimSpace(query)
+}
+
+// SetShowSize swaps the date column for thread sizes, used while the
+// list is sorted by size
+func (v *ThreadListView) SetShowSize(show bool) {
+	v.showSize = show
+}
+
 // Select sets the selected thread
 func (v *ThreadListView) Select(index int) {
 	if index >= 0 && index < len(v.threads) {
@@ -135,30 +192,31 @@ func (v *ThreadListView) Select(index int) {
 func (v *ThreadListView) SetSize(width, height int) {
 	v.width = width
 	v.height = height
-	v.contentWidth = width
-	if v.contentWidth > maxListWidth {
-		v.contentWidth = maxListWidth
-	}
+	v.contentWidth = capWidth(width, v.maxWidth)
 }
 
 // calculateColumnWidths returns responsive from and subject widths
 func (v *ThreadListView) calculateColumnWidths() (fromWidth, subjectWidth int) {
-	// Fixed columns: date (10) + count (4) + spacing (4) = 18
-	fixedWidth := dateWidth + countWidth + 4
+	// Fixed columns: date (10) + count (4) + spacing (4) = 18, and the
+	// size column when shown beside the date
+	fixedWidth := v.dateColumnWidth() + countWidth + 4
+	if v.sizeColumn() {
+		fixedWidth += sizeWidth + 1
+	}
 	flexibleWidth := v.contentWidth - fixedWidth
 
-	if flexibleWidth < minFromWidth+minSubjWidth {
+	if flexibleWidth < v.columns.MinFrom+minSubjWidth {
 		// Terminal too narrow, use minimums
-		return minFromWidth, minSubjWidth
+		return v.columns.MinFrom, minSubjWidth
 	}
 
-	// Allocate flexible space: 25% to from, 75% to subject
-	fromWidth = flexibleWidth / 4
-	if fromWidth < minFromWidth {
-		fromWidth = minFromWidth
+	// Allocate flexible space: by default 25% to from, 75% to subject
+	fromWidth = flexibleWidth * v.columns.FromPercent / 100
+	if fromWidth < v.columns.MinFrom {
+		fromWidth = v.columns.MinFrom
 	}
-	if fromWidth > maxFromWidth {
-		fromWidth = maxFromWidth
+	if fromWidth > v.columns.MaxFrom {
+		fromWidth = v.columns.MaxFrom
 	}
 
 	subjectWidth = flexibleWidth - fromWidth
@@ -169,10 +227,20 @@ func (v *ThreadListView) calculateColumnWidths() (fromWidth, subjectWidth int) {
 	return fromWidth, subjectWidth
 }
 
+// sizeColumn returns true if sizes get their own column; while sorting by
+// size they take the date's place instead
+func (v *ThreadListView) sizeColumn() bool {
+	return v.columns.Size && !v.showSize
+}
+
 // View renders the thread list
 func (v *ThreadListView) View() string {
 	if len(v.threads) == 0 {
-		emptyMsg := threadEmptyStyle.Render("◇ No messages in this folder")
+		text := "◇ No messages in this folder"
+		if v.highlight != "" {
+			text = "◇ No messages match the filter"
+		}
+		emptyMsg := threadEmptyStyle.Render(text)
 		return lipgloss.Place(v.width, v.height, lipgloss.Center, lipgloss.Center, emptyMsg)
 	}
 
@@ -180,15 +248,21 @@ func (v *ThreadListView) View() string {
 
 	// Calculate responsive column widths
 	fromW, subjectW := v.calculateColumnWidths()
+	dateW := v.dateColumnWidth()
 
 	// Render header
+	lastColumn := "date"
+	if v.showSize {
+		lastColumn = "size ▾"
+	}
+	if v.sizeColumn() {
+		lastColumn = fmt.Sprintf("%*s %*s", sizeWidth, "size", dateW, lastColumn)
+	}
 	header := fmt.Sprintf("    %-*s %-*s %*s",
 		fromW, "from",
 		subjectW, "subject",
-		dateWidth, "date")
-	if len(header) > v.contentWidth {
-		header = header[:v.contentWidth]
-	}
+		dateW, lastColumn)
+	header = truncate(header, v.contentWidth)
 	b.WriteString(threadHeaderStyle.MaxWidth(v.contentWidth).Render(header))
 	b.WriteString("\n")
 
@@ -208,7 +282,7 @@ func (v *ThreadListView) View() string {
 		thread := v.threads[i]
 		isSelected := i == v.selected
 
-		b.WriteString(v.renderThreadRow(thread, isSelected, fromW, subjectW))
+		b.WriteString(v.renderThreadRow(thread, isSelected, fromW, subjectW, dateW))
 		if i < endIdx-1 {
 			b.WriteString("\n")
 		}
@@ -233,7 +307,7 @@ func (v *ThreadListView) View() string {
 	return content
 }
 
-func (v *ThreadListView) renderThreadRow(thread Thread, selected bool, fromWidth, subjectWidth int) string {
+func (v *ThreadListView) renderThreadRow(thread Thread, selected bool, fromWidth, subjectWidth, dateW int) string {
 	// Build plain text first, then style
 
 	// Unread indicator (1 char)
@@ -241,67 +315,151 @@ func (v *ThreadListView) renderThreadRow(thread Thread, selected bool, fromWidth
 	if thread.UnreadCnt > 0 {
 		unreadDot = "●"
 	}
+	if thread.New {
+		unreadDot = "✦"
+	}
+	if thread.Failed {
+		unreadDot = "✗"
+	}
 
-	// Thread/email indicator (countWidth chars)
-	countStr := fmt.Sprintf("%*s", countWidth, "")
+	// Thread/email indicator (countWidth chars), led by a pin marker
+	pin := ""
+	if thread.Pinned {
+		pin = "⊤"
+	}
+	pinWidth := len([]rune(pin))
+	countStr := fmt.Sprintf("%s%*s", pin, countWidth-pinWidth, "")
 	if thread.EmailCnt > 1 {
 		if thread.Expanded {
-			countStr = fmt.Sprintf("▼%-*d", countWidth-1, thread.EmailCnt)
+			countStr = fmt.Sprintf("%s▼%-*d", pin, countWidth-1-pinWidth, thread.EmailCnt)
 		} else {
-			countStr = fmt.Sprintf("▶%-*d", countWidth-1, thread.EmailCnt)
+			countStr = fmt.Sprintf("%s▶%-*d", pin, countWidth-1-pinWidth, thread.EmailCnt)
 		}
 	}
 
 	// From - truncate and pad
-	from := thread.From
-	if len(from) > fromWidth {
-		from = from[:fromWidth-1] + "…"
-	}
-	from = fmt.Sprintf("%-*s", fromWidth, from)
+	from := padRight(truncate(thread.From, fromWidth), fromWidth)
 
-	// Subject - truncate and pad
+	// Subject - truncate and pad, led by a checkbox for follow-ups and a
+	// marker for attachments
 	subject := thread.Subject
 	if subject == "" {
 		subject = "(no subject)"
 	}
-	if len(subject) > subjectWidth {
-		subject = subject[:subjectWidth-1] + "…"
+	todoMark := ""
+	if v.columns.Flags {
+		if thread.Priority {
+			todoMark = "! "
+		}
+		if thread.Todo {
+			todoMark += "☐ "
+		}
+		if thread.HasAttachment {
+			todoMark += "◈ "
+		}
 	}
-	subject = fmt.Sprintf("%-*s", subjectWidth, subject)
+	subjectSpace := subjectWidth - len([]rune(todoMark))
+	subject = todoMark + padRight(truncate(subject, subjectSpace), subjectSpace)
 
-	// Date - right align (use constant dateWidth)
-	date := fmt.Sprintf("%*s", dateWidth, thread.Date)
+	// Date - right align, or size when sorting by it
+	date := fmt.Sprintf("%*s", dateW, thread.Date)
+	if v.showSize {
+		date = fmt.Sprintf("%*s", dateW, FormatSize(thread.Size))
+	}
+	if v.sizeColumn() {
+		date = fmt.Sprintf("%*s %s", sizeWidth, FormatSize(thread.Size), date)
+	}
 
 	// Build the row as plain text
 	row := fmt.Sprintf("%s%s%s %s %s", unreadDot, countStr, from, subject, date)
 
-	// Truncate to contentWidth to prevent any overflow
-	if len(row) > v.contentWidth {
-		row = row[:v.contentWidth]
+	// Truncate to contentWidth to prevent any overflow. The row is still
+	// plain text here; styling comes after, so no escape sequence is cut.
+	row = truncate(row, v.contentWidth)
+
+	// Marked rows swap the unread dot for a check
+	if thread.Marked {
+		row = "✓" + row[len(unreadDot):]
 	}
 
 	// Now apply styling to the complete row
+	if thread.Leaving {
+		return threadRowLeavingStyle.MaxWidth(v.contentWidth).Render(row)
+	}
+
 	if selected {
 		return threadRowSelectedStyle.MaxWidth(v.contentWidth).Render(row)
 	}
 
+	if thread.Marked {
+		return threadRowMarkedStyle.MaxWidth(v.contentWidth).Render(row)
+	}
+
 	// For unselected, style individual parts
 	var styled strings.Builder
-	if thread.UnreadCnt > 0 {
+	if thread.Failed {
+		styled.WriteString(threadFailedStyle.Render(unreadDot))
+		styled.WriteString(threadCountStyle.Render(countStr))
+		styled.WriteString(v.renderHighlighted(from, threadFromStyle))
+		styled.WriteString(" ")
+		styled.WriteString(v.renderHighlighted(subject, threadSubjectStyle))
+	} else if thread.New {
+		styled.WriteString(threadNewStyle.Render(unreadDot))
+		styled.WriteString(threadCountStyle.Render(countStr))
+		styled.WriteString(v.renderHighlighted(from, threadFromUnreadStyle))
+		styled.WriteString(" ")
+		styled.WriteString(v.renderHighlighted(subject, threadSubjectUnreadStyle))
+	} else if thread.UnreadCnt > 0 {
 		styled.WriteString(threadUnreadDotStyle.Render(unreadDot))
 		styled.WriteString(threadCountStyle.Render(countStr))
-		styled.WriteString(threadFromUnreadStyle.Render(from))
+		styled.WriteString(v.renderHighlighted(from, threadFromUnreadStyle))
 		styled.WriteString(" ")
-		styled.WriteString(threadSubjectUnreadStyle.Render(subject))
+		styled.WriteString(v.renderHighlighted(subject, threadSubjectUnreadStyle))
 	} else {
 		styled.WriteString(threadDateStyle.Render(unreadDot))
 		styled.WriteString(threadDateStyle.Render(countStr))
-		styled.WriteString(threadFromStyle.Render(from))
+		styled.WriteString(v.renderHighlighted(from, threadFromStyle))
 		styled.WriteString(" ")
-		styled.WriteString(threadSubjectStyle.Render(subject))
+		styled.WriteString(v.renderHighlighted(subject, threadSubjectStyle))
 	}
 	styled.WriteString(" ")
 	styled.WriteString(threadDateStyle.Render(date))
 
 	return threadRowStyle.MaxWidth(v.contentWidth).Render(styled.String())
 }
+
+// renderHighlighted renders text in style, marking the first
+// case-insensitive occurrence of the filter text
+func (v *ThreadListView) renderHighlighted(text string, style lipgloss.Style) string {
+	if v.highlight == "" {
+		return style.Render(text)
+	}
+	idx, end := indexFold(text, v.highlight)
+	if idx < 0 {
+		return style.Render(text)
+	}
+	return style.Render(text[:idx]) +
+		threadMatchStyle.Render(text[idx:end]) +
+		style.Render(text[end:])
+}
+
+// indexFold returns the byte range in s of the first case-insensitive
+// occurrence of substr, or -1, -1. Characters are compared one by one
+// in s itself, since lowercasing can change their length.
+func indexFold(s, substr string) (int, int) {
+	for i := range s {
+		j := i
+		for _, r := range substr {
+			c, size := utf8.DecodeRuneInString(s[j:])
+			if size == 0 || !strings.EqualFold(string(c), string(r)) {
+				j = -1
+				break
+			}
+			j += size
+		}
+		if j >= 0 {
+			return i, j
+		}
+	}
+	return -1, -1
+}
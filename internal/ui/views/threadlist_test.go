@@ -0,0 +1,37 @@
+package views
+
+import "testing"
+
+func TestIndexFold(t *testing.T) {
+	tests := []struct {
+		s, substr string
+		want      string
+	}{
+		{"Weekly Report", "report", "Report"},
+		{"STRASSE und Straße", "straße", "Straße"},
+		// "İ" lowercases to three bytes; the match must still line up
+		{"İstanbul trip", "trip", "trip"},
+		{"Ünïcode ÜBER", "über", "ÜBER"},
+		{"K is for Kelvin", "kelvin", "Kelvin"},
+		{"nothing here", "absent", ""},
+	}
+	for _, tt := range tests {
+		start, end := indexFold(tt.s, tt.substr)
+		got := ""
+		if start >= 0 {
+			got = tt.s[start:end]
+		}
+		if got != tt.want {
+			t.Errorf("indexFold(%q, %q) matched %q, want %q", tt.s, tt.substr, got, tt.want)
+		}
+	}
+}
+
+func TestRenderHighlightedKeepsText(t *testing.T) {
+	v := NewThreadListView(80, 20, 0)
+	v.SetHighlight("trip")
+	got := ansiRe.ReplaceAllString(v.renderHighlighted("İstanbul Trip plans", threadSubjectStyle), "")
+	if got != "İstanbul Trip plans" {
+		t.Errorf("highlighted text = %q", got)
+	}
+}
@@ -0,0 +1,26 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// truncate shortens s to at most width terminal cells, ending it with "…"
+// when anything was cut. Widths are measured in cells, so wide characters
+// count double, multi-byte characters are never split, and escape
+// sequences in styled text are kept whole.
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return ansi.Truncate(s, width, "…")
+}
+
+// padRight pads s with spaces to width terminal cells
+func padRight(s string, width int) string {
+	if w := ansi.StringWidth(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/jmap"
+	"github.com/the9x/anneal/internal/storage"
+)
+
+// logout signs an account out of this machine: its cached mail and
+// attachments are deleted, its token is removed from the token store and
+// the account is dropped from the config. The next run sets up an account
+// from scratch.
+func logout(cfg *config.Config, email string) error {
+	account := cfg.DefaultAccount()
+	if email != "" {
+		account = cfg.FindAccount(email)
+	}
+	if account == nil {
+		return fmt.Errorf("no account %s configured", email)
+	}
+	email = account.Email
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Printf("Log out of %s and delete its cached mail and attachments? [y/N] ", email)
+	answer, _ := reader.ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	if err := purgeCache(cfg, email); err != nil {
+		return err
+	}
+
+	if err := cfg.DeleteToken(email); err != nil && !errors.Is(err, config.ErrTokenNotFound) {
+		return fmt.Errorf("failed to delete token: %w", err)
+	}
+
+	cfg.RemoveAccount(email)
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("Logged out of %s.\n", email)
+	return nil
+}
+
+// purgeCache deletes an account's cached mail and the attachment cache.
+// Cached rows are scoped by the server's account ID, which needs the token
+// to look up; when the server can't be reached the whole cache is cleared
+// if this is the only account. Otherwise it fails, so the logout stops
+// while the token is still there to try again with.
+func purgeCache(cfg *config.Config, email string) error {
+	if blobs, err := storage.NewBlobCache(cfg.CacheDir, 0); err == nil {
+		if err := blobs.Clear(); err != nil {
+			return err
+		}
+	}
+
+	store, err := storage.New(cfg.CacheDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: local cache unavailable: %v\n", err)
+		return nil
+	}
+	defer store.Close()
+
+	var accountID string
+	if token, err := cfg.GetToken(email); err == nil {
+		if client, err := jmap.New(email, token); err == nil {
+			accountID = client.AccountID()
+		}
+	}
+
+	switch {
+	case accountID != "":
+		return store.DeleteAccount(accountID)
+	case len(cfg.Accounts) == 1:
+		return store.ClearCache()
+	default:
+		return fmt.Errorf("couldn't reach the server to find the mail cached for %s; still logged in, "+
+			"run again when online, or delete cache.db in the data directory to clear every account", email)
+	}
+}
@@ -2,24 +2,88 @@ package main
 
 import (
 	"bufio"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/the9x/anneal/internal/config"
+	"github.com/the9x/anneal/internal/i18n"
 	"github.com/the9x/anneal/internal/jmap"
+	"github.com/the9x/anneal/internal/models"
 	"github.com/the9x/anneal/internal/storage"
 	"github.com/the9x/anneal/internal/ui"
+	"github.com/the9x/anneal/internal/ui/theme"
 )
 
 func main() {
-	// Load configuration
+	debug := flag.Bool("debug", os.Getenv("ANNEAL_DEBUG") == "1", "log JMAP requests to the data directory")
+	noColor := flag.Bool("no-color", false, "disable colors (also set by NO_COLOR)")
+	noCache := flag.Bool("no-cache", false, "don't cache mail or attachments on disk")
+	flag.Parse()
+
+	if *debug {
+		if closeLog := enableDebugLog(); closeLog != nil {
+			defer closeLog()
+		}
+	}
+
+	// Load configuration, carrying on with what could be salvaged if the
+	// file has errors
 	cfg, err := config.Load()
-	if err != nil {
+	var recovered *config.RecoveryError
+	if errors.As(err, &recovered) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
+	i18n.SetLanguage(cfg.Language)
+	if *noCache {
+		cfg.NoCacheFlag = true
+	}
+
+	// anneal edit-account [email] changes an account's signature and Reply-To
+	if flag.Arg(0) == "edit-account" {
+		if err := editAccount(cfg, flag.Arg(1)); err != nil {
+			fmt.Fprintf(os.Stderr, "Edit failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// anneal doctor checks tokens, sign-in and the cache without the TUI
+	if flag.Arg(0) == "doctor" {
+		if err := doctor(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// anneal logout [email] deletes an account's token and cached mail
+	if flag.Arg(0) == "logout" {
+		if err := logout(cfg, flag.Arg(1)); err != nil {
+			fmt.Fprintf(os.Stderr, "Logout failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// anneal contacts export <file> writes cached contacts as vCard or CSV
+	if flag.Arg(0) == "contacts" {
+		if flag.NArg() != 3 || flag.Arg(1) != "export" {
+			fmt.Fprintf(os.Stderr, "Usage: anneal contacts export <file.vcf|file.csv>\n")
+			os.Exit(2)
+		}
+		if err := exportContacts(cfg, flag.Arg(2)); err != nil {
+			fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Check if we have accounts configured
 	if len(cfg.Accounts) == 0 {
@@ -36,41 +100,173 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Get token from keyring
-	token, err := config.GetToken(account.Email)
+	client, ok := connectAccount(cfg, account)
+	if !ok {
+		os.Exit(1)
+	}
+
+	// anneal export <folder> <file> writes a folder to an mbox file
+	if flag.Arg(0) == "export" {
+		if flag.NArg() != 3 {
+			fmt.Fprintf(os.Stderr, "Usage: anneal export <folder> <file>\n")
+			os.Exit(2)
+		}
+		if err := exportMailbox(client, flag.Arg(1), flag.Arg(2)); err != nil {
+			fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Create local storage (non-fatal if fails). Without it every view
+	// falls back to fetching from the server.
+	var store *storage.Store
+	if !cfg.CacheDisabled() {
+		store, err = storage.New(cfg.CacheDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: local cache unavailable: %v\n", err)
+			store = nil
+		}
+	}
+	defer func() {
+		if store != nil {
+			store.Close()
+		}
+	}()
+
+	theme.Setup(cfg.Theme, *noColor)
+
+	// Create and run the app, starting it again on another account when
+	// the user switches
+	for {
+		app := ui.NewApp(cfg, client, store)
+		if recovered != nil {
+			app.SetStartupWarning(recovered.Error())
+			recovered = nil
+		}
+		opts := []tea.ProgramOption{tea.WithAltScreen()}
+		if cfg.Mouse {
+			opts = append(opts, tea.WithMouseCellMotion())
+		}
+		p := tea.NewProgram(app, opts...)
+
+		if _, err := p.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		next := cfg.FindAccount(app.NextAccount())
+		if next == nil {
+			return
+		}
+		if client, ok = connectAccount(cfg, next); !ok {
+			os.Exit(1)
+		}
+	}
+}
+
+// connectAccount reads the account's token and connects to its server,
+// explaining on stderr what's missing when it can't
+func connectAccount(cfg *config.Config, account *models.Account) (*jmap.Client, bool) {
+	// Get token from the configured store
+	token, err := cfg.GetToken(account.Email)
+	if config.IsKeyringUnavailable(err) {
+		token, err = recoverToken(cfg, account.Email, err)
+	}
 	if err != nil {
+		if config.IsKeyringUnavailable(err) {
+			fmt.Fprintf(os.Stderr, "Could not read the API token for %s: %v\n", account.Email, err)
+			fmt.Fprintf(os.Stderr, "If this machine has no secret service, add `token_store: file` to your config\n")
+			fmt.Fprintf(os.Stderr, "and run again to store the token in a 0600 file instead.\n")
+			return nil, false
+		}
 		fmt.Fprintf(os.Stderr, "No API token found for %s\n", account.Email)
 		fmt.Fprintf(os.Stderr, "Please set your token: tuimail set-token %s <token>\n", account.Email)
-		os.Exit(1)
+		return nil, false
 	}
 
 	// Create JMAP client
 	client, err := jmap.New(account.Email, token)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to connect: %v\n", err)
-		os.Exit(1)
+		return nil, false
+	}
+	client.SetReplyTo(account.ReplyTo)
+	identityReplyTo := make(map[string]string)
+	for addr, settings := range account.Identities {
+		if settings.ReplyTo != "" {
+			identityReplyTo[addr] = settings.ReplyTo
+		}
 	}
+	client.SetIdentityReplyTo(identityReplyTo)
+	client.SetSender(account.Sender)
+	client.SetFetchPriority(cfg.PrioritySort)
+	return client, true
+}
 
-	// Create local storage (non-fatal if fails)
-	store, err := storage.New()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: local cache unavailable: %v\n", err)
-		store = nil
+// recoverToken asks for the account's token when the keyring can't be read
+// at startup, and stores it the way setup does, offering the token file
+func recoverToken(cfg *config.Config, email string, keyringErr error) (string, error) {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Printf("Could not read the API token for %s: %v\n", email, keyringErr)
+	fmt.Print("API Token (empty to quit): ")
+	token, _ := reader.ReadString('\n')
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", keyringErr
 	}
-	defer func() {
-		if store != nil {
-			store.Close()
-		}
-	}()
 
-	// Create and run the app
-	app := ui.NewApp(cfg, client, store)
-	p := tea.NewProgram(app, tea.WithAltScreen())
+	if err := storeToken(cfg, reader, email, token); err != nil {
+		return "", err
+	}
+	if err := cfg.Save(); err != nil {
+		return "", fmt.Errorf("failed to save config: %w", err)
+	}
+	return token, nil
+}
 
-	if _, err := p.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+// storeToken saves the token to the configured store, offering a file if
+// the keyring is broken and switching token_store over when accepted
+func storeToken(cfg *config.Config, reader *bufio.Reader, email, token string) error {
+	err := cfg.SetToken(email, token)
+	if err == nil {
+		return nil
+	}
+	if !config.IsKeyringUnavailable(err) {
+		return fmt.Errorf("failed to save token: %w", err)
 	}
+
+	tokenPath, _ := config.TokenFilePath()
+	fmt.Println()
+	fmt.Printf("The system keyring is unavailable (%v).\n", err)
+	fmt.Printf("Store the token unencrypted in %s (readable only by you)? [y/N] ", tokenPath)
+	answer, _ := reader.ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+
+	cfg.TokenStore = config.TokenStoreFile
+	if err := cfg.SetToken(email, token); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+	return nil
+}
+
+// enableDebugLog starts logging JMAP requests to debug.log in the data dir
+func enableDebugLog() func() {
+	dataDir, err := storage.DataDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: debug log unavailable: %v\n", err)
+		return nil
+	}
+	f, err := jmap.OpenDebugLog(dataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: debug log unavailable: %v\n", err)
+		return nil
+	}
+	jmap.SetDebugLog(f)
+	return func() { f.Close() }
 }
 
 func setupFirstAccount(cfg *config.Config) error {
@@ -110,14 +306,25 @@ func setupFirstAccount(cfg *config.Config) error {
 		return fmt.Errorf("API token is required")
 	}
 
+	// Optional persona settings
+	fmt.Println()
+	fmt.Print("Reply-To address (optional): ")
+	replyTo, _ := reader.ReadString('\n')
+	replyTo = strings.TrimSpace(replyTo)
+
+	fmt.Println("Signature (optional, finish with an empty line):")
+	signature := readSignature(reader)
+
 	// Add account
 	if err := cfg.AddAccount(name, email, true); err != nil {
 		return err
 	}
+	account := cfg.FindAccount(email)
+	account.ReplyTo = replyTo
+	account.Signature = signature
 
-	// Save token to keyring
-	if err := config.SetToken(email, token); err != nil {
-		return fmt.Errorf("failed to save token: %w", err)
+	if err := storeToken(cfg, reader, email, token); err != nil {
+		return err
 	}
 
 	// Save config
@@ -131,3 +338,69 @@ func setupFirstAccount(cfg *config.Config) error {
 
 	return nil
 }
+
+// editAccount prompts for an account's signature and Reply-To. Pressing
+// enter keeps the current value and "-" clears it.
+func editAccount(cfg *config.Config, email string) error {
+	account := cfg.DefaultAccount()
+	if email != "" {
+		account = cfg.FindAccount(email)
+	}
+	if account == nil {
+		return fmt.Errorf("no account %s configured", email)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Printf("Editing %s (%s)\n", account.Name, account.Email)
+	fmt.Println("Press enter to keep a value, or enter - to clear it.")
+	fmt.Println()
+
+	fmt.Printf("Reply-To [%s]: ", account.ReplyTo)
+	replyTo, _ := reader.ReadString('\n')
+	switch replyTo = strings.TrimSpace(replyTo); replyTo {
+	case "":
+	case "-":
+		account.ReplyTo = ""
+	default:
+		account.ReplyTo = replyTo
+	}
+
+	if account.Signature != "" {
+		fmt.Println("Current signature:")
+		fmt.Println(account.Signature)
+	}
+	fmt.Println("New signature (finish with an empty line):")
+	switch signature := readSignature(reader); signature {
+	case "":
+	case "-":
+		account.Signature = ""
+	default:
+		account.Signature = signature
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Println("Account updated.")
+	return nil
+}
+
+// readSignature reads lines until an empty one, so signatures can span
+// several lines
+func readSignature(reader *bufio.Reader) string {
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		lines = append(lines, line)
+		if err != nil {
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/the9x/anneal/internal/jmap"
+)
+
+// mboxPageSize is how many messages are listed per request while exporting
+const mboxPageSize = 100
+
+// exportMailbox writes a folder, found by name or role, to an mbox file
+func exportMailbox(client *jmap.Client, folder, destPath string) error {
+	mailboxes, err := client.GetMailboxes()
+	if err != nil {
+		return err
+	}
+
+	var mailboxID, name string
+	for _, mb := range mailboxes {
+		if strings.EqualFold(mb.Role, folder) || strings.EqualFold(mb.Name, folder) {
+			mailboxID, name = mb.ID, mb.Name
+			break
+		}
+	}
+	if mailboxID == "" {
+		return fmt.Errorf("no folder named %q", folder)
+	}
+
+	fmt.Fprintf(os.Stderr, "Exporting %s to %s\n", name, destPath)
+	return exportMailboxMbox(client, mailboxID, destPath)
+}
+
+// exportMailboxMbox downloads every message in a mailbox as sent and
+// writes them, oldest first, to an mboxrd file. Messages are streamed to
+// disk one at a time so large folders don't have to fit in memory. The
+// file only appears under destPath once the export is complete.
+func exportMailboxMbox(client *jmap.Client, mailboxID, destPath string) error {
+	tmp := destPath + ".part"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", destPath, err)
+	}
+	defer os.Remove(tmp) // no-op once renamed
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	written := 0
+	for position := 0; ; position += mboxPageSize {
+		page, total, err := client.GetRawEmails(mailboxID, position, mboxPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, raw := range page {
+			data, err := client.DownloadBlob(raw.BlobID, "message.eml")
+			if err != nil {
+				return fmt.Errorf("failed to download message %s: %w", raw.ID, err)
+			}
+			if err := writeMboxMessage(w, raw, data); err != nil {
+				return fmt.Errorf("failed to write %s: %w", destPath, err)
+			}
+			written++
+		}
+		fmt.Fprintf(os.Stderr, "\r%d/%d messages", written, total)
+
+		if len(page) == 0 || position+len(page) >= total {
+			break
+		}
+	}
+	fmt.Fprintln(os.Stderr)
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", destPath, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", destPath, err)
+	}
+	return os.Rename(tmp, destPath)
+}
+
+// writeMboxMessage appends one message in mboxrd format: a "From " line
+// with the sender and date, the message with line endings normalized and
+// any line starting with ">*From " quoted once more, then a blank line.
+func writeMboxMessage(w *bufio.Writer, raw jmap.RawEmail, data []byte) error {
+	sender := raw.From
+	if sender == "" {
+		sender = "MAILER-DAEMON"
+	}
+	fmt.Fprintf(w, "From %s %s\n", sender, raw.ReceivedAt.UTC().Format("Mon Jan _2 15:04:05 2006"))
+
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	data = bytes.TrimRight(data, "\n")
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if bytes.HasPrefix(bytes.TrimLeft(line, ">"), []byte("From ")) {
+			w.WriteByte('>')
+		}
+		w.Write(line)
+		w.WriteByte('\n')
+	}
+	_, err := w.WriteString("\n")
+	return err
+}